@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stevekineeve88/nimydb-engine/pkg/engine"
+	"github.com/stevekineeve88/nimydb-engine/pkg/server/http"
+	"github.com/stevekineeve88/nimydb-engine/pkg/server/tcp"
+)
+
+func main() {
+	address := flag.String("address", "127.0.0.1:7070", "address to listen on for line-delimited JSON queries")
+	httpAddress := flag.String("http", "", "address to listen on for the HTTP API, disabled when empty")
+	dataLocation := flag.String("data", "./data", "data directory")
+	dataCaching := flag.Bool("cache", true, "cache pages and indexes in memory")
+	flag.Parse()
+
+	engineObj, err := engine.Start(engine.Config{
+		DataLocation: *dataLocation,
+		RootPassword: os.Getenv("NIMYDB_ROOT_PASSWORD"),
+		DataCaching:  *dataCaching,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := tcpServer.CreateServer(engineObj.QueryManager)
+	var httpServerObj *http.Server
+	if *httpAddress != "" {
+		httpServerObj = &http.Server{
+			Addr:    *httpAddress,
+			Handler: httpServer.CreateServer(engineObj.QueryManager),
+		}
+		go func() {
+			log.Printf("nimydb HTTP API listening on %s", *httpAddress)
+			if err := httpServerObj.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		if httpServerObj != nil {
+			_ = httpServerObj.Close()
+		}
+		_ = server.Close()
+	}()
+
+	log.Printf("nimydb listening on %s", *address)
+	if err := server.ListenAndServe(*address); err != nil {
+		log.Fatal(err)
+	}
+}
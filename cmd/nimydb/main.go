@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stevekineeve88/nimydb-engine/pkg/engine"
+	"github.com/stevekineeve88/nimydb-engine/pkg/shell"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replay(os.Args[2:])
+		return
+	}
+	dataLocation := flag.String("data", "./data", "data directory")
+	dataCaching := flag.Bool("cache", true, "cache pages and indexes in memory")
+	jsonOutput := flag.Bool("json", false, "print every result as a JSON line")
+	user := flag.String("user", "root", "user to log in as")
+	flag.Parse()
+
+	rootPassword := os.Getenv("NIMYDB_ROOT_PASSWORD")
+	engineObj, err := engine.Start(engine.Config{
+		DataLocation: *dataLocation,
+		RootPassword: rootPassword,
+		DataCaching:  *dataCaching,
+	})
+	if err != nil {
+		fail(err)
+	}
+
+	password := os.Getenv("NIMYDB_PASSWORD")
+	if password == "" && *user == "root" {
+		password = rootPassword
+	}
+	connectionUser, err := engineObj.UserManager.Authenticate(*user, password)
+	if err != nil {
+		fail(err)
+	}
+
+	shellObj := shell.CreateShell(engineObj.QueryManager, connectionUser, os.Stdout, *jsonOutput)
+	interactive := isTerminal(os.Stdin)
+	if interactive {
+		if home, err := os.UserHomeDir(); err == nil {
+			_ = shellObj.LoadHistory(filepath.Join(home, ".nimydb_history"))
+		}
+	}
+	if err := shellObj.Run(os.Stdin, interactive && !*jsonOutput); err != nil {
+		fail(err)
+	}
+}
+
+func replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	dataLocation := flags.String("data", "./data", "data directory to replay logs from")
+	target := flags.String("target", "", "data directory to replay logs into")
+	from := flags.Int("from", 1, "first log version to replay")
+	to := flags.Int("to", 0, "last log version to replay, 0 for the latest")
+	dataCaching := flags.Bool("cache", true, "cache pages and indexes in memory")
+	_ = flags.Parse(args)
+
+	if *target == "" {
+		fail(fmt.Errorf("replay target is required"))
+	}
+	rootPassword := os.Getenv("NIMYDB_ROOT_PASSWORD")
+	replayed, err := engine.Replay(engine.Config{
+		DataLocation: *dataLocation,
+		RootPassword: rootPassword,
+		DataCaching:  *dataCaching,
+	}, engine.Config{
+		DataLocation: *target,
+		RootPassword: rootPassword,
+		DataCaching:  *dataCaching,
+	}, *from, *to)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("replayed %d queries into %s\n", replayed, *target)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func fail(err error) {
+	_, _ = fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,432 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"slices"
+	"strings"
+)
+
+type QueryBuilder struct {
+	query queryModels.Query
+	err   error
+}
+
+func newQueryBuilder(action string, on string, name string) *QueryBuilder {
+	return &QueryBuilder{
+		query: queryModels.Query{
+			Action: action,
+			On:     on,
+			Name:   name,
+		},
+	}
+}
+
+func Login(user string, password string) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionCreate, queryConstants.OnConnection, "")
+	queryBuilder.query.With.UserConnection = &systemModels.UserConnection{User: user, Password: password}
+	return queryBuilder
+}
+
+func CreateDB(db string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionCreate, queryConstants.OnDB, db).requireName()
+}
+
+func GetDBs() *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnDBs, "")
+}
+
+func DeleteDB(db string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionDelete, queryConstants.OnDB, db).requireName()
+}
+
+func CreateBlob(name string) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionCreate, queryConstants.OnBlob, name).requireName()
+	queryBuilder.query.With.Format = map[string]string{}
+	return queryBuilder
+}
+
+func GetBlobs(db string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnBlobs, db).requireName()
+}
+
+func GetPartitions(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnPartitions, name).requireName()
+}
+
+func GetAlter(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnAlter, name).requireName()
+}
+
+func DeleteBlob(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionDelete, queryConstants.OnBlob, name).requireName()
+}
+
+func TruncateBlob(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionTruncate, queryConstants.OnBlob, name).requireName()
+}
+
+func DropPartition(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionDelete, queryConstants.OnPartition, name).requireName()
+}
+
+func AddKey(name string, key string, keyType string, optional bool, defaultValue any) *QueryBuilder {
+	return alterBlob(name, diskModels.Alter{
+		Op:       memoryConstants.AlterAdd,
+		Key:      key,
+		KeyType:  keyType,
+		Optional: optional,
+		Default:  defaultValue,
+	})
+}
+
+func DropKey(name string, key string) *QueryBuilder {
+	return alterBlob(name, diskModels.Alter{Op: memoryConstants.AlterDrop, Key: key})
+}
+
+func RenameKey(name string, key string, newKey string) *QueryBuilder {
+	return alterBlob(name, diskModels.Alter{Op: memoryConstants.AlterRename, Key: key, NewKey: newKey})
+}
+
+func ChangeKeyType(name string, key string, keyType string) *QueryBuilder {
+	return alterBlob(name, diskModels.Alter{Op: memoryConstants.AlterType, Key: key, KeyType: keyType})
+}
+
+func MakeKeyUnique(name string, key string) *QueryBuilder {
+	return alterBlob(name, diskModels.Alter{Op: memoryConstants.AlterUnique, Key: key})
+}
+
+func alterBlob(name string, alter diskModels.Alter) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionUpdate, queryConstants.OnBlob, name).requireName()
+	queryBuilder.query.With.Alter = &alter
+	return queryBuilder
+}
+
+func CreateIndex(name string, key string, indexType string) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionCreate, queryConstants.OnIndex, name).requireName()
+	queryBuilder.query.With.Key = key
+	queryBuilder.query.With.IndexType = indexType
+	return queryBuilder
+}
+
+func DeleteIndex(name string, key string) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionDelete, queryConstants.OnIndex, name).requireName()
+	queryBuilder.query.With.Key = key
+	return queryBuilder
+}
+
+func Get(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnData, name).requireName()
+}
+
+func Insert(name string, records ...any) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionCreate, queryConstants.OnData, name).requireName()
+	queryBuilder.query.With.Records = []diskModels.PageRecord{}
+	for _, record := range records {
+		pageRecords, err := EncodeRecords(record)
+		if err != nil {
+			return queryBuilder.fail(err)
+		}
+		queryBuilder.query.With.Records = append(queryBuilder.query.With.Records, pageRecords...)
+	}
+	return queryBuilder
+}
+
+func Update(name string) *QueryBuilder {
+	queryBuilder := newQueryBuilder(queryConstants.ActionUpdate, queryConstants.OnData, name).requireName()
+	queryBuilder.query.With.UpdateRecord = diskModels.PageRecord{}
+	return queryBuilder
+}
+
+func Delete(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionDelete, queryConstants.OnData, name).requireName()
+}
+
+func Aggregate(name string) *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnAggregate, name).requireName()
+}
+
+func GetLogs() *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnLogs, "")
+}
+
+func GetUsers() *QueryBuilder {
+	return newQueryBuilder(queryConstants.ActionGet, queryConstants.OnUsers, "")
+}
+
+func Cond(key string, op string, value any) memoryModels.FilterItem {
+	return memoryModels.FilterItem{Key: key, Op: strings.ToUpper(op), Value: value}
+}
+
+func And(filterItems ...memoryModels.FilterItem) memoryModels.FilterItem {
+	return memoryModels.FilterItem{Op: memoryConstants.FilterAnd, Items: filterItems}
+}
+
+func Or(filterItems ...memoryModels.FilterItem) memoryModels.FilterItem {
+	return memoryModels.FilterItem{Op: memoryConstants.FilterOr, Items: filterItems}
+}
+
+func Not(filterItem memoryModels.FilterItem) memoryModels.FilterItem {
+	return memoryModels.FilterItem{Op: memoryConstants.FilterNot, Items: []memoryModels.FilterItem{filterItem}}
+}
+
+func (qb *QueryBuilder) Key(key string, keyType string) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("key %s can only be set when creating a blob", key))
+	}
+	qb.query.With.Format[key] = keyType
+	return qb
+}
+
+func (qb *QueryBuilder) Partition(keys ...string) *QueryBuilder {
+	qb.query.With.Partition = append(qb.query.With.Partition, keys...)
+	return qb
+}
+
+func (qb *QueryBuilder) RangePartition(key string, bounds ...float64) *QueryBuilder {
+	return qb.bucketPartition(key, diskModels.PartitionBucket{Type: memoryConstants.PartitionRange, Bounds: bounds})
+}
+
+func (qb *QueryBuilder) TimePartition(key string, period string) *QueryBuilder {
+	return qb.bucketPartition(key, diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: strings.ToLower(period)})
+}
+
+func (qb *QueryBuilder) bucketPartition(key string, bucket diskModels.PartitionBucket) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("%s partition on key %s can only be set when creating a blob", bucket.Type, key))
+	}
+	if qb.query.With.PartitionBuckets == nil {
+		qb.query.With.PartitionBuckets = map[string]diskModels.PartitionBucket{}
+	}
+	qb.query.With.PartitionBuckets[key] = bucket
+	qb.query.With.Partition = append(qb.query.With.Partition, key)
+	return qb
+}
+
+func (qb *QueryBuilder) Unique(keys ...string) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("unique keys can only be set when creating a blob"))
+	}
+	qb.query.With.Unique = append(qb.query.With.Unique, keys)
+	return qb
+}
+
+func (qb *QueryBuilder) Optional(keys ...string) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("optional keys can only be set when creating a blob"))
+	}
+	qb.query.With.Optional = append(qb.query.With.Optional, keys...)
+	return qb
+}
+
+func (qb *QueryBuilder) Default(key string, value any) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("default on key %s can only be set when creating a blob", key))
+	}
+	if qb.query.With.Default == nil {
+		qb.query.With.Default = map[string]any{}
+	}
+	qb.query.With.Default[key] = value
+	return qb
+}
+
+func (qb *QueryBuilder) Scale(key string, scale int) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("scale on key %s can only be set when creating a blob", key))
+	}
+	if qb.query.With.Scale == nil {
+		qb.query.With.Scale = map[string]int{}
+	}
+	qb.query.With.Scale[key] = scale
+	return qb
+}
+
+func (qb *QueryBuilder) Values(key string, values ...string) *QueryBuilder {
+	if qb.query.With.Format == nil {
+		return qb.fail(fmt.Errorf("values on key %s can only be set when creating a blob", key))
+	}
+	if qb.query.With.Values == nil {
+		qb.query.With.Values = map[string][]string{}
+	}
+	qb.query.With.Values[key] = values
+	return qb
+}
+
+func (qb *QueryBuilder) ID(id string) *QueryBuilder {
+	qb.query.With.Index = id
+	return qb
+}
+
+func (qb *QueryBuilder) Where(key string, op string, value any) *QueryBuilder {
+	return qb.WhereItems(Cond(key, op, value))
+}
+
+func (qb *QueryBuilder) WhereItems(filterItems ...memoryModels.FilterItem) *QueryBuilder {
+	for _, filterItem := range filterItems {
+		if err := validateFilterItem(filterItem); err != nil {
+			return qb.fail(err)
+		}
+	}
+	qb.query.With.Filter = append(qb.query.With.Filter, filterItems...)
+	return qb
+}
+
+func (qb *QueryBuilder) InPartition(key string, value any) *QueryBuilder {
+	if qb.query.With.SearchPartition == nil {
+		qb.query.With.SearchPartition = memoryModels.SearchPartition{}
+	}
+	qb.query.With.SearchPartition[key] = value
+	return qb
+}
+
+func (qb *QueryBuilder) SortBy(key string, direction string) *QueryBuilder {
+	direction = strings.ToLower(direction)
+	if direction != memoryConstants.SortAsc && direction != memoryConstants.SortDesc {
+		return qb.fail(fmt.Errorf("sort direction %s is not %s or %s", direction, memoryConstants.SortAsc, memoryConstants.SortDesc))
+	}
+	qb.query.With.Sort = append(qb.query.With.Sort, memoryModels.SortItem{Key: key, Direction: direction})
+	return qb
+}
+
+func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
+	if limit < 0 {
+		return qb.fail(fmt.Errorf("limit %d cannot be negative", limit))
+	}
+	qb.query.With.Limit = limit
+	return qb
+}
+
+func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
+	if offset < 0 {
+		return qb.fail(fmt.Errorf("offset %d cannot be negative", offset))
+	}
+	qb.query.With.Offset = offset
+	return qb
+}
+
+func (qb *QueryBuilder) Fields(keys ...string) *QueryBuilder {
+	qb.query.With.Fields = append(qb.query.With.Fields, keys...)
+	return qb
+}
+
+func (qb *QueryBuilder) Exclude(keys ...string) *QueryBuilder {
+	qb.query.With.ExcludeFields = append(qb.query.With.ExcludeFields, keys...)
+	return qb
+}
+
+func (qb *QueryBuilder) TimeZone(timeZone string) *QueryBuilder {
+	qb.query.With.TimeZone = timeZone
+	return qb
+}
+
+func (qb *QueryBuilder) Set(key string, value any) *QueryBuilder {
+	if qb.query.With.UpdateRecord == nil {
+		return qb.fail(fmt.Errorf("key %s can only be set when updating data", key))
+	}
+	qb.query.With.UpdateRecord[key] = value
+	return qb
+}
+
+func (qb *QueryBuilder) SetRecord(record any) *QueryBuilder {
+	pageRecords, err := EncodeRecords(record)
+	if err != nil {
+		return qb.fail(err)
+	}
+	if len(pageRecords) != 1 {
+		return qb.fail(errors.New("update record must be a single record"))
+	}
+	for key, value := range pageRecords[0] {
+		qb.Set(key, value)
+	}
+	return qb
+}
+
+func (qb *QueryBuilder) Count(as string) *QueryBuilder {
+	return qb.Aggregate(memoryConstants.AggregateCount, "", as)
+}
+
+func (qb *QueryBuilder) Sum(key string, as string) *QueryBuilder {
+	return qb.Aggregate(memoryConstants.AggregateSum, key, as)
+}
+
+func (qb *QueryBuilder) Avg(key string, as string) *QueryBuilder {
+	return qb.Aggregate(memoryConstants.AggregateAvg, key, as)
+}
+
+func (qb *QueryBuilder) Min(key string, as string) *QueryBuilder {
+	return qb.Aggregate(memoryConstants.AggregateMin, key, as)
+}
+
+func (qb *QueryBuilder) Max(key string, as string) *QueryBuilder {
+	return qb.Aggregate(memoryConstants.AggregateMax, key, as)
+}
+
+func (qb *QueryBuilder) Aggregate(op string, key string, as string) *QueryBuilder {
+	if qb.query.On != queryConstants.OnAggregate {
+		return qb.fail(fmt.Errorf("aggregate %s can only be used in an aggregate query", op))
+	}
+	op = strings.ToLower(op)
+	if !slices.Contains(memoryConstants.GetAggregateOps(), op) {
+		return qb.fail(fmt.Errorf("aggregate op %s is not supported", op))
+	}
+	qb.query.With.Aggregates = append(qb.query.With.Aggregates, memoryModels.AggregateItem{Op: op, Key: key, As: as})
+	return qb
+}
+
+func (qb *QueryBuilder) GroupBy(keys ...string) *QueryBuilder {
+	qb.query.With.GroupBy = append(qb.query.With.GroupBy, keys...)
+	return qb
+}
+
+func (qb *QueryBuilder) Build() (queryModels.Query, error) {
+	if qb.err != nil {
+		return queryModels.Query{}, qb.err
+	}
+	return qb.query, nil
+}
+
+func (qb *QueryBuilder) requireName() *QueryBuilder {
+	if qb.query.Name == "" {
+		return qb.fail(fmt.Errorf("name is required to %s %s", qb.query.Action, qb.query.On))
+	}
+	return qb
+}
+
+func (qb *QueryBuilder) fail(err error) *QueryBuilder {
+	if qb.err == nil {
+		qb.err = err
+	}
+	return qb
+}
+
+func validateFilterItem(filterItem memoryModels.FilterItem) error {
+	if filterItem.IsGroup() {
+		if len(filterItem.Items) == 0 {
+			return fmt.Errorf("filter group %s has no items", filterItem.Op)
+		}
+		if filterItem.Op == memoryConstants.FilterNot && len(filterItem.Items) != 1 {
+			return fmt.Errorf("filter group %s must have exactly one item", filterItem.Op)
+		}
+		for _, item := range filterItem.Items {
+			if err := validateFilterItem(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if filterItem.Key == "" {
+		return fmt.Errorf("filter op %s is missing a key", filterItem.Op)
+	}
+	for _, formatType := range memoryConstants.GetFormatTypes() {
+		if slices.Contains(memoryConstants.GetFilterOps(formatType), filterItem.Op) {
+			return nil
+		}
+	}
+	return fmt.Errorf("filter op %s on key %s is not supported", filterItem.Op, filterItem.Key)
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnit_QueryBuilder_BuildsGetQuery(t *testing.T) {
+	query, err := Get("shop.orders").
+		Where("qty", ">", 3).
+		WhereItems(Or(Cond("status", "=", "open"), Not(Cond("status", "like", "clos%")))).
+		InPartition("region", "eu").
+		SortBy("qty", "DESC").
+		Limit(10).
+		Offset(5).
+		Fields("qty", "status").
+		TimeZone("Europe/Paris").
+		Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, queryConstants.ActionGet, query.Action)
+	assert.Equal(t, queryConstants.OnData, query.On)
+	assert.Equal(t, "shop.orders", query.Name)
+	assert.Equal(t, []memoryModels.FilterItem{
+		{Key: "qty", Op: ">", Value: 3},
+		{Op: "OR", Items: []memoryModels.FilterItem{
+			{Key: "status", Op: "=", Value: "open"},
+			{Op: "NOT", Items: []memoryModels.FilterItem{{Key: "status", Op: "LIKE", Value: "clos%"}}},
+		}},
+	}, query.With.Filter)
+	assert.Equal(t, memoryModels.SearchPartition{"region": "eu"}, query.With.SearchPartition)
+	assert.Equal(t, memoryModels.SortItems{{Key: "qty", Direction: "desc"}}, query.With.Sort)
+	assert.Equal(t, 10, query.With.Limit)
+	assert.Equal(t, 5, query.With.Offset)
+	assert.Equal(t, []string{"qty", "status"}, query.With.Fields)
+	assert.Equal(t, "Europe/Paris", query.With.TimeZone)
+}
+
+func TestUnit_QueryBuilder_BuildsMutationsAndAggregates(t *testing.T) {
+	createBlob, _ := CreateBlob("shop.orders").Key("qty", "int").Key("status", "string").Partition("status").Unique("qty", "status").Optional("qty").Default("status", "new").Build()
+	update, _ := Update("shop.orders").ID("abc").Set("qty", 4).Build()
+	aggregate, _ := Aggregate("shop.orders").Count("").Sum("qty", "total").GroupBy("status").Build()
+	getBlobs, _ := GetBlobs("shop").Build()
+	getPartitions, _ := GetPartitions("shop.orders").Build()
+	dropPartition, _ := DropPartition("shop.orders").InPartition("status", "new").Build()
+	truncate, _ := TruncateBlob("shop.orders").Build()
+	createIndex, _ := CreateIndex("shop.orders", "status", "hash").Build()
+	deleteIndex, _ := DeleteIndex("shop.orders", "status").Build()
+
+	assert.Equal(t, map[string]string{"qty": "int", "status": "string"}, createBlob.With.Format)
+	assert.Equal(t, []string{"status"}, createBlob.With.Partition)
+	assert.Equal(t, [][]string{{"qty", "status"}}, createBlob.With.Unique)
+	assert.Equal(t, []string{"qty"}, createBlob.With.Optional)
+	assert.Equal(t, map[string]any{"status": "new"}, createBlob.With.Default)
+	assert.Equal(t, queryConstants.ActionUpdate, update.Action)
+	assert.Equal(t, "abc", update.With.Index)
+	assert.Equal(t, 4, update.With.UpdateRecord["qty"])
+	assert.Equal(t, queryConstants.OnAggregate, aggregate.On)
+	assert.Equal(t, []memoryModels.AggregateItem{{Op: "count"}, {Op: "sum", Key: "qty", As: "total"}}, aggregate.With.Aggregates)
+	assert.Equal(t, []string{"status"}, aggregate.With.GroupBy)
+	assert.Equal(t, queryConstants.OnBlobs, getBlobs.On)
+	assert.Equal(t, queryConstants.OnPartitions, getPartitions.On)
+	assert.Equal(t, "shop.orders", getPartitions.Name)
+	assert.Equal(t, queryConstants.OnPartition, dropPartition.On)
+	assert.Equal(t, memoryModels.SearchPartition{"status": "new"}, dropPartition.With.SearchPartition)
+	assert.Equal(t, queryConstants.ActionTruncate, truncate.Action)
+	assert.Equal(t, "shop", getBlobs.Name)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "status", IndexType: "hash"}}, createIndex)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "status"}}, deleteIndex)
+}
+
+func TestUnit_QueryBuilder_BuildsPartitionBuckets(t *testing.T) {
+	query, err := CreateBlob("shop.events").
+		Key("region", "string").
+		Key("qty", "int").
+		Key("created", "datetime").
+		Partition("region").
+		RangePartition("qty", 10, 100).
+		TimePartition("created", "Month").
+		Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"region", "qty", "created"}, query.With.Partition)
+	assert.Equal(t, map[string]diskModels.PartitionBucket{
+		"qty":     {Type: memoryConstants.PartitionRange, Bounds: []float64{10, 100}},
+		"created": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodMonth},
+	}, query.With.PartitionBuckets)
+}
+
+func TestUnit_QueryBuilder_ReturnsFirstError(t *testing.T) {
+	builders := map[string]*QueryBuilder{
+		"name is required to get data":                                    Get(""),
+		"filter op ~ on key qty is not supported":                         Get("shop.orders").Where("qty", "~", 1).Limit(-1),
+		"limit -1 cannot be negative":                                     Get("shop.orders").Limit(-1),
+		"range partition on key qty can only be set when creating a blob": Get("shop.orders").RangePartition("qty", 1),
+		"sort direction up is not asc or desc":                            Get("shop.orders").SortBy("qty", "up"),
+		"key qty can only be set when updating data":                      Get("shop.orders").Set("qty", 1),
+		"aggregate sum can only be used in an aggregate query":            Get("shop.orders").Sum("qty", ""),
+		"aggregate op median is not supported":                            Aggregate("shop.orders").Aggregate("median", "qty", ""),
+		"filter group OR has no items":                                    Get("shop.orders").WhereItems(Or()),
+	}
+
+	for expected, queryBuilder := range builders {
+		_, err := queryBuilder.Build()
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestUnit_QueryBuilder_BuildsAlterBlob(t *testing.T) {
+	addKey, _ := AddKey("shop.orders", "note", "string", true, nil).Build()
+	dropKey, _ := DropKey("shop.orders", "note").Build()
+	renameKey, _ := RenameKey("shop.orders", "qty", "quantity").Build()
+	changeKeyType, _ := ChangeKeyType("shop.orders", "qty", "float").Build()
+	makeKeyUnique, _ := MakeKeyUnique("shop.orders", "code").Build()
+	_, err := DropKey("", "note").Build()
+
+	assert.Equal(t, queryConstants.ActionUpdate, addKey.Action)
+	assert.Equal(t, queryConstants.OnBlob, addKey.On)
+	assert.Equal(t, &diskModels.Alter{Op: "add", Key: "note", KeyType: "string", Optional: true}, addKey.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "drop", Key: "note"}, dropKey.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "rename", Key: "qty", NewKey: "quantity"}, renameKey.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "type", Key: "qty", KeyType: "float"}, changeKeyType.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "unique", Key: "code"}, makeKeyUnique.With.Alter)
+	assert.NotNil(t, err)
+}
+
+func TestUnit_QueryBuilder_BuildsNestedKeysAndFilters(t *testing.T) {
+	createBlob, err := CreateBlob("shop.events").Key("address", "object").Key("address.city", "string").Key("tags", "array<string>").Build()
+	get, getErr := Get("shop.events").Where("tags", "any", []any{"a", "b"}).Where("address.city", "=", "Paris").Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"address": "object", "address.city": "string", "tags": "array<string>"}, createBlob.With.Format)
+	assert.Nil(t, getErr)
+	assert.Equal(t, []memoryModels.FilterItem{
+		{Key: "tags", Op: "ANY", Value: []any{"a", "b"}},
+		{Key: "address.city", Op: "=", Value: "Paris"},
+	}, get.With.Filter)
+}
+
+func TestUnit_QueryBuilder_BuildsScaleAndValues(t *testing.T) {
+	createBlob, err := CreateBlob("shop.orders").Key("amount", "decimal").Scale("amount", 2).Key("status", "enum").Values("status", "new", "paid").Build()
+	_, scaleErr := Get("shop.orders").Scale("amount", 2).Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"amount": 2}, createBlob.With.Scale)
+	assert.Equal(t, map[string][]string{"status": {"new", "paid"}}, createBlob.With.Values)
+	assert.NotNil(t, scaleErr)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+)
+
+type Result struct {
+	Records        []diskModels.PageRecord
+	ConnectionUser systemModels.User
+}
+
+func (r Result) Decode(target any) error {
+	return DecodeRecords(r.Records, target)
+}
+
+type Client struct {
+	executor Executor
+}
+
+func CreateClient(executor Executor) *Client {
+	return &Client{
+		executor: executor,
+	}
+}
+
+func (c *Client) Run(queryBuilder *QueryBuilder) (Result, error) {
+	query, err := queryBuilder.Build()
+	if err != nil {
+		return Result{}, err
+	}
+	queryResult, err := c.executor.Execute(query)
+	if err != nil {
+		return Result{}, err
+	}
+	if queryResult.ErrorMessage != "" {
+		return Result{}, errors.New(queryResult.ErrorMessage)
+	}
+	return Result{
+		Records:        queryResult.Records,
+		ConnectionUser: queryResult.ConnectionUser,
+	}, nil
+}
+
+func (c *Client) RunInto(queryBuilder *QueryBuilder, target any) error {
+	result, err := c.Run(queryBuilder)
+	if err != nil {
+		return err
+	}
+	return result.Decode(target)
+}
+
+func (c *Client) Close() error {
+	return c.executor.Close()
+}
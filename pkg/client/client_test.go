@@ -0,0 +1,78 @@
+package client
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/server/tcp"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+type testQueryManager struct {
+	queries []queryModels.Query
+	users   []systemModels.User
+}
+
+func (tqm *testQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if query.On == queryConstants.OnConnection {
+		if query.With.UserConnection.Password != "secret" {
+			return queryModels.QueryResult{ErrorMessage: "authentication failed"}
+		}
+		return queryModels.QueryResult{ConnectionUser: systemModels.User{User: query.With.UserConnection.User, Permission: "rw"}}
+	}
+	tqm.queries = append(tqm.queries, query)
+	tqm.users = append(tqm.users, user)
+	if query.Name == "shop.missing" {
+		return queryModels.QueryResult{ErrorMessage: "blob missing does not exist"}
+	}
+	return queryModels.QueryResult{Records: []diskModels.PageRecord{{"_id": "a1", "status": "open", "qty": 2}}}
+}
+
+func TestUnit_Run_ExecutesAgainstEmbeddedQueryManager(t *testing.T) {
+	queryManager := &testQueryManager{}
+	client := CreateClient(CreateEmbeddedExecutor(queryManager, systemModels.User{User: "root"}))
+	var orders []testOrder
+
+	err := client.RunInto(Get("shop.orders").Where("qty", ">", 1), &orders)
+	_, loginErr := client.Run(Login("bob", "secret"))
+	_, queryErr := client.Run(Get("shop.missing"))
+	_, buildErr := client.Run(Get("shop.orders").Limit(-1))
+
+	assert.Nil(t, err)
+	assert.Nil(t, loginErr)
+	assert.Equal(t, []testOrder{{ID: "a1", Status: "open", Qty: 2}}, orders)
+	assert.Equal(t, "root", queryManager.users[0].User)
+	assert.Equal(t, "bob", queryManager.users[1].User)
+	assert.EqualError(t, queryErr, "blob missing does not exist")
+	assert.EqualError(t, buildErr, "limit -1 cannot be negative")
+	assert.Equal(t, 2, len(queryManager.queries))
+}
+
+func TestUnit_Run_ExecutesAgainstRemoteServer(t *testing.T) {
+	queryManager := &testQueryManager{}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := tcpServer.CreateServer(queryManager)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	_, dialErr := DialRemoteExecutor(listener.Addr().String(), "bob", "wrong")
+	executor, err := DialRemoteExecutor(listener.Addr().String(), "bob", "secret")
+	assert.Nil(t, err)
+	client := CreateClient(executor)
+	defer client.Close()
+	var orders []testOrder
+	err = client.RunInto(Get("shop.orders").Where("qty", ">", 1).Limit(1), &orders)
+
+	assert.EqualError(t, dialErr, "authentication failed")
+	assert.Nil(t, err)
+	assert.Equal(t, []testOrder{{ID: "a1", Status: "open", Qty: 2}}, orders)
+	assert.Equal(t, "bob", queryManager.users[0].User)
+	assert.Equal(t, 1, queryManager.queries[0].With.Limit)
+	assert.Equal(t, float64(1), queryManager.queries[0].With.Filter[0].Value)
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const TagName = "nimy"
+
+var timeType = reflect.TypeOf(time.Time{})
+var pageRecordType = reflect.TypeOf(diskModels.PageRecord{})
+
+type fieldInfo struct {
+	key       string
+	index     int
+	omitEmpty bool
+}
+
+func EncodeRecords(value any) ([]diskModels.PageRecord, error) {
+	switch typedValue := value.(type) {
+	case diskModels.PageRecord:
+		return []diskModels.PageRecord{typedValue}, nil
+	case map[string]any:
+		return []diskModels.PageRecord{typedValue}, nil
+	case []diskModels.PageRecord:
+		return typedValue, nil
+	}
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Pointer {
+		if reflectValue.IsNil() {
+			return nil, errors.New("record cannot be nil")
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		pageRecord, err := encodeStruct(reflectValue)
+		if err != nil {
+			return nil, err
+		}
+		return []diskModels.PageRecord{pageRecord}, nil
+	case reflect.Slice, reflect.Array:
+		pageRecords := []diskModels.PageRecord{}
+		for i := 0; i < reflectValue.Len(); i++ {
+			items, err := EncodeRecords(reflectValue.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			pageRecords = append(pageRecords, items...)
+		}
+		return pageRecords, nil
+	default:
+		return nil, fmt.Errorf("record of type %s cannot be encoded", reflectValue.Type())
+	}
+}
+
+func DecodeRecords(pageRecords []diskModels.PageRecord, target any) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
+		return errors.New("decode target must be a non nil pointer")
+	}
+	targetValue = targetValue.Elem()
+	if targetValue.Kind() != reflect.Slice {
+		if len(pageRecords) == 0 {
+			return errors.New("no records to decode")
+		}
+		return decodeRecord(pageRecords[0], targetValue)
+	}
+	slice := reflect.MakeSlice(targetValue.Type(), len(pageRecords), len(pageRecords))
+	for i, pageRecord := range pageRecords {
+		if err := decodeRecord(pageRecord, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	targetValue.Set(slice)
+	return nil
+}
+
+func encodeStruct(structValue reflect.Value) (diskModels.PageRecord, error) {
+	pageRecord := diskModels.PageRecord{}
+	for _, field := range getFields(structValue.Type()) {
+		fieldValue := structValue.Field(field.index)
+		if field.omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		pageRecord[field.key] = encodeValue(fieldValue)
+	}
+	return pageRecord, nil
+}
+
+func encodeValue(fieldValue reflect.Value) any {
+	if fieldValue.Kind() == reflect.Pointer {
+		if fieldValue.IsNil() {
+			return nil
+		}
+		fieldValue = fieldValue.Elem()
+	}
+	if fieldValue.Type() == timeType {
+		return int(fieldValue.Interface().(time.Time).Unix())
+	}
+	switch fieldValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(fieldValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(fieldValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fieldValue.Float()
+	default:
+		return fieldValue.Interface()
+	}
+}
+
+func decodeRecord(pageRecord diskModels.PageRecord, target reflect.Value) error {
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+	if target.Type() == pageRecordType || target.Kind() == reflect.Map && target.Type().Key().Kind() == reflect.String {
+		target.Set(reflect.ValueOf(pageRecord).Convert(target.Type()))
+		return nil
+	}
+	if target.Kind() != reflect.Struct {
+		return fmt.Errorf("records cannot be decoded into %s", target.Type())
+	}
+	for _, field := range getFields(target.Type()) {
+		value, ok := pageRecord[field.key]
+		if !ok {
+			continue
+		}
+		if err := setValue(target.Field(field.index), value); err != nil {
+			return fmt.Errorf("key %s: %s", field.key, err.Error())
+		}
+	}
+	return nil
+}
+
+func setValue(fieldValue reflect.Value, value any) error {
+	if value == nil {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		return nil
+	}
+	if fieldValue.Kind() == reflect.Pointer {
+		pointer := reflect.New(fieldValue.Type().Elem())
+		if err := setValue(pointer.Elem(), value); err != nil {
+			return err
+		}
+		fieldValue.Set(pointer)
+		return nil
+	}
+	if fieldValue.Type() == timeType {
+		timeValue, err := convertTime(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(timeValue))
+		return nil
+	}
+	switch fieldValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := convertInt(value)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(number)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, err := convertInt(value)
+		if err != nil {
+			return err
+		}
+		if number < 0 {
+			return fmt.Errorf("value %d cannot be unsigned", number)
+		}
+		fieldValue.SetUint(uint64(number))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch number := value.(type) {
+		case int:
+			fieldValue.SetFloat(float64(number))
+			return nil
+		case float64:
+			fieldValue.SetFloat(number)
+			return nil
+		}
+	}
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Type().AssignableTo(fieldValue.Type()) {
+		fieldValue.Set(reflectValue)
+		return nil
+	}
+	if reflectValue.Kind() == fieldValue.Kind() && reflectValue.Type().ConvertibleTo(fieldValue.Type()) {
+		fieldValue.Set(reflectValue.Convert(fieldValue.Type()))
+		return nil
+	}
+	return fmt.Errorf("value %v of type %T cannot be decoded into %s", value, value, fieldValue.Type())
+}
+
+func convertInt(value any) (int64, error) {
+	switch number := value.(type) {
+	case int:
+		return int64(number), nil
+	case int64:
+		return number, nil
+	case float64:
+		if number != math.Trunc(number) {
+			return 0, fmt.Errorf("value %v is not an integer", number)
+		}
+		return int64(number), nil
+	default:
+		return 0, fmt.Errorf("value %v of type %T is not an integer", value, value)
+	}
+}
+
+func convertTime(value any) (time.Time, error) {
+	switch timeValue := value.(type) {
+	case string:
+		for _, layout := range []string{time.DateTime, time.DateOnly, time.RFC3339} {
+			if parsed, err := time.Parse(layout, timeValue); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("value %s is not a date or datetime", timeValue)
+	default:
+		number, err := convertInt(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(number, 0), nil
+	}
+}
+
+func getFields(structType reflect.Type) []fieldInfo {
+	fields := []fieldInfo{}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		tag, ok := structField.Tag.Lookup(TagName)
+		if !ok || tag == "-" || !structField.IsExported() {
+			continue
+		}
+		key, options, _ := strings.Cut(tag, ",")
+		fields = append(fields, fieldInfo{
+			key:       key,
+			index:     i,
+			omitEmpty: options == "omitempty",
+		})
+	}
+	return fields
+}
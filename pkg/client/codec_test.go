@@ -0,0 +1,76 @@
+package client
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testOrder struct {
+	ID       string    `nimy:"_id,omitempty"`
+	Status   string    `nimy:"status"`
+	Qty      int       `nimy:"qty"`
+	Price    float64   `nimy:"price"`
+	Paid     bool      `nimy:"paid"`
+	Day      time.Time `nimy:"day"`
+	Note     *string   `nimy:"note"`
+	Internal string    `nimy:"-"`
+	Ignored  string
+}
+
+func TestUnit_EncodeRecords_EncodesTaggedFields(t *testing.T) {
+	note := "fragile"
+	orders := []testOrder{
+		{Status: "open", Qty: 2, Price: 1.5, Day: time.Unix(1704067200, 0), Note: &note, Internal: "x"},
+		{ID: "abc", Status: "closed"},
+	}
+
+	pageRecords, err := EncodeRecords(orders)
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{
+		"status": "open", "qty": 2, "price": 1.5, "paid": false, "day": 1704067200, "note": "fragile",
+	}, pageRecords[0])
+	assert.Equal(t, "abc", pageRecords[1]["_id"])
+	assert.Nil(t, pageRecords[1]["note"])
+}
+
+func TestUnit_DecodeRecords_DecodesEmbeddedAndRemoteValues(t *testing.T) {
+	pageRecords := []diskModels.PageRecord{
+		{"_id": "a1", "status": "open", "qty": 2, "price": 3, "paid": true, "day": "2024-01-02", "note": "fragile", "extra": 1},
+		{"_id": "b2", "status": "closed", "qty": float64(5), "price": 1.25, "day": "2024-01-02 10:30:00", "note": nil},
+	}
+	var orders []testOrder
+
+	err := DecodeRecords(pageRecords, &orders)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(orders))
+	assert.Equal(t, "a1", orders[0].ID)
+	assert.Equal(t, 2, orders[0].Qty)
+	assert.Equal(t, float64(3), orders[0].Price)
+	assert.True(t, orders[0].Paid)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), orders[0].Day)
+	assert.Equal(t, "fragile", *orders[0].Note)
+	assert.Equal(t, 5, orders[1].Qty)
+	assert.Equal(t, time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC), orders[1].Day)
+	assert.Nil(t, orders[1].Note)
+}
+
+func TestUnit_DecodeRecords_DecodesSingleRecordAndErrors(t *testing.T) {
+	var order testOrder
+	var pointers []*testOrder
+	var records []map[string]any
+
+	assert.Nil(t, DecodeRecords([]diskModels.PageRecord{{"qty": 7}}, &order))
+	assert.Nil(t, DecodeRecords([]diskModels.PageRecord{{"qty": 7}}, &pointers))
+	assert.Nil(t, DecodeRecords([]diskModels.PageRecord{{"qty": 7}}, &records))
+	assert.Equal(t, 7, order.Qty)
+	assert.Equal(t, 7, pointers[0].Qty)
+	assert.Equal(t, 7, records[0]["qty"])
+	assert.EqualError(t, DecodeRecords([]diskModels.PageRecord{{"qty": 1.5}}, &order), "key qty: value 1.5 is not an integer")
+	assert.EqualError(t, DecodeRecords([]diskModels.PageRecord{{"status": 1}}, &order), "key status: value 1 of type int cannot be decoded into string")
+	assert.EqualError(t, DecodeRecords([]diskModels.PageRecord{}, &order), "no records to decode")
+	assert.NotNil(t, DecodeRecords([]diskModels.PageRecord{}, order))
+}
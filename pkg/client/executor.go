@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/server/tcp"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"net"
+	"sync"
+)
+
+type Executor interface {
+	Execute(query queryModels.Query) (queryModels.QueryResult, error)
+	Close() error
+}
+
+type embeddedExecutor struct {
+	m            sync.Mutex
+	queryManager queryManagers.QueryManager
+	user         systemModels.User
+}
+
+func CreateEmbeddedExecutor(queryManager queryManagers.QueryManager, user systemModels.User) Executor {
+	return &embeddedExecutor{
+		queryManager: queryManager,
+		user:         user,
+	}
+}
+
+func (ee *embeddedExecutor) Execute(query queryModels.Query) (queryModels.QueryResult, error) {
+	ee.m.Lock()
+	user := ee.user
+	ee.m.Unlock()
+	queryResult := ee.queryManager.Query(query, user)
+	if query.On == queryConstants.OnConnection && queryResult.ErrorMessage == "" {
+		ee.m.Lock()
+		ee.user = queryResult.ConnectionUser
+		ee.m.Unlock()
+	}
+	return queryResult, nil
+}
+
+func (ee *embeddedExecutor) Close() error {
+	return nil
+}
+
+type remoteExecutor struct {
+	m       sync.Mutex
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+func DialRemoteExecutor(address string, user string, password string) (Executor, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	executor := CreateRemoteExecutor(conn)
+	query, _ := Login(user, password).Build()
+	queryResult, err := executor.Execute(query)
+	if err == nil && queryResult.ErrorMessage != "" {
+		err = errors.New(queryResult.ErrorMessage)
+	}
+	if err != nil {
+		_ = executor.Close()
+		return nil, err
+	}
+	return executor, nil
+}
+
+func CreateRemoteExecutor(conn net.Conn) Executor {
+	return &remoteExecutor{
+		conn:    conn,
+		encoder: json.NewEncoder(conn),
+		decoder: json.NewDecoder(bufio.NewReaderSize(conn, 64*1024)),
+	}
+}
+
+func (re *remoteExecutor) Execute(query queryModels.Query) (queryModels.QueryResult, error) {
+	re.m.Lock()
+	defer re.m.Unlock()
+	var queryResult queryModels.QueryResult
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return queryResult, err
+	}
+	if len(queryBytes) > tcpServer.MaxQuerySize {
+		return queryResult, errors.New("query exceeds maximum query size")
+	}
+	if err = re.encoder.Encode(query); err != nil {
+		return queryResult, err
+	}
+	err = re.decoder.Decode(&queryResult)
+	return queryResult, err
+}
+
+func (re *remoteExecutor) Close() error {
+	return re.conn.Close()
+}
@@ -3,6 +3,7 @@ package diskManagers
 import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"sync"
 )
 
 type BlobManager interface {
@@ -19,21 +20,31 @@ type blobManager struct {
 }
 
 var blobManagerInstance BlobManager
+var blobManagerInstances = make(map[string]BlobManager)
+var blobManagerM = &sync.Mutex{}
 
 func CreateBlobManager(dataLocation string) BlobManager {
-	if blobManagerInstance == nil {
-		blobManagerInstance = &blobManager{
+	blobManagerM.Lock()
+	defer blobManagerM.Unlock()
+	if blobManagerInstance != nil {
+		return blobManagerInstance
+	}
+	if _, ok := blobManagerInstances[dataLocation]; !ok {
+		blobManagerInstances[dataLocation] = &blobManager{
 			dataLocation:       dataLocation,
 			createDirFunc:      diskUtils.CreateDir,
 			deleteDirFunc:      diskUtils.DeleteDirectory,
 			getDirContentsFunc: diskUtils.GetDirectoryContents,
 		}
 	}
-	return blobManagerInstance
+	return blobManagerInstances[dataLocation]
 }
 
 func DestructBlobManager() {
+	blobManagerM.Lock()
+	defer blobManagerM.Unlock()
 	blobManagerInstance = nil
+	blobManagerInstances = make(map[string]BlobManager)
 }
 
 func (bdm *blobManager) Create(db string, blob string) error {
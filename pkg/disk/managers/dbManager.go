@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
 	"os"
+	"sync"
 )
 
 type DBManager interface {
@@ -22,10 +23,17 @@ type dbManager struct {
 }
 
 var dbManagerInstance DBManager
+var dbManagerInstances = make(map[string]DBManager)
+var dbManagerM = &sync.Mutex{}
 
 func CreateDBManager(dataLocation string) DBManager {
-	if dbManagerInstance == nil {
-		dbManagerInstance = &dbManager{
+	dbManagerM.Lock()
+	defer dbManagerM.Unlock()
+	if dbManagerInstance != nil {
+		return dbManagerInstance
+	}
+	if _, ok := dbManagerInstances[dataLocation]; !ok {
+		dbManagerInstances[dataLocation] = &dbManager{
 			dataLocation:       dataLocation,
 			createDirFunc:      diskUtils.CreateDir,
 			deleteDirFunc:      diskUtils.DeleteDirectory,
@@ -33,7 +41,14 @@ func CreateDBManager(dataLocation string) DBManager {
 			osStatFunc:         os.Stat,
 		}
 	}
-	return dbManagerInstance
+	return dbManagerInstances[dataLocation]
+}
+
+func DestructDBManager() {
+	dbManagerM.Lock()
+	defer dbManagerM.Unlock()
+	dbManagerInstance = nil
+	dbManagerInstances = make(map[string]DBManager)
 }
 
 func (ddm *dbManager) Create(db string) error {
@@ -2,18 +2,29 @@ package diskManagers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"io/fs"
+	"sync"
 )
 
 const (
-	formatFile = "format.json"
+	formatFile    = "format.json"
+	alterFile     = "alter.json"
+	migrationFile = "migrations.json"
 )
 
 type FormatManager interface {
 	Create(db string, blob string, format diskModels.Format) error
 	Get(db string, blob string) (diskModels.Format, error)
+	Update(db string, blob string, format diskModels.Format) error
+	GetAlter(db string, blob string) (*diskModels.Alter, error)
+	WriteAlter(db string, blob string, alter diskModels.Alter) error
+	DeleteAlter(db string, blob string) error
+	GetMigrations(db string, blob string) ([]string, error)
+	WriteMigrations(db string, blob string, migrations []string) error
 }
 
 type formatManager struct {
@@ -21,24 +32,36 @@ type formatManager struct {
 	createFileFunc func(filePath string) error
 	writeFileFunc  func(filePath string, fileData []byte) error
 	getFileFunc    func(filePath string) ([]byte, error)
+	deleteFileFunc func(filePath string) error
 }
 
 var formatManagerInstance FormatManager
+var formatManagerInstances = make(map[string]FormatManager)
+var formatManagerM = &sync.Mutex{}
 
 func CreateFormatManager(dataLocation string) FormatManager {
-	if formatManagerInstance == nil {
-		formatManagerInstance = &formatManager{
+	formatManagerM.Lock()
+	defer formatManagerM.Unlock()
+	if formatManagerInstance != nil {
+		return formatManagerInstance
+	}
+	if _, ok := formatManagerInstances[dataLocation]; !ok {
+		formatManagerInstances[dataLocation] = &formatManager{
 			dataLocation:   dataLocation,
 			createFileFunc: diskUtils.CreateFile,
 			writeFileFunc:  diskUtils.WriteFile,
 			getFileFunc:    diskUtils.GetFile,
+			deleteFileFunc: diskUtils.DeleteFile,
 		}
 	}
-	return formatManagerInstance
+	return formatManagerInstances[dataLocation]
 }
 
 func DestructFormatManager() {
+	formatManagerM.Lock()
+	defer formatManagerM.Unlock()
 	formatManagerInstance = nil
+	formatManagerInstances = make(map[string]FormatManager)
 }
 
 func (fdm *formatManager) Create(db string, blob string, format diskModels.Format) error {
@@ -61,3 +84,54 @@ func (fdm *formatManager) Get(db string, blob string) (diskModels.Format, error)
 	err = json.Unmarshal(file, &format)
 	return format, err
 }
+
+func (fdm *formatManager) Update(db string, blob string, format diskModels.Format) error {
+	formatData, _ := json.Marshal(format)
+	return fdm.writeFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, formatFile), formatData)
+}
+
+func (fdm *formatManager) GetAlter(db string, blob string) (*diskModels.Alter, error) {
+	file, err := fdm.getFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, alterFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	alter := diskModels.Alter{}
+	if err = json.Unmarshal(file, &alter); err != nil {
+		return nil, err
+	}
+	return &alter, nil
+}
+
+func (fdm *formatManager) WriteAlter(db string, blob string, alter diskModels.Alter) error {
+	alterData, _ := json.Marshal(alter)
+	return fdm.writeFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, alterFile), alterData)
+}
+
+func (fdm *formatManager) DeleteAlter(db string, blob string) error {
+	err := fdm.deleteFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, alterFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (fdm *formatManager) GetMigrations(db string, blob string) ([]string, error) {
+	migrations := []string{}
+	file, err := fdm.getFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, migrationFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return migrations, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(file, &migrations)
+	return migrations, err
+}
+
+func (fdm *formatManager) WriteMigrations(db string, blob string, migrations []string) error {
+	migrationData, _ := json.Marshal(migrations)
+	return fdm.writeFileFunc(fmt.Sprintf("%s/%s/%s/%s", fdm.dataLocation, db, blob, migrationFile), migrationData)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
 	"github.com/stretchr/testify/assert"
+	"io/fs"
 	"reflect"
 	"testing"
 )
@@ -24,6 +25,7 @@ func TestUnit_CreateFormatManager_CreatesFormatManager(t *testing.T) {
 	assert.Equal(t, reflect.ValueOf(diskUtils.CreateFile).Pointer(), reflect.Indirect(fmV).FieldByName("createFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.WriteFile).Pointer(), reflect.Indirect(fmV).FieldByName("writeFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetFile).Pointer(), reflect.Indirect(fmV).FieldByName("getFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteFile).Pointer(), reflect.Indirect(fmV).FieldByName("deleteFileFunc").Pointer())
 }
 
 func TestUnit_Create_CreatesFormatFile(t *testing.T) {
@@ -143,3 +145,100 @@ func TestUnit_Get_FailsOnInvalidFormatBytes(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 }
+
+func TestUnit_Update_WritesFormatFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	format := diskModels.Format{"col_one": diskModels.FormatItem{KeyType: "some_key_type"}}
+	formatBytes, _ := json.Marshal(format)
+	writeCalled := false
+	fm := createTestFormatManager(dataLocation)
+	fm.writeFileFunc = func(filePath string, fileBytes []byte) error {
+		writeCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, formatFile), filePath)
+		assert.Equal(t, formatBytes, fileBytes)
+		return nil
+	}
+
+	err := fm.Update(db, blob, format)
+
+	assert.True(t, writeCalled)
+	assert.Nil(t, err)
+}
+
+func TestUnit_WriteAlter_WritesAndGetsAlterFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	alter := diskModels.Alter{Op: "rename", Key: "col_one", NewKey: "col_two"}
+	files := map[string][]byte{}
+	fm := createTestFormatManager(dataLocation)
+	fm.writeFileFunc = func(filePath string, fileBytes []byte) error {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, alterFile), filePath)
+		files[filePath] = fileBytes
+		return nil
+	}
+	fm.getFileFunc = func(filePath string) ([]byte, error) {
+		return files[filePath], nil
+	}
+
+	writeErr := fm.WriteAlter(db, blob, alter)
+	result, getErr := fm.GetAlter(db, blob)
+
+	assert.Nil(t, writeErr)
+	assert.Nil(t, getErr)
+	assert.Equal(t, &alter, result)
+}
+
+func TestUnit_GetAlter_GetsNilOnMissingAlterFile(t *testing.T) {
+	fm := createTestFormatManager("dataLocation")
+	fm.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+	fm.deleteFileFunc = func(filePath string) error {
+		return fs.ErrNotExist
+	}
+
+	result, getErr := fm.GetAlter("db", "blob")
+	deleteErr := fm.DeleteAlter("db", "blob")
+
+	assert.Nil(t, getErr)
+	assert.Nil(t, deleteErr)
+	assert.Nil(t, result)
+}
+
+func TestUnit_WriteMigrations_WritesAndGetsMigrationFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	files := map[string][]byte{}
+	fm := createTestFormatManager(dataLocation)
+	fm.writeFileFunc = func(filePath string, fileBytes []byte) error {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, migrationFile), filePath)
+		files[filePath] = fileBytes
+		return nil
+	}
+	fm.getFileFunc = func(filePath string) ([]byte, error) {
+		return files[filePath], nil
+	}
+
+	writeErr := fm.WriteMigrations(db, blob, []string{"migration"})
+	result, getErr := fm.GetMigrations(db, blob)
+
+	assert.Nil(t, writeErr)
+	assert.Nil(t, getErr)
+	assert.Equal(t, []string{"migration"}, result)
+}
+
+func TestUnit_GetMigrations_GetsEmptyOnMissingMigrationFile(t *testing.T) {
+	fm := createTestFormatManager("dataLocation")
+	fm.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+
+	result, err := fm.GetMigrations("db", "blob")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, result)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"sync"
 )
 
 const (
@@ -21,6 +22,7 @@ type IndexManager interface {
 	WriteData(db string, blob string, indexFileName string, data diskModels.IndexRecords) error
 	Delete(db string, blob string, indexFileName string) (bool, error)
 	GetPageRecordIdPrefix(pageRecordId string) string
+	Truncate(db string, blob string) error
 }
 
 type indexManager struct {
@@ -30,28 +32,40 @@ type indexManager struct {
 	writeFileFunc  func(filePath string, fileData []byte) error
 	getFileFunc    func(filePath string) ([]byte, error)
 	deleteFileFunc func(filePath string) error
+	deleteDirFunc  func(directory string) error
 	uuidFunc       func() string
 }
 
 var indexManagerInstance IndexManager
+var indexManagerInstances = make(map[string]IndexManager)
+var indexManagerM = &sync.Mutex{}
 
 func CreateIndexManager(dataLocation string) IndexManager {
-	if indexManagerInstance == nil {
-		indexManagerInstance = &indexManager{
+	indexManagerM.Lock()
+	defer indexManagerM.Unlock()
+	if indexManagerInstance != nil {
+		return indexManagerInstance
+	}
+	if _, ok := indexManagerInstances[dataLocation]; !ok {
+		indexManagerInstances[dataLocation] = &indexManager{
 			dataLocation:   dataLocation,
 			createFileFunc: diskUtils.CreateFile,
 			createDirFunc:  diskUtils.CreateDir,
 			writeFileFunc:  diskUtils.WriteFile,
 			getFileFunc:    diskUtils.GetFile,
 			deleteFileFunc: diskUtils.DeleteFile,
+			deleteDirFunc:  diskUtils.DeleteDirectory,
 			uuidFunc:       diskUtils.GetUUID,
 		}
 	}
-	return indexManagerInstance
+	return indexManagerInstances[dataLocation]
 }
 
 func DestructIndexManager() {
+	indexManagerM.Lock()
+	defer indexManagerM.Unlock()
 	indexManagerInstance = nil
+	indexManagerInstances = make(map[string]IndexManager)
 }
 
 func (idm *indexManager) Initialize(db string, blob string) error {
@@ -151,6 +165,13 @@ func (idm *indexManager) Delete(db string, blob string, indexFileName string) (b
 	return err != nil, err
 }
 
+func (idm *indexManager) Truncate(db string, blob string) error {
+	if err := idm.deleteDirFunc(idm.getIndexesDirectoryName(db, blob)); err != nil {
+		return err
+	}
+	return idm.Initialize(db, blob)
+}
+
 func (idm *indexManager) GetPageRecordIdPrefix(pageRecordId string) string {
 	return pageRecordId[0:indexPrefixLength]
 }
@@ -26,6 +26,7 @@ func TestUnit_CreateIndexManager_CreatesIndexManager(t *testing.T) {
 	assert.Equal(t, reflect.ValueOf(diskUtils.WriteFile).Pointer(), reflect.Indirect(imV).FieldByName("writeFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetFile).Pointer(), reflect.Indirect(imV).FieldByName("getFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteFile).Pointer(), reflect.Indirect(imV).FieldByName("deleteFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteDirectory).Pointer(), reflect.Indirect(imV).FieldByName("deleteDirFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetUUID).Pointer(), reflect.Indirect(imV).FieldByName("uuidFunc").Pointer())
 }
 
@@ -695,3 +696,56 @@ func TestUnit_Delete_FailsOnDeleteIndexFile(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.True(t, phantomFile)
 }
+
+func TestUnit_Truncate_TruncatesIndexes(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	deleteDirCalled := false
+	writeFileCalled := false
+	createDirCalled := false
+	im := createTestIndexManager(dataLocation)
+	im.deleteDirFunc = func(directory string) error {
+		deleteDirCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, indexesDirectory), directory)
+		return nil
+	}
+	im.createFileFunc = func(filePath string) error {
+		return nil
+	}
+	im.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, indexesFile), filePath)
+		blankBytes, _ := json.Marshal(diskModels.Indexes{})
+		assert.Equal(t, blankBytes, fileData)
+		return nil
+	}
+	im.createDirFunc = func(directory string) error {
+		createDirCalled = true
+		return nil
+	}
+
+	err := im.Truncate(db, blob)
+
+	assert.True(t, deleteDirCalled)
+	assert.True(t, writeFileCalled)
+	assert.True(t, createDirCalled)
+	assert.Nil(t, err)
+}
+
+func TestUnit_Truncate_FailsOnIndexesDirectoryDeleteError(t *testing.T) {
+	writeFileCalled := false
+	im := createTestIndexManager("dataLocation")
+	im.deleteDirFunc = func(directory string) error {
+		return assert.AnError
+	}
+	im.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		return nil
+	}
+
+	err := im.Truncate("db", "blob")
+
+	assert.False(t, writeFileCalled)
+	assert.NotNil(t, err)
+}
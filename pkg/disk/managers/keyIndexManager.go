@@ -0,0 +1,205 @@
+package diskManagers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+const (
+	keyIndexesFile       = "key_indexes.json"
+	keyIndexesDirectory  = "key_indexes"
+	keyIndexPrefixLength = 1
+)
+
+type KeyIndexManager interface {
+	GetAll(db string, blob string) (diskModels.KeyIndexes, error)
+	Create(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error
+	GetPrefixes(db string, blob string, key string) ([]string, error)
+	GetData(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error)
+	WriteData(db string, blob string, key string, prefix string, data diskModels.KeyIndexRecords) error
+	GetOrderedData(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error)
+	WriteOrderedData(db string, blob string, key string, prefix string, data diskModels.OrderedIndex) error
+	Delete(db string, blob string, key string) error
+	GetPageRecordIdPrefix(pageRecordId string) string
+}
+
+type keyIndexManager struct {
+	dataLocation       string
+	createDirFunc      func(directory string) error
+	writeFileFunc      func(filePath string, fileData []byte) error
+	getFileFunc        func(filePath string) ([]byte, error)
+	deleteFileFunc     func(filePath string) error
+	deleteDirFunc      func(directory string) error
+	getDirContentsFunc func(directory string) ([]string, error)
+}
+
+var keyIndexManagerInstance KeyIndexManager
+var keyIndexManagerInstances = make(map[string]KeyIndexManager)
+var keyIndexManagerM = &sync.Mutex{}
+
+func CreateKeyIndexManager(dataLocation string) KeyIndexManager {
+	keyIndexManagerM.Lock()
+	defer keyIndexManagerM.Unlock()
+	if keyIndexManagerInstance != nil {
+		return keyIndexManagerInstance
+	}
+	if _, ok := keyIndexManagerInstances[dataLocation]; !ok {
+		keyIndexManagerInstances[dataLocation] = &keyIndexManager{
+			dataLocation:       dataLocation,
+			createDirFunc:      diskUtils.CreateDir,
+			writeFileFunc:      diskUtils.WriteFile,
+			getFileFunc:        diskUtils.GetFile,
+			deleteFileFunc:     diskUtils.DeleteFile,
+			deleteDirFunc:      diskUtils.DeleteDirectory,
+			getDirContentsFunc: diskUtils.GetDirectoryContents,
+		}
+	}
+	return keyIndexManagerInstances[dataLocation]
+}
+
+func DestructKeyIndexManager() {
+	keyIndexManagerM.Lock()
+	defer keyIndexManagerM.Unlock()
+	keyIndexManagerInstance = nil
+	keyIndexManagerInstances = make(map[string]KeyIndexManager)
+}
+
+func (kim *keyIndexManager) GetAll(db string, blob string) (diskModels.KeyIndexes, error) {
+	file, err := kim.getFileFunc(kim.getKeyIndexesFileName(db, blob))
+	if errors.Is(err, fs.ErrNotExist) {
+		return diskModels.KeyIndexes{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyIndexes := diskModels.KeyIndexes{}
+	err = json.Unmarshal(file, &keyIndexes)
+	return keyIndexes, err
+}
+
+func (kim *keyIndexManager) Create(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+	keyIndexes, err := kim.GetAll(db, blob)
+	if err != nil {
+		return err
+	}
+	if _, ok := keyIndexes[keyIndexItem.Key]; ok {
+		return fmt.Errorf("index on key %s already exists", keyIndexItem.Key)
+	}
+	if len(keyIndexes) == 0 {
+		if err = kim.createDirFunc(kim.getKeyIndexesDirectoryName(db, blob)); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+	if err = kim.createDirFunc(kim.getKeyIndexDirectoryName(db, blob, keyIndexItem.Key)); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	keyIndexes[keyIndexItem.Key] = keyIndexItem
+	keyIndexesData, _ := json.Marshal(keyIndexes)
+	return kim.writeFileFunc(kim.getKeyIndexesFileName(db, blob), keyIndexesData)
+}
+
+func (kim *keyIndexManager) GetPrefixes(db string, blob string, key string) ([]string, error) {
+	fileNames, err := kim.getDirContentsFunc(kim.getKeyIndexDirectoryName(db, blob, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefixes := []string{}
+	for _, fileName := range fileNames {
+		if prefix, ok := strings.CutSuffix(fileName, ".json"); ok {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, nil
+}
+
+func (kim *keyIndexManager) GetData(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error) {
+	keyIndexRecords := diskModels.KeyIndexRecords{}
+	file, err := kim.getFileFunc(kim.getKeyIndexFileName(db, blob, key, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return keyIndexRecords, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(file, &keyIndexRecords)
+	return keyIndexRecords, err
+}
+
+func (kim *keyIndexManager) WriteData(db string, blob string, key string, prefix string, data diskModels.KeyIndexRecords) error {
+	dataBytes, _ := json.Marshal(data)
+	return kim.writeFileFunc(kim.getKeyIndexFileName(db, blob, key, prefix), dataBytes)
+}
+
+func (kim *keyIndexManager) GetOrderedData(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error) {
+	var orderedIndex diskModels.OrderedIndex
+	file, err := kim.getFileFunc(kim.getKeyIndexFileName(db, blob, key, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return orderedIndex, nil
+	}
+	if err != nil {
+		return orderedIndex, err
+	}
+	err = json.Unmarshal(file, &orderedIndex)
+	return orderedIndex, err
+}
+
+func (kim *keyIndexManager) WriteOrderedData(db string, blob string, key string, prefix string, data diskModels.OrderedIndex) error {
+	dataBytes, _ := json.Marshal(data)
+	return kim.writeFileFunc(kim.getKeyIndexFileName(db, blob, key, prefix), dataBytes)
+}
+
+func (kim *keyIndexManager) Delete(db string, blob string, key string) error {
+	keyIndexes, err := kim.GetAll(db, blob)
+	if err != nil {
+		return err
+	}
+	if _, ok := keyIndexes[key]; !ok {
+		return fmt.Errorf("index on key %s does not exist", key)
+	}
+	delete(keyIndexes, key)
+	keyIndexesData, _ := json.Marshal(keyIndexes)
+	if err = kim.writeFileFunc(kim.getKeyIndexesFileName(db, blob), keyIndexesData); err != nil {
+		return err
+	}
+	err = kim.deleteFileFunc(kim.getLegacyKeyIndexFileName(db, blob, key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return kim.deleteDirFunc(kim.getKeyIndexDirectoryName(db, blob, key))
+}
+
+func (kim *keyIndexManager) GetPageRecordIdPrefix(pageRecordId string) string {
+	if len(pageRecordId) < keyIndexPrefixLength {
+		return pageRecordId
+	}
+	return pageRecordId[0:keyIndexPrefixLength]
+}
+
+func (kim *keyIndexManager) getKeyIndexesFileName(db string, blob string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kim.dataLocation, db, blob, keyIndexesFile)
+}
+
+func (kim *keyIndexManager) getKeyIndexesDirectoryName(db string, blob string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kim.dataLocation, db, blob, keyIndexesDirectory)
+}
+
+func (kim *keyIndexManager) getKeyIndexDirectoryName(db string, blob string, key string) string {
+	return fmt.Sprintf("%s/%s", kim.getKeyIndexesDirectoryName(db, blob), key)
+}
+
+func (kim *keyIndexManager) getKeyIndexFileName(db string, blob string, key string, prefix string) string {
+	return fmt.Sprintf("%s/%s.json", kim.getKeyIndexDirectoryName(db, blob, key), prefix)
+}
+
+func (kim *keyIndexManager) getLegacyKeyIndexFileName(db string, blob string, key string) string {
+	return fmt.Sprintf("%s/%s.json", kim.getKeyIndexesDirectoryName(db, blob), key)
+}
@@ -0,0 +1,271 @@
+package diskManagers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func createTestKeyIndexManager(dataLocation string) keyIndexManager {
+	return keyIndexManager{dataLocation: dataLocation}
+}
+
+func TestUnit_CreateKeyIndexManager_CreatesKeyIndexManager(t *testing.T) {
+	dataLocation := "dataLocation"
+	DestructKeyIndexManager()
+	kim := CreateKeyIndexManager(dataLocation)
+	defer DestructKeyIndexManager()
+
+	kimV := reflect.ValueOf(kim)
+
+	assert.Equal(t, dataLocation, reflect.Indirect(kimV).FieldByName("dataLocation").String())
+	assert.Equal(t, reflect.ValueOf(diskUtils.CreateDir).Pointer(), reflect.Indirect(kimV).FieldByName("createDirFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.WriteFile).Pointer(), reflect.Indirect(kimV).FieldByName("writeFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.GetFile).Pointer(), reflect.Indirect(kimV).FieldByName("getFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteFile).Pointer(), reflect.Indirect(kimV).FieldByName("deleteFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteDirectory).Pointer(), reflect.Indirect(kimV).FieldByName("deleteDirFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.GetDirectoryContents).Pointer(), reflect.Indirect(kimV).FieldByName("getDirContentsFunc").Pointer())
+}
+
+func TestUnit_GetAll_ReturnsEmptyKeyIndexesOnMissingFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, keyIndexesFile), filePath)
+		return nil, fs.ErrNotExist
+	}
+
+	keyIndexes, err := kim.GetAll(db, blob)
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexes{}, keyIndexes)
+}
+
+func TestUnit_GetAll_FailsOnGetFileError(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, assert.AnError
+	}
+
+	_, err := kim.GetAll("db", "blob")
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Create_CreatesKeyIndex(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	keyIndexItem := diskModels.KeyIndexItem{Key: "name", IndexType: "hash"}
+	createdDirs := []string{}
+	writtenFiles := make(map[string][]byte)
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+	kim.createDirFunc = func(directory string) error {
+		createdDirs = append(createdDirs, directory)
+		return nil
+	}
+	kim.writeFileFunc = func(filePath string, fileData []byte) error {
+		writtenFiles[filePath] = fileData
+		return nil
+	}
+
+	err := kim.Create(db, blob, keyIndexItem)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, keyIndexesDirectory),
+		fmt.Sprintf("%s/%s/%s/%s/name", dataLocation, db, blob, keyIndexesDirectory),
+	}, createdDirs)
+	expectedKeyIndexes, _ := json.Marshal(diskModels.KeyIndexes{"name": keyIndexItem})
+	assert.Equal(t, expectedKeyIndexes, writtenFiles[fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, keyIndexesFile)])
+	assert.Len(t, writtenFiles, 1)
+}
+
+func TestUnit_Create_FailsOnExistingKeyIndex(t *testing.T) {
+	createDirCalled := false
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return json.Marshal(diskModels.KeyIndexes{"name": {Key: "name", IndexType: "hash"}})
+	}
+	kim.createDirFunc = func(directory string) error {
+		createDirCalled = true
+		return nil
+	}
+
+	err := kim.Create("db", "blob", diskModels.KeyIndexItem{Key: "name", IndexType: "hash"})
+
+	assert.NotNil(t, err)
+	assert.False(t, createDirCalled)
+}
+
+func TestUnit_Delete_DeletesKeyIndex(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	deleteFileCalled := false
+	deleteDirCalled := false
+	var writtenKeyIndexes []byte
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return json.Marshal(diskModels.KeyIndexes{
+			"name": {Key: "name", IndexType: "hash"},
+			"age":  {Key: "age", IndexType: "hash"},
+		})
+	}
+	kim.writeFileFunc = func(filePath string, fileData []byte) error {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, keyIndexesFile), filePath)
+		writtenKeyIndexes = fileData
+		return nil
+	}
+	kim.deleteFileFunc = func(filePath string) error {
+		deleteFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s/name.json", dataLocation, db, blob, keyIndexesDirectory), filePath)
+		return fs.ErrNotExist
+	}
+	kim.deleteDirFunc = func(directory string) error {
+		deleteDirCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s/name", dataLocation, db, blob, keyIndexesDirectory), directory)
+		return nil
+	}
+
+	err := kim.Delete(db, blob, "name")
+
+	assert.Nil(t, err)
+	assert.True(t, deleteFileCalled)
+	assert.True(t, deleteDirCalled)
+	expectedKeyIndexes, _ := json.Marshal(diskModels.KeyIndexes{"age": {Key: "age", IndexType: "hash"}})
+	assert.Equal(t, expectedKeyIndexes, writtenKeyIndexes)
+}
+
+func TestUnit_Delete_FailsOnMissingKeyIndex(t *testing.T) {
+	writeFileCalled := false
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+	kim.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		return nil
+	}
+
+	err := kim.Delete("db", "blob", "name")
+
+	assert.NotNil(t, err)
+	assert.False(t, writeFileCalled)
+}
+
+func TestUnit_GetOrderedData_ReadsOrderedIndex(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	expected := diskModels.OrderedIndex{
+		Entries: []diskModels.OrderedIndexEntry{{Value: 1, PageRecordId: "id1", PageFile: "page1"}},
+		Nulls:   diskModels.IndexRecords{"id2": "page1"},
+	}
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s/age/a.json", dataLocation, db, blob, keyIndexesDirectory), filePath)
+		return json.Marshal(expected)
+	}
+
+	orderedIndex, err := kim.GetOrderedData(db, blob, "age", "a")
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, orderedIndex)
+}
+
+func TestUnit_GetOrderedData_ReadsNewlyCreatedIndex(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return []byte("{}"), nil
+	}
+
+	orderedIndex, err := kim.GetOrderedData("db", "blob", "age", "a")
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.OrderedIndex{}, orderedIndex)
+}
+
+func TestUnit_GetOrderedData_ReturnsEmptyOnMissingShard(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+
+	orderedIndex, err := kim.GetOrderedData("db", "blob", "age", "a")
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.OrderedIndex{}, orderedIndex)
+}
+
+func TestUnit_GetData_ReturnsEmptyOnMissingShard(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, fs.ErrNotExist
+	}
+
+	keyIndexRecords, err := kim.GetData("db", "blob", "name", "a")
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexRecords{}, keyIndexRecords)
+}
+
+func TestUnit_WriteData_WritesShardFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.writeFileFunc = func(filePath string, fileData []byte) error {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s/name/b.json", dataLocation, db, blob, keyIndexesDirectory), filePath)
+		return nil
+	}
+
+	err := kim.WriteData(db, blob, "name", "b", diskModels.KeyIndexRecords{})
+
+	assert.Nil(t, err)
+}
+
+func TestUnit_GetPrefixes_ReturnsShardPrefixes(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	kim := createTestKeyIndexManager(dataLocation)
+	kim.getDirContentsFunc = func(directory string) ([]string, error) {
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s/name", dataLocation, db, blob, keyIndexesDirectory), directory)
+		return []string{"a.json", "b.json", "tmp"}, nil
+	}
+
+	prefixes, err := kim.GetPrefixes(db, blob, "name")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, prefixes)
+}
+
+func TestUnit_GetPrefixes_ReturnsEmptyOnMissingDirectory(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+	kim.getDirContentsFunc = func(directory string) ([]string, error) {
+		return nil, fs.ErrNotExist
+	}
+
+	prefixes, err := kim.GetPrefixes("db", "blob", "name")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, prefixes)
+}
+
+func TestUnit_GetPageRecordIdPrefix_ReturnsLeadingCharacters(t *testing.T) {
+	kim := createTestKeyIndexManager("dataLocation")
+
+	assert.Equal(t, "a", kim.GetPageRecordIdPrefix("abc"))
+	assert.Equal(t, "", kim.GetPageRecordIdPrefix(""))
+}
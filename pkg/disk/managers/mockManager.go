@@ -37,6 +37,7 @@ type MockIndexManager struct {
 	WriteDataFunc             func(db string, blob string, indexFileName string, data diskModels.IndexRecords) error
 	DeleteFunc                func(db string, blob string, indexFileName string) (bool, error)
 	GetPageRecordIdPrefixFunc func(pageRecordId string) string
+	TruncateFunc              func(db string, blob string) error
 }
 
 var MockIndexManagerInstance *MockIndexManager
@@ -74,6 +75,10 @@ func (im *MockIndexManager) GetPageRecordIdPrefix(pageRecordId string) string {
 	return im.GetPageRecordIdPrefixFunc(pageRecordId)
 }
 
+func (im *MockIndexManager) Truncate(db string, blob string) error {
+	return im.TruncateFunc(db, blob)
+}
+
 type MockPartitionManager struct {
 	InitializeFunc     func(db string, blob string, partition diskModels.Partition) error
 	AddPageFunc        func(db string, blob string, hashKeyFileName string, pageFileName string) error
@@ -85,6 +90,9 @@ type MockPartitionManager struct {
 	DeleteFunc         func(db string, blob string, hashKeyFileName string) error
 	GetHashKeyFunc     func(partition diskModels.Partition, pageRecord diskModels.PageRecord) (string, error)
 	CreateHashKeyFunc  func(db string, blob string, hashKeyFileName string) (diskModels.PartitionPages, error)
+	GetCatalogFunc     func(db string, blob string) (diskModels.PartitionCatalog, error)
+	WriteCatalogFunc   func(db string, blob string, catalog diskModels.PartitionCatalog) error
+	TruncateFunc       func(db string, blob string) error
 }
 
 var MockPartitionManagerInstance *MockPartitionManager
@@ -134,9 +142,27 @@ func (pm *MockPartitionManager) CreateHashKey(db string, blob string, hashKeyFil
 	return pm.CreateHashKeyFunc(db, blob, hashKeyFileName)
 }
 
+func (pm *MockPartitionManager) GetCatalog(db string, blob string) (diskModels.PartitionCatalog, error) {
+	return pm.GetCatalogFunc(db, blob)
+}
+
+func (pm *MockPartitionManager) WriteCatalog(db string, blob string, catalog diskModels.PartitionCatalog) error {
+	return pm.WriteCatalogFunc(db, blob, catalog)
+}
+
+func (pm *MockPartitionManager) Truncate(db string, blob string) error {
+	return pm.TruncateFunc(db, blob)
+}
+
 type MockFormatManager struct {
-	CreateFunc func(db string, blob string, format diskModels.Format) error
-	GetFunc    func(db string, blob string) (diskModels.Format, error)
+	CreateFunc          func(db string, blob string, format diskModels.Format) error
+	GetFunc             func(db string, blob string) (diskModels.Format, error)
+	UpdateFunc          func(db string, blob string, format diskModels.Format) error
+	GetAlterFunc        func(db string, blob string) (*diskModels.Alter, error)
+	WriteAlterFunc      func(db string, blob string, alter diskModels.Alter) error
+	DeleteAlterFunc     func(db string, blob string) error
+	GetMigrationsFunc   func(db string, blob string) ([]string, error)
+	WriteMigrationsFunc func(db string, blob string, migrations []string) error
 }
 
 var MockFormatManagerInstance *MockFormatManager
@@ -152,6 +178,24 @@ func (fm *MockFormatManager) Create(db string, blob string, format diskModels.Fo
 func (fm *MockFormatManager) Get(db string, blob string) (diskModels.Format, error) {
 	return fm.GetFunc(db, blob)
 }
+func (fm *MockFormatManager) Update(db string, blob string, format diskModels.Format) error {
+	return fm.UpdateFunc(db, blob, format)
+}
+func (fm *MockFormatManager) GetAlter(db string, blob string) (*diskModels.Alter, error) {
+	return fm.GetAlterFunc(db, blob)
+}
+func (fm *MockFormatManager) WriteAlter(db string, blob string, alter diskModels.Alter) error {
+	return fm.WriteAlterFunc(db, blob, alter)
+}
+func (fm *MockFormatManager) DeleteAlter(db string, blob string) error {
+	return fm.DeleteAlterFunc(db, blob)
+}
+func (fm *MockFormatManager) GetMigrations(db string, blob string) ([]string, error) {
+	return fm.GetMigrationsFunc(db, blob)
+}
+func (fm *MockFormatManager) WriteMigrations(db string, blob string, migrations []string) error {
+	return fm.WriteMigrationsFunc(db, blob, migrations)
+}
 
 type MockPageManager struct {
 	InitializeFunc func(db string, blob string) error
@@ -160,6 +204,7 @@ type MockPageManager struct {
 	GetDataFunc    func(db string, blob string, pageFileName string) (diskModels.PageRecords, error)
 	WriteDataFunc  func(db string, blob string, pageFileName string, data diskModels.PageRecords) error
 	DeleteFunc     func(db string, blob string, pageFileName string) (bool, error)
+	TruncateFunc   func(db string, blob string) error
 }
 
 var MockPageManagerInstance *MockPageManager
@@ -192,3 +237,62 @@ func (pm *MockPageManager) WriteData(db string, blob string, pageFileName string
 func (pm *MockPageManager) Delete(db string, blob string, pageFileName string) (bool, error) {
 	return pm.DeleteFunc(db, blob, pageFileName)
 }
+
+func (pm *MockPageManager) Truncate(db string, blob string) error {
+	return pm.TruncateFunc(db, blob)
+}
+
+type MockKeyIndexManager struct {
+	GetAllFunc                func(db string, blob string) (diskModels.KeyIndexes, error)
+	CreateFunc                func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error
+	GetPrefixesFunc           func(db string, blob string, key string) ([]string, error)
+	GetDataFunc               func(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error)
+	WriteDataFunc             func(db string, blob string, key string, prefix string, data diskModels.KeyIndexRecords) error
+	GetOrderedDataFunc        func(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error)
+	WriteOrderedDataFunc      func(db string, blob string, key string, prefix string, data diskModels.OrderedIndex) error
+	DeleteFunc                func(db string, blob string, key string) error
+	GetPageRecordIdPrefixFunc func(pageRecordId string) string
+}
+
+var MockKeyIndexManagerInstance *MockKeyIndexManager
+
+func CreateMockKeyIndexManager() {
+	MockKeyIndexManagerInstance = &MockKeyIndexManager{}
+	keyIndexManagerInstance = MockKeyIndexManagerInstance
+}
+
+func (kim *MockKeyIndexManager) GetAll(db string, blob string) (diskModels.KeyIndexes, error) {
+	return kim.GetAllFunc(db, blob)
+}
+
+func (kim *MockKeyIndexManager) Create(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+	return kim.CreateFunc(db, blob, keyIndexItem)
+}
+
+func (kim *MockKeyIndexManager) GetPrefixes(db string, blob string, key string) ([]string, error) {
+	return kim.GetPrefixesFunc(db, blob, key)
+}
+
+func (kim *MockKeyIndexManager) GetData(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error) {
+	return kim.GetDataFunc(db, blob, key, prefix)
+}
+
+func (kim *MockKeyIndexManager) WriteData(db string, blob string, key string, prefix string, data diskModels.KeyIndexRecords) error {
+	return kim.WriteDataFunc(db, blob, key, prefix, data)
+}
+
+func (kim *MockKeyIndexManager) GetOrderedData(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error) {
+	return kim.GetOrderedDataFunc(db, blob, key, prefix)
+}
+
+func (kim *MockKeyIndexManager) WriteOrderedData(db string, blob string, key string, prefix string, data diskModels.OrderedIndex) error {
+	return kim.WriteOrderedDataFunc(db, blob, key, prefix, data)
+}
+
+func (kim *MockKeyIndexManager) Delete(db string, blob string, key string) error {
+	return kim.DeleteFunc(db, blob, key)
+}
+
+func (kim *MockKeyIndexManager) GetPageRecordIdPrefix(pageRecordId string) string {
+	return kim.GetPageRecordIdPrefixFunc(pageRecordId)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"sync"
 )
 
 const (
@@ -19,6 +20,7 @@ type PageManager interface {
 	GetData(db string, blob string, pageFileName string) (diskModels.PageRecords, error)
 	WriteData(db string, blob string, pageFileName string, data diskModels.PageRecords) error
 	Delete(db string, blob string, pageFileName string) (bool, error)
+	Truncate(db string, blob string) error
 }
 
 type pageManager struct {
@@ -28,28 +30,40 @@ type pageManager struct {
 	writeFileFunc  func(filePath string, fileData []byte) error
 	getFileFunc    func(filePath string) ([]byte, error)
 	deleteFileFunc func(filePath string) error
+	deleteDirFunc  func(directory string) error
 	uuidFunc       func() string
 }
 
 var pageManagerInstance PageManager
+var pageManagerInstances = make(map[string]PageManager)
+var pageManagerM = &sync.Mutex{}
 
 func CreatePageManager(dataLocation string) PageManager {
-	if pageManagerInstance == nil {
-		pageManagerInstance = &pageManager{
+	pageManagerM.Lock()
+	defer pageManagerM.Unlock()
+	if pageManagerInstance != nil {
+		return pageManagerInstance
+	}
+	if _, ok := pageManagerInstances[dataLocation]; !ok {
+		pageManagerInstances[dataLocation] = &pageManager{
 			dataLocation:   dataLocation,
 			createFileFunc: diskUtils.CreateFile,
 			createDirFunc:  diskUtils.CreateDir,
 			writeFileFunc:  diskUtils.WriteFile,
 			getFileFunc:    diskUtils.GetFile,
 			deleteFileFunc: diskUtils.DeleteFile,
+			deleteDirFunc:  diskUtils.DeleteDirectory,
 			uuidFunc:       diskUtils.GetUUID,
 		}
 	}
-	return pageManagerInstance
+	return pageManagerInstances[dataLocation]
 }
 
 func DestructPageManager() {
+	pageManagerM.Lock()
+	defer pageManagerM.Unlock()
 	pageManagerInstance = nil
+	pageManagerInstances = make(map[string]PageManager)
 }
 
 func (pdm *pageManager) Initialize(db string, blob string) error {
@@ -136,6 +150,13 @@ func (pdm *pageManager) Delete(db string, blob string, pageFileName string) (boo
 	return err != nil, err
 }
 
+func (pdm *pageManager) Truncate(db string, blob string) error {
+	if err := pdm.deleteDirFunc(pdm.getPagesDirectoryName(db, blob)); err != nil {
+		return err
+	}
+	return pdm.Initialize(db, blob)
+}
+
 func (pdm *pageManager) getPagesFileName(db string, blob string) string {
 	return fmt.Sprintf("%s/%s/%s/%s", pdm.dataLocation, db, blob, pagesFile)
 }
@@ -26,9 +26,22 @@ func TestUnit_CreatePageManager_CreatesPageManager(t *testing.T) {
 	assert.Equal(t, reflect.ValueOf(diskUtils.WriteFile).Pointer(), reflect.Indirect(pmV).FieldByName("writeFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetFile).Pointer(), reflect.Indirect(pmV).FieldByName("getFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteFile).Pointer(), reflect.Indirect(pmV).FieldByName("deleteFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteDirectory).Pointer(), reflect.Indirect(pmV).FieldByName("deleteDirFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetUUID).Pointer(), reflect.Indirect(pmV).FieldByName("uuidFunc").Pointer())
 }
 
+func TestUnit_CreatePageManager_CreatesPageManagerPerDataLocation(t *testing.T) {
+	DestructPageManager()
+	defer DestructPageManager()
+
+	first := CreatePageManager("first")
+	second := CreatePageManager("second")
+
+	assert.Same(t, first, CreatePageManager("first"))
+	assert.NotSame(t, first, second)
+	assert.Equal(t, "second", reflect.Indirect(reflect.ValueOf(second)).FieldByName("dataLocation").String())
+}
+
 func TestUnit_Initialize_InitializesPages(t *testing.T) {
 	dataLocation := "dataLocation"
 	db := "db"
@@ -667,3 +680,56 @@ func TestUnit_Delete_FailsOnDeletePageFile(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.True(t, phantomFile)
 }
+
+func TestUnit_Truncate_TruncatesPages(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	deleteDirCalled := false
+	writeFileCalled := false
+	createDirCalled := false
+	pm := createTestPageManager(dataLocation)
+	pm.deleteDirFunc = func(directory string) error {
+		deleteDirCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, pagesDirectory), directory)
+		return nil
+	}
+	pm.createFileFunc = func(filePath string) error {
+		return nil
+	}
+	pm.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, pagesFile), filePath)
+		blankBytes, _ := json.Marshal(diskModels.Pages{})
+		assert.Equal(t, blankBytes, fileData)
+		return nil
+	}
+	pm.createDirFunc = func(directory string) error {
+		createDirCalled = true
+		return nil
+	}
+
+	err := pm.Truncate(db, blob)
+
+	assert.True(t, deleteDirCalled)
+	assert.True(t, writeFileCalled)
+	assert.True(t, createDirCalled)
+	assert.Nil(t, err)
+}
+
+func TestUnit_Truncate_FailsOnPagesDirectoryDeleteError(t *testing.T) {
+	writeFileCalled := false
+	pm := createTestPageManager("dataLocation")
+	pm.deleteDirFunc = func(directory string) error {
+		return assert.AnError
+	}
+	pm.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		return nil
+	}
+
+	err := pm.Truncate("db", "blob")
+
+	assert.False(t, writeFileCalled)
+	assert.NotNil(t, err)
+}
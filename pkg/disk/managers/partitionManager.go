@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/utils"
+	"sync"
 )
 
 const (
-	partitionsFile      = "partitions.json"
-	partitionsDirectory = "partitions"
+	partitionsFile       = "partitions.json"
+	partitionsDirectory  = "partitions"
+	partitionCatalogFile = "partition_catalog.json"
 )
 
 type PartitionManager interface {
@@ -26,6 +28,9 @@ type PartitionManager interface {
 	Delete(db string, blob string, hashKeyFileName string) error
 	GetHashKey(partition diskModels.Partition, pageRecord diskModels.PageRecord) (string, error)
 	CreateHashKey(db string, blob string, hashKeyFileName string) (diskModels.PartitionPages, error)
+	GetCatalog(db string, blob string) (diskModels.PartitionCatalog, error)
+	WriteCatalog(db string, blob string, catalog diskModels.PartitionCatalog) error
+	Truncate(db string, blob string) error
 }
 
 type partitionManager struct {
@@ -36,13 +41,21 @@ type partitionManager struct {
 	getFileFunc        func(filePath string) ([]byte, error)
 	getDirContentsFunc func(directory string) ([]string, error)
 	deleteFileFunc     func(filePath string) error
+	deleteDirFunc      func(directory string) error
 }
 
 var partitionManagerInstance PartitionManager
+var partitionManagerInstances = make(map[string]PartitionManager)
+var partitionManagerM = &sync.Mutex{}
 
 func CreatePartitionManager(dataLocation string) PartitionManager {
-	if partitionManagerInstance == nil {
-		partitionManagerInstance = &partitionManager{
+	partitionManagerM.Lock()
+	defer partitionManagerM.Unlock()
+	if partitionManagerInstance != nil {
+		return partitionManagerInstance
+	}
+	if _, ok := partitionManagerInstances[dataLocation]; !ok {
+		partitionManagerInstances[dataLocation] = &partitionManager{
 			dataLocation:       dataLocation,
 			createFileFunc:     diskUtils.CreateFile,
 			createDirFunc:      diskUtils.CreateDir,
@@ -50,13 +63,17 @@ func CreatePartitionManager(dataLocation string) PartitionManager {
 			getFileFunc:        diskUtils.GetFile,
 			getDirContentsFunc: diskUtils.GetDirectoryContents,
 			deleteFileFunc:     diskUtils.DeleteFile,
+			deleteDirFunc:      diskUtils.DeleteDirectory,
 		}
 	}
-	return partitionManagerInstance
+	return partitionManagerInstances[dataLocation]
 }
 
 func DestructPartitionManager() {
+	partitionManagerM.Lock()
+	defer partitionManagerM.Unlock()
 	partitionManagerInstance = nil
+	partitionManagerInstances = make(map[string]PartitionManager)
 }
 
 func (pdm *partitionManager) Initialize(db string, blob string, partition diskModels.Partition) error {
@@ -146,7 +163,13 @@ func (pdm *partitionManager) Delete(db string, blob string, hashKeyFileName stri
 func (pdm *partitionManager) GetHashKey(partition diskModels.Partition, pageRecord diskModels.PageRecord) (string, error) {
 	hashKey := ""
 	for _, key := range partition.Keys {
-		hashKeyItem, err := pdm.GetHashKeyItem(key, pageRecord)
+		var hashKeyItem string
+		var err error
+		if _, ok := partition.Buckets[key]; ok {
+			hashKeyItem, err = pdm.getBucketKeyItem(key, pageRecord)
+		} else {
+			hashKeyItem, err = pdm.GetHashKeyItem(key, pageRecord)
+		}
 		if err != nil {
 			return hashKey, err
 		}
@@ -165,6 +188,14 @@ func (pdm *partitionManager) GetHashKeyItem(partitionKey string, pageRecord disk
 	return base64.URLEncoding.EncodeToString(hash.Sum(nil)), nil
 }
 
+func (pdm *partitionManager) getBucketKeyItem(partitionKey string, pageRecord diskModels.PageRecord) (string, error) {
+	bucketLabel, ok := pageRecord[partitionKey].(string)
+	if !ok || bucketLabel == "" {
+		return "", errors.New(fmt.Sprintf("%s bucket not found in page record", partitionKey))
+	}
+	return bucketLabel, nil
+}
+
 func (pdm *partitionManager) CreateHashKey(db string, blob string, hashKeyFileName string) (diskModels.PartitionPages, error) {
 	hashKeyFilePath := fmt.Sprintf("%s/%s", pdm.getPartitionsDirectoryName(db, blob), hashKeyFileName)
 	err := pdm.createFileFunc(hashKeyFilePath)
@@ -178,6 +209,37 @@ func (pdm *partitionManager) CreateHashKey(db string, blob string, hashKeyFileNa
 	return partitionPages, pdm.writeFileFunc(hashKeyFilePath, partitionPagesData)
 }
 
+func (pdm *partitionManager) GetCatalog(db string, blob string) (diskModels.PartitionCatalog, error) {
+	file, err := pdm.getFileFunc(pdm.getPartitionCatalogFileName(db, blob))
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := diskModels.PartitionCatalog{}
+	err = json.Unmarshal(file, &catalog)
+	return catalog, err
+}
+
+func (pdm *partitionManager) WriteCatalog(db string, blob string, catalog diskModels.PartitionCatalog) error {
+	catalogData, _ := json.Marshal(catalog)
+	return pdm.writeFileFunc(pdm.getPartitionCatalogFileName(db, blob), catalogData)
+}
+
+func (pdm *partitionManager) Truncate(db string, blob string) error {
+	partitionsDirectoryName := pdm.getPartitionsDirectoryName(db, blob)
+	if err := pdm.deleteDirFunc(partitionsDirectoryName); err != nil {
+		return err
+	}
+	if err := pdm.createDirFunc(partitionsDirectoryName); err != nil {
+		return err
+	}
+	return pdm.WriteCatalog(db, blob, diskModels.PartitionCatalog{})
+}
+
+func (pdm *partitionManager) getPartitionCatalogFileName(db string, blob string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", pdm.dataLocation, db, blob, partitionCatalogFile)
+}
+
 func (pdm *partitionManager) getPartitionsFileName(db string, blob string) string {
 	return fmt.Sprintf("%s/%s/%s/%s", pdm.dataLocation, db, blob, partitionsFile)
 }
@@ -28,6 +28,7 @@ func TestUnit_CreatePartitionManager_CreatesPartitionManager(t *testing.T) {
 	assert.Equal(t, reflect.ValueOf(diskUtils.WriteFile).Pointer(), reflect.Indirect(pmV).FieldByName("writeFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetFile).Pointer(), reflect.Indirect(pmV).FieldByName("getFileFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteFile).Pointer(), reflect.Indirect(pmV).FieldByName("deleteFileFunc").Pointer())
+	assert.Equal(t, reflect.ValueOf(diskUtils.DeleteDirectory).Pointer(), reflect.Indirect(pmV).FieldByName("deleteDirFunc").Pointer())
 	assert.Equal(t, reflect.ValueOf(diskUtils.GetDirectoryContents).Pointer(), reflect.Indirect(pmV).FieldByName("getDirContentsFunc").Pointer())
 }
 
@@ -665,6 +666,38 @@ func TestUnit_GetHashKey_FailsOnHashKeyItem(t *testing.T) {
 	assert.Equal(t, result, keyItem1)
 }
 
+func TestUnit_GetHashKey_UsesBucketLabels(t *testing.T) {
+	dataLocation := "location"
+	partition := diskModels.Partition{
+		Keys:    []string{"col_1", "col_2"},
+		Buckets: map[string]diskModels.PartitionBucket{"col_2": {Type: "time", Period: "day"}},
+	}
+	pageRecord := diskModels.PageRecord{
+		"col_1": "value_1",
+		"col_2": "20240315",
+	}
+	pm := createTestPartitionManager(dataLocation)
+	keyItem1, _ := pm.GetHashKeyItem("col_1", pageRecord)
+
+	result, err := pm.GetHashKey(partition, pageRecord)
+
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("%s20240315.json", keyItem1), result)
+}
+
+func TestUnit_GetHashKey_FailsOnMissingBucketLabel(t *testing.T) {
+	dataLocation := "location"
+	partition := diskModels.Partition{
+		Keys:    []string{"col_1"},
+		Buckets: map[string]diskModels.PartitionBucket{"col_1": {Type: "range", Bounds: []float64{10}}},
+	}
+	pm := createTestPartitionManager(dataLocation)
+
+	_, err := pm.GetHashKey(partition, diskModels.PageRecord{"col_1": 5})
+
+	assert.NotNil(t, err)
+}
+
 func TestUnit_GetHashKeyItem_GetsHashKeyItem(t *testing.T) {
 	dataLocation := "location"
 	key := "col_1"
@@ -773,3 +806,111 @@ func TestUnit_CreateHashKey_FailsOnHashKeyWrite(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, diskModels.PartitionPages{}, result)
 }
+
+func TestUnit_GetCatalog_GetsCatalog(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	catalog := diskModels.PartitionCatalog{"hash.json": {Values: map[string]any{"col_one": "a"}, Records: 2, Pages: 1}}
+	getFileCalled := false
+	pm := createTestPartitionManager(dataLocation)
+	pm.getFileFunc = func(filePath string) ([]byte, error) {
+		getFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, partitionCatalogFile), filePath)
+		catalogBytes, _ := json.Marshal(catalog)
+		return catalogBytes, nil
+	}
+
+	result, err := pm.GetCatalog(db, blob)
+
+	assert.True(t, getFileCalled)
+	assert.Nil(t, err)
+	assert.Equal(t, catalog, result)
+}
+
+func TestUnit_GetCatalog_FailsOnGetCatalogFile(t *testing.T) {
+	dataLocation := "dataLocation"
+	pm := createTestPartitionManager(dataLocation)
+	pm.getFileFunc = func(filePath string) ([]byte, error) {
+		return nil, assert.AnError
+	}
+
+	result, err := pm.GetCatalog("db", "blob")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+}
+
+func TestUnit_WriteCatalog_WritesCatalog(t *testing.T) {
+	dataLocation := "dataLocation"
+	db := "db"
+	blob := "blob"
+	catalog := diskModels.PartitionCatalog{"hash.json": {Values: map[string]any{"col_one": "a"}, Records: 2, Pages: 1}}
+	writeFileCalled := false
+	pm := createTestPartitionManager(dataLocation)
+	pm.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, partitionCatalogFile), filePath)
+		catalogBytes, _ := json.Marshal(catalog)
+		assert.Equal(t, catalogBytes, fileData)
+		return nil
+	}
+
+	err := pm.WriteCatalog(db, blob, catalog)
+
+	assert.True(t, writeFileCalled)
+	assert.Nil(t, err)
+}
+
+func TestUnit_Truncate_TruncatesPartitions(t *testing.T) {
+	dataLocation := "location"
+	db := "db"
+	blob := "blob"
+	deleteDirCalled := false
+	createDirCalled := false
+	writeFileCalled := false
+	pm := createTestPartitionManager(dataLocation)
+	pm.deleteDirFunc = func(directory string) error {
+		deleteDirCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, partitionsDirectory), directory)
+		return nil
+	}
+	pm.createDirFunc = func(directory string) error {
+		createDirCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, partitionsDirectory), directory)
+		return nil
+	}
+	pm.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		assert.Equal(t, fmt.Sprintf("%s/%s/%s/%s", dataLocation, db, blob, partitionCatalogFile), filePath)
+		assert.Equal(t, []byte("{}"), fileData)
+		return nil
+	}
+
+	err := pm.Truncate(db, blob)
+
+	assert.True(t, deleteDirCalled)
+	assert.True(t, createDirCalled)
+	assert.True(t, writeFileCalled)
+	assert.Nil(t, err)
+}
+
+func TestUnit_Truncate_FailsOnPartitionsDirectoryCreateError(t *testing.T) {
+	writeFileCalled := false
+	pm := createTestPartitionManager("location")
+	pm.deleteDirFunc = func(directory string) error {
+		return nil
+	}
+	pm.createDirFunc = func(directory string) error {
+		return assert.AnError
+	}
+	pm.writeFileFunc = func(filePath string, fileData []byte) error {
+		writeFileCalled = true
+		return nil
+	}
+
+	err := pm.Truncate("db", "blob")
+
+	assert.False(t, writeFileCalled)
+	assert.NotNil(t, err)
+}
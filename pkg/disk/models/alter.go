@@ -0,0 +1,14 @@
+package diskModels
+
+type Alter struct {
+	Op        string   `json:"op"`
+	Key       string   `json:"key"`
+	NewKey    string   `json:"newKey,omitempty"`
+	KeyType   string   `json:"keyType,omitempty"`
+	Optional  bool     `json:"optional,omitempty"`
+	Default   any      `json:"default,omitempty"`
+	Scale     int      `json:"scale,omitempty"`
+	Values    []string `json:"values,omitempty"`
+	IndexType string   `json:"indexType,omitempty"`
+	Format    Format   `json:"format,omitempty"`
+}
@@ -1,18 +1,77 @@
 package diskModels
 
+import (
+	"slices"
+	"sort"
+)
+
 type Format map[string]FormatItem
 
 type FormatItem struct {
-	KeyType string `json:"keyType"`
+	KeyType    string   `json:"keyType"`
+	Unique     bool     `json:"unique,omitempty"`
+	UniqueWith []string `json:"uniqueWith,omitempty"`
+	Optional   bool     `json:"optional,omitempty"`
+	Default    any      `json:"default,omitempty"`
+	Scale      int      `json:"scale,omitempty"`
+	Values     []string `json:"values,omitempty"`
+	Format     Format   `json:"format,omitempty"`
+}
+
+func (f Format) GetKeys() []string {
+	keys := []string{}
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f Format) GetUniqueKeys() [][]string {
+	uniqueKeys := [][]string{}
+	for _, key := range f.GetKeys() {
+		if f[key].Unique {
+			uniqueKeys = append(uniqueKeys, append([]string{key}, f[key].UniqueWith...))
+		}
+	}
+	return uniqueKeys
+}
+
+func (f Format) IsUniqueKey(key string) bool {
+	for _, uniqueKeys := range f.GetUniqueKeys() {
+		if slices.Contains(uniqueKeys, key) {
+			return true
+		}
+	}
+	return false
 }
 
 func (f Format) ConvertToPageRecords() []PageRecord {
 	pageRecords := []PageRecord{}
-	for key, data := range f {
-		pageRecords = append(pageRecords, PageRecord{
+	for _, key := range f.GetKeys() {
+		pageRecord := PageRecord{
 			"key":      key,
-			"key_type": data.KeyType,
-		})
+			"key_type": f[key].KeyType,
+		}
+		if f[key].Optional {
+			pageRecord["optional"] = true
+		}
+		if f[key].Default != nil {
+			pageRecord["default"] = f[key].Default
+		}
+		if f[key].Scale > 0 {
+			pageRecord["scale"] = f[key].Scale
+		}
+		if f[key].Values != nil {
+			pageRecord["values"] = f[key].Values
+		}
+		if f[key].Unique {
+			pageRecord["unique"] = append([]string{key}, f[key].UniqueWith...)
+		}
+		if f[key].Format != nil {
+			pageRecord["format"] = f[key].Format.ConvertToPageRecords()
+		}
+		pageRecords = append(pageRecords, pageRecord)
 	}
 	return pageRecords
 }
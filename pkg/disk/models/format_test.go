@@ -18,3 +18,27 @@ func TestUnit_ConvertToPageRecords_ConvertsToFormatPageRecords(t *testing.T) {
 	assert.Equal(t, "key_2", pageRecords[1]["key"].(string))
 	assert.Equal(t, format["key_2"].KeyType, pageRecords[1]["key_type"].(string))
 }
+
+func TestUnit_GetUniqueKeys_GetsSingleAndCompositeKeys(t *testing.T) {
+	format := Format{
+		"email": FormatItem{KeyType: "string", Unique: true},
+		"org":   FormatItem{KeyType: "string", Unique: true, UniqueWith: []string{"name"}},
+		"name":  FormatItem{KeyType: "string"},
+		"age":   FormatItem{KeyType: "int"},
+	}
+
+	assert.Equal(t, [][]string{{"email"}, {"org", "name"}}, format.GetUniqueKeys())
+	assert.True(t, format.IsUniqueKey("name"))
+	assert.False(t, format.IsUniqueKey("age"))
+	assert.Equal(t, []string{"org", "name"}, format.ConvertToPageRecords()[3]["unique"])
+}
+
+func TestUnit_ConvertToPageRecords_ConvertsNestedFormat(t *testing.T) {
+	format := Format{
+		"address": FormatItem{KeyType: "object", Format: Format{
+			"city": FormatItem{KeyType: "string"},
+		}},
+	}
+
+	assert.Equal(t, []PageRecord{{"key": "city", "key_type": "string"}}, format.ConvertToPageRecords()[0]["format"])
+}
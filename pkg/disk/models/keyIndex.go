@@ -0,0 +1,39 @@
+package diskModels
+
+import "sort"
+
+type KeyIndexes map[string]KeyIndexItem
+
+type KeyIndexItem struct {
+	Key       string `json:"key"`
+	IndexType string `json:"indexType"`
+}
+
+type KeyIndexRecords map[string]IndexRecords
+
+func (ki KeyIndexes) ConvertToPageRecords() []PageRecord {
+	keys := []string{}
+	for key := range ki {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pageRecords := []PageRecord{}
+	for _, key := range keys {
+		pageRecords = append(pageRecords, PageRecord{
+			"key":        key,
+			"index_type": ki[key].IndexType,
+		})
+	}
+	return pageRecords
+}
+
+type OrderedIndex struct {
+	Entries []OrderedIndexEntry `json:"entries"`
+	Nulls   IndexRecords        `json:"nulls"`
+}
+
+type OrderedIndexEntry struct {
+	Value        float64 `json:"value"`
+	PageRecordId string  `json:"id"`
+	PageFile     string  `json:"page"`
+}
@@ -1,7 +1,19 @@
 package diskModels
 
+import (
+	"sort"
+	"strings"
+)
+
 type Partition struct {
-	Keys []string `json:"keys"`
+	Keys    []string                   `json:"keys"`
+	Buckets map[string]PartitionBucket `json:"buckets,omitempty"`
+}
+
+type PartitionBucket struct {
+	Type   string    `json:"type"`
+	Bounds []float64 `json:"bounds,omitempty"`
+	Period string    `json:"period,omitempty"`
 }
 
 type PartitionPages []PartitionPageItem
@@ -10,11 +22,47 @@ type PartitionPageItem struct {
 	FileName string `json:"fileName"`
 }
 
+type PartitionCatalog map[string]PartitionCatalogItem
+
+type PartitionCatalogItem struct {
+	Values  map[string]any `json:"values"`
+	Records int            `json:"records"`
+	Pages   int            `json:"pages"`
+}
+
 func (p Partition) ConvertToPageRecords() []PageRecord {
 	pageRecords := []PageRecord{}
 	for _, key := range p.Keys {
-		pageRecords = append(pageRecords, PageRecord{
+		pageRecord := PageRecord{
 			"key": key,
+		}
+		if bucket, ok := p.Buckets[key]; ok {
+			pageRecord["type"] = bucket.Type
+			if len(bucket.Bounds) > 0 {
+				pageRecord["bounds"] = bucket.Bounds
+			}
+			if bucket.Period != "" {
+				pageRecord["period"] = bucket.Period
+			}
+		}
+		pageRecords = append(pageRecords, pageRecord)
+	}
+	return pageRecords
+}
+
+func (pc PartitionCatalog) ConvertToPageRecords() []PageRecord {
+	hashKeyFiles := []string{}
+	for hashKeyFile := range pc {
+		hashKeyFiles = append(hashKeyFiles, hashKeyFile)
+	}
+	sort.Strings(hashKeyFiles)
+	pageRecords := []PageRecord{}
+	for _, hashKeyFile := range hashKeyFiles {
+		pageRecords = append(pageRecords, PageRecord{
+			"partition": strings.TrimSuffix(hashKeyFile, ".json"),
+			"values":    pc[hashKeyFile].Values,
+			"records":   pc[hashKeyFile].Records,
+			"pages":     pc[hashKeyFile].Pages,
 		})
 	}
 	return pageRecords
@@ -13,3 +13,33 @@ func TestUnit_ConvertToPageRecords_ConvertsToPartitionPageRecords(t *testing.T)
 	assert.Equal(t, "key_1", pageRecords[0]["key"].(string))
 	assert.Equal(t, "key_2", pageRecords[1]["key"].(string))
 }
+
+func TestUnit_ConvertToPageRecords_ConvertsPartitionBuckets(t *testing.T) {
+	partition := Partition{
+		Keys: []string{"region", "qty", "created"},
+		Buckets: map[string]PartitionBucket{
+			"qty":     {Type: "range", Bounds: []float64{10, 100}},
+			"created": {Type: "time", Period: "day"},
+		},
+	}
+	pageRecords := partition.ConvertToPageRecords()
+
+	assert.Equal(t, []PageRecord{
+		{"key": "region"},
+		{"key": "qty", "type": "range", "bounds": []float64{10, 100}},
+		{"key": "created", "type": "time", "period": "day"},
+	}, pageRecords)
+}
+
+func TestUnit_ConvertToPageRecords_ConvertsPartitionCatalog(t *testing.T) {
+	catalog := PartitionCatalog{
+		"b.json": {Values: map[string]any{"region": "us"}, Records: 1, Pages: 1},
+		"a.json": {Values: map[string]any{"region": "eu"}, Records: 12, Pages: 2},
+	}
+	pageRecords := catalog.ConvertToPageRecords()
+
+	assert.Equal(t, []PageRecord{
+		{"partition": "a", "values": map[string]any{"region": "eu"}, "records": 12, "pages": 2},
+		{"partition": "b", "values": map[string]any{"region": "us"}, "records": 1, "pages": 1},
+	}, pageRecords)
+}
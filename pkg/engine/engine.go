@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"errors"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/managers"
+	"os"
+	"path/filepath"
+)
+
+type Engine struct {
+	QueryManager queryManagers.QueryManager
+	UserManager  systemManagers.UserManager
+	LogManager   systemManagers.LogManager
+}
+
+type Config struct {
+	DataLocation string
+	RootPassword string
+	DataCaching  bool
+}
+
+func Start(config Config) (Engine, error) {
+	if config.DataLocation == "" {
+		return Engine{}, errors.New("data location is required")
+	}
+	if config.RootPassword == "" {
+		return Engine{}, errors.New("root password is required")
+	}
+	if err := os.MkdirAll(config.DataLocation, 0755); err != nil {
+		return Engine{}, err
+	}
+	dbMap := memoryModels.NewDBMap(config.DataLocation, config.DataCaching)
+	operationManager := memoryManagers.CreateOperationManager(&dbMap)
+	if err := system.InitDB(operationManager); err != nil {
+		return Engine{}, err
+	}
+	userManager := systemManagers.CreateUserManager(operationManager)
+	userManager.InitRoot(config.RootPassword)
+	logManager := systemManagers.CreateLogManager(operationManager)
+	return Engine{
+		QueryManager: queryManagers.CreateSynchronizedQueryManager(queryManagers.NewQueryManager(operationManager, userManager, logManager)),
+		UserManager:  userManager,
+		LogManager:   logManager,
+	}, nil
+}
+
+// Replay starts the source and target engines and runs the source's logged queries from version
+// from through to (0 for the latest) against the target.
+func Replay(source Config, target Config, from int, to int) (int, error) {
+	sourceLocation, err := filepath.Abs(source.DataLocation)
+	if err != nil {
+		return 0, err
+	}
+	targetLocation, err := filepath.Abs(target.DataLocation)
+	if err != nil {
+		return 0, err
+	}
+	if sourceLocation == targetLocation {
+		return 0, errors.New("replay target must be a different data location")
+	}
+	sourceEngine, err := Start(source)
+	if err != nil {
+		return 0, err
+	}
+	targetEngine, err := Start(target)
+	if err != nil {
+		return 0, err
+	}
+	return queryManagers.Replay(sourceEngine.LogManager, targetEngine.QueryManager, from, to)
+}
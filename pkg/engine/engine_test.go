@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func createBaselineSysLog(t *testing.T, dataLocation string) {
+	dbMap := memoryModels.NewDBMap(dataLocation, false)
+	operationManager := memoryManagers.CreateOperationManager(&dbMap)
+	assert.Nil(t, operationManager.CreateDB(systemConstants.DBSys))
+	assert.Nil(t, operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysLog, diskModels.Format{
+		"is_current": diskModels.FormatItem{KeyType: memoryConstants.Bool},
+		"version":    diskModels.FormatItem{KeyType: memoryConstants.Int},
+		"query_hex":  diskModels.FormatItem{KeyType: memoryConstants.String},
+	}, nil))
+	queryBytes, _ := json.Marshal(queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "old"})
+	_, err := operationManager.AddRecords(systemConstants.DBSys, systemConstants.BlobSysLog, []diskModels.PageRecord{{
+		"is_current": true,
+		"version":    1,
+		"query_hex":  hex.EncodeToString(queryBytes),
+	}})
+	assert.Nil(t, err)
+}
+
+func TestIntegration_Start_LogsMutationsOnBaselineSysLog(t *testing.T) {
+	dataLocation := t.TempDir()
+	createBaselineSysLog(t, dataLocation)
+
+	engineObj, err := Start(Config{DataLocation: dataLocation, RootPassword: "secret"})
+	assert.Nil(t, err)
+	root, err := engineObj.UserManager.Authenticate("root", "secret")
+	assert.Nil(t, err)
+
+	queryResult := engineObj.QueryManager.Query(queryModels.Query{
+		Action: queryConstants.ActionCreate,
+		On:     queryConstants.OnDB,
+		Name:   "shop",
+	}, root)
+
+	assert.Equal(t, "", queryResult.ErrorMessage)
+	logs, err := engineObj.LogManager.GetLogs([]memoryModels.FilterItem{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, "", logs[0].User)
+	assert.Equal(t, "root", logs[1].User)
+	assert.NotEqual(t, "", logs[1].Timestamp)
+	assert.Equal(t, "shop", logs[1].Query.Name)
+}
+
+func TestIntegration_Replay_ReplaysLogRangeIntoFreshDataLocation(t *testing.T) {
+	source := Config{DataLocation: t.TempDir(), RootPassword: "secret"}
+	target := Config{DataLocation: t.TempDir(), RootPassword: "secret"}
+	engineObj, err := Start(source)
+	assert.Nil(t, err)
+	root, err := engineObj.UserManager.Authenticate("root", "secret")
+	assert.Nil(t, err)
+	for _, query := range []queryModels.Query{
+		{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "shop"},
+		{Action: queryConstants.ActionCreate, On: queryConstants.OnBlob, Name: "shop.orders", With: queryModels.With{Format: map[string]string{"code": "string"}}},
+		{Action: queryConstants.ActionCreate, On: queryConstants.OnData, Name: "shop.orders", With: queryModels.With{Records: []diskModels.PageRecord{{"code": "a"}}}},
+		{Action: queryConstants.ActionCreate, On: queryConstants.OnData, Name: "shop.orders", With: queryModels.With{Records: []diskModels.PageRecord{{"code": "b"}}}},
+	} {
+		assert.Equal(t, "", engineObj.QueryManager.Query(query, root).ErrorMessage)
+	}
+
+	replayed, err := Replay(source, target, 1, 3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, replayed)
+	targetEngine, err := Start(target)
+	assert.Nil(t, err)
+	queryResult := targetEngine.QueryManager.Query(queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: "shop.orders"}, root)
+	assert.Equal(t, "", queryResult.ErrorMessage)
+	assert.Equal(t, 1, len(queryResult.Records))
+	assert.Equal(t, "a", queryResult.Records[0]["code"])
+	logs, err := targetEngine.LogManager.GetLogs([]memoryModels.FilterItem{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(logs))
+	sourceResult := engineObj.QueryManager.Query(queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: "shop.orders"}, root)
+	assert.Equal(t, 2, len(sourceResult.Records))
+}
+
+func TestUnit_Replay_FailsOnSameDataLocation(t *testing.T) {
+	dataLocation := t.TempDir()
+
+	_, err := Replay(Config{DataLocation: dataLocation, RootPassword: "secret"}, Config{DataLocation: dataLocation + "/.", RootPassword: "secret"}, 1, 0)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "different data location")
+}
+
+func TestIntegration_Start_FailsOnDuplicateBaselineSysUsers(t *testing.T) {
+	dataLocation := t.TempDir()
+	dbMap := memoryModels.NewDBMap(dataLocation, false)
+	operationManager := memoryManagers.CreateOperationManager(&dbMap)
+	assert.Nil(t, operationManager.CreateDB(systemConstants.DBSys))
+	assert.Nil(t, operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysUser, diskModels.Format{
+		"user":       diskModels.FormatItem{KeyType: memoryConstants.String},
+		"password":   diskModels.FormatItem{KeyType: memoryConstants.String},
+		"permission": diskModels.FormatItem{KeyType: memoryConstants.String},
+	}, nil))
+	records, err := operationManager.AddRecords(systemConstants.DBSys, systemConstants.BlobSysUser, []diskModels.PageRecord{
+		{"user": "root", "password": "one", "permission": systemConstants.PermissionSuper},
+		{"user": "root", "password": "two", "permission": systemConstants.PermissionSuper},
+		{"user": "bob", "password": "three", "permission": systemConstants.PermissionSuper},
+	})
+	assert.Nil(t, err)
+
+	_, err = Start(Config{DataLocation: dataLocation, RootPassword: "secret"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "sys_user has duplicate users")
+	assert.Contains(t, err.Error(), records[0][memoryConstants.IdKey])
+	assert.Contains(t, err.Error(), records[1][memoryConstants.IdKey])
+	assert.NotContains(t, err.Error(), records[2][memoryConstants.IdKey])
+	assert.NotContains(t, err.Error(), "bob")
+}
@@ -1,5 +1,10 @@
 package memoryConstants
 
+import (
+	"slices"
+	"strings"
+)
+
 const (
 	String   = "string"
 	Int      = "int"
@@ -7,6 +12,16 @@ const (
 	DateTime = "datetime"
 	Date     = "date"
 	Float    = "float"
+	Uuid     = "uuid"
+	Decimal  = "decimal"
+	Enum     = "enum"
+	Bytes    = "bytes"
+	Object   = "object"
+	Array    = "array"
+
+	DecimalMaxScale = 18
+
+	KeySeparator = "."
 
 	SearchThreadCount = 10
 
@@ -26,15 +41,248 @@ const (
 	MaxIndexSize = 5024 * 100
 
 	IdKey = "_id"
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	FilterAnd = "AND"
+	FilterOr  = "OR"
+	FilterNot = "NOT"
+
+	OpEqual        = "="
+	OpNotEqual     = "!="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpIn           = "IN"
+	OpNotIn        = "NOT IN"
+	OpBetween      = "BETWEEN"
+	OpIsNull       = "IS NULL"
+	OpIsNotNull    = "IS NOT NULL"
+	OpContains     = "CONTAINS"
+	OpContainsCS   = "CONTAINS_CS"
+	OpPrefix       = "PREFIX"
+	OpPrefixCS     = "PREFIX_CS"
+	OpSuffix       = "SUFFIX"
+	OpSuffixCS     = "SUFFIX_CS"
+	OpRegex        = "REGEX"
+	OpLike         = "LIKE"
+	OpILike        = "ILIKE"
+	OpMatch        = "MATCH"
+	OpAny          = "ANY"
+
+	AggregateCount = "count"
+	AggregateSum   = "sum"
+	AggregateAvg   = "avg"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+
+	KeyIndexHash    = "hash"
+	KeyIndexOrdered = "ordered"
+	KeyIndexText    = "text"
+
+	AlterAdd    = "add"
+	AlterDrop   = "drop"
+	AlterRename = "rename"
+	AlterType   = "type"
+	AlterUnique = "unique"
+
+	PartitionRange = "range"
+	PartitionTime  = "time"
+
+	PartitionMaxBounds = 1000
+
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+
+	MigrationUTCDateTime       = "utc_datetime"
+	MigrationUniqueKeyIndexes  = "unique_key_indexes"
+	MigrationShardedKeyIndexes = "sharded_key_indexes"
 )
 
-func GetFormatTypes() []string {
+func GetMigrations() []string {
 	return []string{
+		MigrationUTCDateTime,
+		MigrationUniqueKeyIndexes,
+		MigrationShardedKeyIndexes,
+	}
+}
+
+func GetFormatTypes() []string {
+	formatTypes := []string{
 		String,
 		Int,
 		Bool,
 		DateTime,
 		Date,
 		Float,
+		Uuid,
+		Decimal,
+		Enum,
+		Bytes,
+		Object,
+	}
+	for _, formatType := range formatTypes {
+		formatTypes = append(formatTypes, GetArrayType(formatType))
+	}
+	return formatTypes
+}
+
+func GetArrayType(elementType string) string {
+	return Array + "<" + elementType + ">"
+}
+
+func GetArrayElementType(keyType string) (string, bool) {
+	if !strings.HasPrefix(keyType, Array+"<") || !strings.HasSuffix(keyType, ">") {
+		return "", false
+	}
+	return keyType[len(Array)+1 : len(keyType)-1], true
+}
+
+func GetBaseType(keyType string) string {
+	if elementType, ok := GetArrayElementType(keyType); ok {
+		return elementType
+	}
+	return keyType
+}
+
+func IsNestedType(keyType string) bool {
+	_, isArray := GetArrayElementType(keyType)
+	return keyType == Object || isArray
+}
+
+func HasNestedFormat(keyType string) bool {
+	elementType, _ := GetArrayElementType(keyType)
+	return keyType == Object || elementType == Object
+}
+
+func GetFilterOps(keyType string) []string {
+	ops := []string{
+		OpEqual,
+		OpNotEqual,
+		OpIn,
+		OpNotIn,
+		OpIsNull,
+		OpIsNotNull,
+	}
+	orderedOps := []string{
+		OpGreater,
+		OpGreaterEqual,
+		OpLess,
+		OpLessEqual,
+		OpBetween,
+	}
+	patternOps := []string{
+		OpRegex,
+		OpLike,
+		OpILike,
+	}
+	switch keyType {
+	case String:
+		ops = append(ops, orderedOps...)
+		ops = append(ops, patternOps...)
+		return append(ops, OpContains, OpContainsCS, OpPrefix, OpPrefixCS, OpSuffix, OpSuffixCS, OpMatch)
+	case Date:
+		ops = append(ops, orderedOps...)
+		return append(ops, patternOps...)
+	case Int, Float, DateTime, Decimal:
+		return append(ops, orderedOps...)
+	case Bool, Uuid, Enum, Bytes:
+		return ops
+	case Object:
+		return []string{OpIsNull, OpIsNotNull}
+	}
+	elementType, ok := GetArrayElementType(keyType)
+	if !ok || !slices.Contains(GetFormatTypes(), keyType) {
+		return []string{}
+	}
+	if elementType == Object {
+		return []string{OpIsNull, OpIsNotNull}
+	}
+	return []string{OpIsNull, OpIsNotNull, OpContains, OpAny}
+}
+
+func GetAggregateOps() []string {
+	return []string{
+		AggregateCount,
+		AggregateSum,
+		AggregateAvg,
+		AggregateMin,
+		AggregateMax,
+	}
+}
+
+func GetAggregateTypes(op string) []string {
+	switch op {
+	case AggregateCount:
+		return GetFormatTypes()
+	case AggregateSum, AggregateAvg:
+		return []string{Int, Float, Decimal}
+	case AggregateMin, AggregateMax:
+		return []string{Int, Float, Date, DateTime, Decimal}
+	default:
+		return []string{}
+	}
+}
+
+func GetKeyIndexTypes(keyType string) []string {
+	switch keyType {
+	case Int, Float, Date, DateTime:
+		return []string{KeyIndexHash, KeyIndexOrdered}
+	case String:
+		return []string{KeyIndexHash, KeyIndexText}
+	}
+	if !slices.Contains(GetFormatTypes(), keyType) || IsNestedType(keyType) {
+		return []string{}
+	}
+	return []string{KeyIndexHash}
+}
+
+func GetAlterOps() []string {
+	return []string{
+		AlterAdd,
+		AlterDrop,
+		AlterRename,
+		AlterType,
+		AlterUnique,
+	}
+}
+
+func GetPartitionTypes() []string {
+	return []string{
+		PartitionRange,
+		PartitionTime,
+	}
+}
+
+func GetPartitionKeyTypes(partitionType string) []string {
+	switch partitionType {
+	case PartitionRange:
+		return []string{Int, Float, Decimal}
+	case PartitionTime:
+		return []string{Date, DateTime}
+	default:
+		return []string{}
+	}
+}
+
+func GetPartitionPeriods() []string {
+	return []string{
+		PeriodDay,
+		PeriodMonth,
+	}
+}
+
+func GetWidenedTypes(keyType string) []string {
+	switch keyType {
+	case Int:
+		return []string{Float, String}
+	case Date:
+		return []string{DateTime, String}
+	case Float, Bool, DateTime:
+		return []string{String}
+	default:
+		return []string{}
 	}
 }
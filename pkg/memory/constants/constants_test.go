@@ -13,5 +13,73 @@ func TestUnit_GetFormatTypes_GetsFormatTypes(t *testing.T) {
 		DateTime,
 		Date,
 		Float,
+		Uuid,
+		Decimal,
+		Enum,
+		Bytes,
+		Object,
+		"array<string>",
+		"array<int>",
+		"array<bool>",
+		"array<datetime>",
+		"array<date>",
+		"array<float>",
+		"array<uuid>",
+		"array<decimal>",
+		"array<enum>",
+		"array<bytes>",
+		"array<object>",
 	})
 }
+
+func TestUnit_GetArrayElementType_GetsElementType(t *testing.T) {
+	elementType, ok := GetArrayElementType(GetArrayType(Int))
+	assert.True(t, ok)
+	assert.Equal(t, Int, elementType)
+	_, ok = GetArrayElementType(Int)
+	assert.False(t, ok)
+	assert.True(t, HasNestedFormat(GetArrayType(Object)))
+	assert.False(t, HasNestedFormat(GetArrayType(String)))
+	assert.True(t, IsNestedType(Object))
+	assert.Equal(t, Decimal, GetBaseType(GetArrayType(Decimal)))
+	assert.Equal(t, Enum, GetBaseType(Enum))
+}
+
+func TestUnit_GetFilterOps_GetsOpsByFormatType(t *testing.T) {
+	assert.Contains(t, GetFilterOps(String), OpContains)
+	assert.Contains(t, GetFilterOps(String), OpMatch)
+	assert.NotContains(t, GetFilterOps(Date), OpMatch)
+	assert.Contains(t, GetFilterOps(Int), OpBetween)
+	assert.NotContains(t, GetFilterOps(Int), OpContains)
+	assert.Contains(t, GetFilterOps(Bool), OpIn)
+	assert.NotContains(t, GetFilterOps(Bool), OpGreater)
+	assert.Empty(t, GetFilterOps("unknown"))
+	assert.Contains(t, GetFilterOps(Decimal), OpBetween)
+	assert.NotContains(t, GetFilterOps(Uuid), OpGreater)
+	assert.Equal(t, GetFilterOps(Bool), GetFilterOps(Enum))
+	assert.Equal(t, []string{OpIsNull, OpIsNotNull, OpContains, OpAny}, GetFilterOps(GetArrayType(String)))
+	assert.Equal(t, []string{OpIsNull, OpIsNotNull}, GetFilterOps(GetArrayType(Object)))
+	assert.Empty(t, GetFilterOps("array<array<int>>"))
+}
+
+func TestUnit_GetKeyIndexTypes_GetsIndexTypesByFormatType(t *testing.T) {
+	assert.Equal(t, []string{KeyIndexHash, KeyIndexText}, GetKeyIndexTypes(String))
+	assert.Equal(t, []string{KeyIndexHash}, GetKeyIndexTypes(Bool))
+	assert.Equal(t, []string{KeyIndexHash, KeyIndexOrdered}, GetKeyIndexTypes(DateTime))
+	assert.Empty(t, GetKeyIndexTypes("unknown"))
+	assert.Empty(t, GetKeyIndexTypes(Object))
+	assert.Empty(t, GetKeyIndexTypes(GetArrayType(Int)))
+}
+
+func TestUnit_GetWidenedTypes_GetsWidenedTypesByFormatType(t *testing.T) {
+	assert.Equal(t, []string{Float, String}, GetWidenedTypes(Int))
+	assert.Equal(t, []string{DateTime, String}, GetWidenedTypes(Date))
+	assert.Equal(t, []string{String}, GetWidenedTypes(Bool))
+	assert.Empty(t, GetWidenedTypes(String))
+}
+
+func TestUnit_GetPartitionKeyTypes_GetsKeyTypesByPartitionType(t *testing.T) {
+	assert.Equal(t, []string{Int, Float, Decimal}, GetPartitionKeyTypes(PartitionRange))
+	assert.Equal(t, []string{Date, DateTime}, GetPartitionKeyTypes(PartitionTime))
+	assert.Empty(t, GetPartitionKeyTypes("hash"))
+}
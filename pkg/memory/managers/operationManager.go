@@ -12,14 +12,24 @@ type OperationManager interface {
 	GetDBs() []diskModels.PageRecord
 	CreateBlob(db string, blob string, format diskModels.Format, partition *diskModels.Partition) error
 	DeleteBlob(db string, blob string) error
+	AlterBlob(db string, blob string, alter diskModels.Alter) error
+	GetAlterState(db string, blob string) (memoryModels.AlterState, bool, error)
+	WaitAlterBlob(db string, blob string) error
+	GetFormat(db string, blob string) (diskModels.Format, error)
+	CreateIndex(db string, blob string, key string, indexType string) error
+	DeleteIndex(db string, blob string, key string) error
 	GetBlobs(db string) []diskModels.PageRecord
-	GetRecordByIndex(db string, blob string, index string) (diskModels.PageRecord, error)
+	GetPartitions(db string, blob string) ([]diskModels.PageRecord, error)
+	GetRecordByIndex(db string, blob string, index string, getOperationParams memoryModels.GetOperationParams) (diskModels.PageRecord, error)
 	GetRecords(db string, blob string, filterItems []memoryModels.FilterItem, searchPartition memoryModels.SearchPartition, getOperationParams memoryModels.GetOperationParams) ([]diskModels.PageRecord, error)
+	AggregateRecords(db string, blob string, filterItems []memoryModels.FilterItem, searchPartition memoryModels.SearchPartition, aggregateParams memoryModels.AggregateParams) ([]diskModels.PageRecord, error)
 	AddRecords(db string, blob string, records []diskModels.PageRecord) ([]diskModels.PageRecord, error)
 	UpdateRecordByIndex(db string, blob string, index string, updateRecord diskModels.PageRecord) error
 	UpdateRecords(db string, blob string, filterItems []memoryModels.FilterItem, searchPartition memoryModels.SearchPartition, updateRecord diskModels.PageRecord) error
 	DeleteRecordByIndex(db string, blob string, index string) error
 	DeleteRecords(db string, blob string, filterItems []memoryModels.FilterItem, searchPartition memoryModels.SearchPartition) error
+	DropPartition(db string, blob string, searchPartition memoryModels.SearchPartition) error
+	TruncateBlob(db string, blob string) error
 	DBExists(db string) bool
 	BlobExists(db string, blob string) bool
 }
@@ -64,6 +74,79 @@ func (om *operationManager) DeleteBlob(db string, blob string) error {
 	return blobMap.Delete(blob)
 }
 
+func (om *operationManager) AlterBlob(db string, blob string, alter diskModels.Alter) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	return blobObj.Alter(alter)
+}
+
+func (om *operationManager) GetAlterState(db string, blob string) (memoryModels.AlterState, bool, error) {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return memoryModels.AlterState{}, false, err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return memoryModels.AlterState{}, false, err
+	}
+	alterState, ok := blobObj.GetAlterState()
+	return alterState, ok, nil
+}
+
+func (om *operationManager) WaitAlterBlob(db string, blob string) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	return blobObj.WaitAlter()
+}
+
+func (om *operationManager) GetFormat(db string, blob string) (diskModels.Format, error) {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return nil, err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return nil, err
+	}
+	return blobObj.GetFormat(), nil
+}
+
+func (om *operationManager) CreateIndex(db string, blob string, key string, indexType string) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	return blobObj.CreateKeyIndex(key, indexType)
+}
+
+func (om *operationManager) DeleteIndex(db string, blob string, key string) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	return blobObj.DeleteKeyIndex(key)
+}
+
 func (om *operationManager) GetBlobs(db string) []diskModels.PageRecord {
 	blobMap, err := om.dbMap.GetBlobMap(db)
 	if err != nil {
@@ -72,7 +155,19 @@ func (om *operationManager) GetBlobs(db string) []diskModels.PageRecord {
 	return blobMap.ConvertToPageRecords()
 }
 
-func (om *operationManager) GetRecordByIndex(db string, blob string, index string) (diskModels.PageRecord, error) {
+func (om *operationManager) GetPartitions(db string, blob string) ([]diskModels.PageRecord, error) {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return nil, err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return nil, err
+	}
+	return blobObj.GetPartitions()
+}
+
+func (om *operationManager) GetRecordByIndex(db string, blob string, index string, getOperationParams memoryModels.GetOperationParams) (diskModels.PageRecord, error) {
 	blobMap, err := om.dbMap.GetBlobMap(db)
 	if err != nil {
 		return diskModels.PageRecord{}, err
@@ -81,7 +176,7 @@ func (om *operationManager) GetRecordByIndex(db string, blob string, index strin
 	if err != nil {
 		return diskModels.PageRecord{}, err
 	}
-	pageRecordsMap, err := blobObj.GetByRecordId(index)
+	pageRecordsMap, err := blobObj.GetByRecordId(index, getOperationParams)
 	if err != nil {
 		return diskModels.PageRecord{}, err
 	}
@@ -102,20 +197,32 @@ func (om *operationManager) GetRecords(db string, blob string, filterItems []mem
 		return nil, err
 	}
 	if !blobObj.IsPartition() {
-		pageRecordsMap, err := blobObj.GetFullScan(filterItems)
+		pageRecordItems, err := blobObj.GetFullScan(filterItems, getOperationParams)
 		if err != nil {
 			return nil, err
 		}
-		return om.buildPageRecords(pageRecordsMap), nil
+		return om.buildPageRecordItems(pageRecordItems), nil
 	} else {
-		pageRecordsMap, err := blobObj.GetByPartition(searchPartition, filterItems)
+		pageRecordItems, err := blobObj.GetByPartition(searchPartition, filterItems, getOperationParams)
 		if err != nil {
 			return nil, err
 		}
-		return om.buildPageRecords(pageRecordsMap), nil
+		return om.buildPageRecordItems(pageRecordItems), nil
 	}
 }
 
+func (om *operationManager) AggregateRecords(db string, blob string, filterItems []memoryModels.FilterItem, searchPartition memoryModels.SearchPartition, aggregateParams memoryModels.AggregateParams) ([]diskModels.PageRecord, error) {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return nil, err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return nil, err
+	}
+	return blobObj.Aggregate(searchPartition, filterItems, aggregateParams)
+}
+
 func (om *operationManager) AddRecords(db string, blob string, records []diskModels.PageRecord) ([]diskModels.PageRecord, error) {
 	blobMap, err := om.dbMap.GetBlobMap(db)
 	if err != nil {
@@ -196,6 +303,31 @@ func (om *operationManager) DeleteRecords(db string, blob string, filterItems []
 	return deleteError
 }
 
+func (om *operationManager) DropPartition(db string, blob string, searchPartition memoryModels.SearchPartition) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	_, err = blobObj.DropPartition(searchPartition)
+	return err
+}
+
+func (om *operationManager) TruncateBlob(db string, blob string) error {
+	blobMap, err := om.dbMap.GetBlobMap(db)
+	if err != nil {
+		return err
+	}
+	blobObj, err := blobMap.Get(blob)
+	if err != nil {
+		return err
+	}
+	return blobObj.Truncate()
+}
+
 func (om *operationManager) DBExists(db string) bool {
 	_, err := om.dbMap.GetBlobMap(db)
 	return err == nil
@@ -224,3 +356,16 @@ func (om *operationManager) buildPageRecords(pageRecordsMap memoryModels.PageRec
 	}
 	return formattedPageRecords
 }
+
+func (om *operationManager) buildPageRecordItems(pageRecordItems memoryModels.PageRecordItems) []diskModels.PageRecord {
+	formattedPageRecords := []diskModels.PageRecord{}
+	for _, pageRecordItem := range pageRecordItems {
+		newPageRecord := diskModels.PageRecord{}
+		for key, value := range pageRecordItem.PageRecord {
+			newPageRecord[key] = value
+		}
+		newPageRecord[memoryConstants.IdKey] = pageRecordItem.PageRecordId
+		formattedPageRecords = append(formattedPageRecords, newPageRecord)
+	}
+	return formattedPageRecords
+}
@@ -0,0 +1,253 @@
+package memoryModels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"math/big"
+	"slices"
+	"sort"
+)
+
+type AggregateItem struct {
+	Op  string `json:"op,required"`
+	Key string `json:"key,omitempty"`
+	As  string `json:"as,omitempty"`
+}
+
+type AggregateParams struct {
+	Aggregates []AggregateItem `json:"aggregates"`
+	GroupBy    []string        `json:"groupBy"`
+}
+
+type AggregateGroups map[string]*aggregateGroup
+
+type aggregateGroup struct {
+	groupValues []any
+	counts      []int
+	intSums     []int
+	floatSums   []float64
+	decimalSums []*big.Rat
+	mins        []any
+	maxes       []any
+}
+
+func (ai *AggregateItem) GetName() string {
+	if ai.As != "" {
+		return ai.As
+	}
+	if ai.Key == "" {
+		return ai.Op
+	}
+	return fmt.Sprintf("%s_%s", ai.Op, ai.Key)
+}
+
+func (ap *AggregateParams) Validate(format diskModels.Format) error {
+	if len(ap.Aggregates) == 0 {
+		return errors.New("at least one aggregate is required")
+	}
+	names := []string{}
+	for _, groupKey := range ap.GroupBy {
+		if _, ok := format[groupKey]; !ok {
+			return errors.New(fmt.Sprintf("group by key %s does not exist in format", groupKey))
+		}
+		names = append(names, groupKey)
+	}
+	for _, aggregateItem := range ap.Aggregates {
+		if !slices.Contains(memoryConstants.GetAggregateOps(), aggregateItem.Op) {
+			return errors.New(fmt.Sprintf("aggregate %s is not known", aggregateItem.Op))
+		}
+		if aggregateItem.Key == "" && aggregateItem.Op != memoryConstants.AggregateCount {
+			return errors.New(fmt.Sprintf("aggregate %s requires a key", aggregateItem.Op))
+		}
+		if aggregateItem.Key != "" {
+			formatItem, ok := format[aggregateItem.Key]
+			if !ok {
+				return errors.New(fmt.Sprintf("aggregate key %s does not exist in format", aggregateItem.Key))
+			}
+			if !slices.Contains(memoryConstants.GetAggregateTypes(aggregateItem.Op), formatItem.KeyType) {
+				return errors.New(fmt.Sprintf("aggregate %s not allowed on key %s of type %s", aggregateItem.Op, aggregateItem.Key, formatItem.KeyType))
+			}
+		}
+		if slices.Contains(names, aggregateItem.GetName()) {
+			return errors.New(fmt.Sprintf("aggregate name %s is used more than once", aggregateItem.GetName()))
+		}
+		names = append(names, aggregateItem.GetName())
+	}
+	return nil
+}
+
+func (ap *AggregateParams) Accumulate(groups AggregateGroups, record diskModels.PageRecord, format diskModels.Format) {
+	groupValues := []any{}
+	for _, groupKey := range ap.GroupBy {
+		groupValues = append(groupValues, normalizeFormatValue(record[groupKey], format[groupKey].KeyType))
+	}
+	groupKeyBytes, _ := json.Marshal(groupValues)
+	group, ok := groups[string(groupKeyBytes)]
+	if !ok {
+		group = ap.newGroup(groupValues)
+		groups[string(groupKeyBytes)] = group
+	}
+	for i, aggregateItem := range ap.Aggregates {
+		if aggregateItem.Key == "" {
+			group.counts[i]++
+			continue
+		}
+		keyType := format[aggregateItem.Key].KeyType
+		value := normalizeFormatValue(record[aggregateItem.Key], keyType)
+		if value == nil {
+			continue
+		}
+		group.counts[i]++
+		switch aggregateItem.Op {
+		case memoryConstants.AggregateSum, memoryConstants.AggregateAvg:
+			switch keyType {
+			case memoryConstants.Int:
+				group.intSums[i] += value.(int)
+			case memoryConstants.Decimal:
+				decimal, _ := memoryUtils.ConvertToDecimal(value)
+				group.decimalSums[i].Add(group.decimalSums[i], decimal)
+			default:
+				group.floatSums[i] += value.(float64)
+			}
+		case memoryConstants.AggregateMin:
+			if group.mins[i] == nil || compareFormatValues(value, group.mins[i], keyType) < 0 {
+				group.mins[i] = value
+			}
+		case memoryConstants.AggregateMax:
+			if group.maxes[i] == nil || compareFormatValues(value, group.maxes[i], keyType) > 0 {
+				group.maxes[i] = value
+			}
+		}
+	}
+}
+
+func (ap *AggregateParams) Merge(target AggregateGroups, source AggregateGroups, format diskModels.Format) {
+	for groupKey, sourceGroup := range source {
+		targetGroup, ok := target[groupKey]
+		if !ok {
+			target[groupKey] = sourceGroup
+			continue
+		}
+		for i, aggregateItem := range ap.Aggregates {
+			targetGroup.counts[i] += sourceGroup.counts[i]
+			targetGroup.intSums[i] += sourceGroup.intSums[i]
+			targetGroup.floatSums[i] += sourceGroup.floatSums[i]
+			targetGroup.decimalSums[i].Add(targetGroup.decimalSums[i], sourceGroup.decimalSums[i])
+			if aggregateItem.Key == "" {
+				continue
+			}
+			keyType := format[aggregateItem.Key].KeyType
+			if sourceGroup.mins[i] != nil && (targetGroup.mins[i] == nil || compareFormatValues(sourceGroup.mins[i], targetGroup.mins[i], keyType) < 0) {
+				targetGroup.mins[i] = sourceGroup.mins[i]
+			}
+			if sourceGroup.maxes[i] != nil && (targetGroup.maxes[i] == nil || compareFormatValues(sourceGroup.maxes[i], targetGroup.maxes[i], keyType) > 0) {
+				targetGroup.maxes[i] = sourceGroup.maxes[i]
+			}
+		}
+	}
+}
+
+func (ap *AggregateParams) ConvertToPageRecords(groups AggregateGroups, format diskModels.Format) []diskModels.PageRecord {
+	if len(groups) == 0 && len(ap.GroupBy) == 0 {
+		groups = AggregateGroups{"": ap.newGroup([]any{})}
+	}
+	groupKeys := []string{}
+	for groupKey := range groups {
+		groupKeys = append(groupKeys, groupKey)
+	}
+	sort.Strings(groupKeys)
+	pageRecords := []diskModels.PageRecord{}
+	for _, groupKey := range groupKeys {
+		group := groups[groupKey]
+		pageRecord := diskModels.PageRecord{}
+		for i, key := range ap.GroupBy {
+			pageRecord[key] = group.groupValues[i]
+		}
+		for i, aggregateItem := range ap.Aggregates {
+			pageRecord[aggregateItem.GetName()] = group.getResult(i, aggregateItem, format)
+		}
+		pageRecords = append(pageRecords, pageRecord)
+	}
+	return pageRecords
+}
+
+func (ap *AggregateParams) newGroup(groupValues []any) *aggregateGroup {
+	group := &aggregateGroup{
+		groupValues: groupValues,
+		counts:      make([]int, len(ap.Aggregates)),
+		intSums:     make([]int, len(ap.Aggregates)),
+		floatSums:   make([]float64, len(ap.Aggregates)),
+		decimalSums: make([]*big.Rat, len(ap.Aggregates)),
+		mins:        make([]any, len(ap.Aggregates)),
+		maxes:       make([]any, len(ap.Aggregates)),
+	}
+	for i := range group.decimalSums {
+		group.decimalSums[i] = new(big.Rat)
+	}
+	return group
+}
+
+func (ag *aggregateGroup) getResult(index int, aggregateItem AggregateItem, format diskModels.Format) any {
+	switch aggregateItem.Op {
+	case memoryConstants.AggregateCount:
+		return ag.counts[index]
+	case memoryConstants.AggregateSum:
+		if ag.counts[index] == 0 {
+			return nil
+		}
+		switch format[aggregateItem.Key].KeyType {
+		case memoryConstants.Int:
+			return ag.intSums[index]
+		case memoryConstants.Decimal:
+			return ag.decimalSums[index].FloatString(format[aggregateItem.Key].Scale)
+		}
+		return ag.floatSums[index]
+	case memoryConstants.AggregateAvg:
+		if ag.counts[index] == 0 {
+			return nil
+		}
+		switch format[aggregateItem.Key].KeyType {
+		case memoryConstants.Int:
+			return float64(ag.intSums[index]) / float64(ag.counts[index])
+		case memoryConstants.Decimal:
+			average := new(big.Rat).Quo(ag.decimalSums[index], new(big.Rat).SetInt64(int64(ag.counts[index])))
+			return average.FloatString(format[aggregateItem.Key].Scale)
+		}
+		return ag.floatSums[index] / float64(ag.counts[index])
+	case memoryConstants.AggregateMin:
+		return ag.mins[index]
+	case memoryConstants.AggregateMax:
+		return ag.maxes[index]
+	default:
+		return nil
+	}
+}
+
+func normalizeFormatValue(value any, keyType string) any {
+	if value == nil {
+		return nil
+	}
+	switch keyType {
+	case memoryConstants.Int:
+		if converted, err := memoryUtils.ConvertToInt(value); err == nil {
+			return converted
+		}
+		return nil
+	case memoryConstants.Float:
+		if converted, err := memoryUtils.ConvertToFloat64(value); err == nil {
+			return converted
+		}
+		return nil
+	case memoryConstants.Decimal:
+		if _, err := memoryUtils.ConvertToDecimal(value); err == nil {
+			return value
+		}
+		return nil
+	default:
+		return value
+	}
+}
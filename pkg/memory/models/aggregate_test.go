@@ -0,0 +1,110 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func createTestAggregateFormat() diskModels.Format {
+	return diskModels.Format{
+		"status": diskModels.FormatItem{KeyType: memoryConstants.String},
+		"count":  diskModels.FormatItem{KeyType: memoryConstants.Int},
+		"price":  diskModels.FormatItem{KeyType: memoryConstants.Float},
+		"day":    diskModels.FormatItem{KeyType: memoryConstants.Date},
+	}
+}
+
+func TestUnit_Validate_FailsOnInvalidAggregates(t *testing.T) {
+	invalidParams := []AggregateParams{
+		{},
+		{Aggregates: []AggregateItem{{Op: "median", Key: "count"}}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateSum}}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateSum, Key: "status"}}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateAvg, Key: "day"}}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateMax, Key: "unknown"}}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateCount}}, GroupBy: []string{"unknown"}},
+		{Aggregates: []AggregateItem{{Op: memoryConstants.AggregateCount, As: "status"}}, GroupBy: []string{"status"}},
+	}
+
+	for _, aggregateParams := range invalidParams {
+		assert.NotNil(t, aggregateParams.Validate(createTestAggregateFormat()), "%+v", aggregateParams)
+	}
+}
+
+func TestUnit_Validate_AllowsMinMaxOnDate(t *testing.T) {
+	aggregateParams := AggregateParams{Aggregates: []AggregateItem{
+		{Op: memoryConstants.AggregateMin, Key: "day"},
+		{Op: memoryConstants.AggregateMax, Key: "day"},
+	}}
+
+	err := aggregateParams.Validate(createTestAggregateFormat())
+
+	assert.Nil(t, err)
+}
+
+func TestUnit_ConvertToPageRecords_AggregatesByGroupAcrossMergedPages(t *testing.T) {
+	format := createTestAggregateFormat()
+	aggregateParams := AggregateParams{
+		Aggregates: []AggregateItem{
+			{Op: memoryConstants.AggregateCount},
+			{Op: memoryConstants.AggregateSum, Key: "count"},
+			{Op: memoryConstants.AggregateAvg, Key: "price", As: "avg_price"},
+			{Op: memoryConstants.AggregateMin, Key: "day"},
+			{Op: memoryConstants.AggregateMax, Key: "count"},
+		},
+		GroupBy: []string{"status"},
+	}
+	pageOne := AggregateGroups{}
+	aggregateParams.Accumulate(pageOne, diskModels.PageRecord{"status": "a", "count": float64(2), "price": 1.5, "day": "2024-03-02"}, format)
+	aggregateParams.Accumulate(pageOne, diskModels.PageRecord{"status": "b", "count": 7, "price": 3.0, "day": "2024-01-01"}, format)
+	pageTwo := AggregateGroups{}
+	aggregateParams.Accumulate(pageTwo, diskModels.PageRecord{"status": "a", "count": 5, "price": 2.5, "day": "2024-02-10"}, format)
+	aggregateParams.Accumulate(pageTwo, diskModels.PageRecord{"status": "a", "count": nil, "price": 2.0, "day": "2024-04-01"}, format)
+
+	total := AggregateGroups{}
+	aggregateParams.Merge(total, pageOne, format)
+	aggregateParams.Merge(total, pageTwo, format)
+	pageRecords := aggregateParams.ConvertToPageRecords(total, format)
+
+	assert.Equal(t, []diskModels.PageRecord{
+		{"status": "a", "count": 3, "sum_count": 7, "avg_price": 2.0, "min_day": "2024-02-10", "max_count": 5},
+		{"status": "b", "count": 1, "sum_count": 7, "avg_price": 3.0, "min_day": "2024-01-01", "max_count": 7},
+	}, pageRecords)
+}
+
+func TestUnit_ConvertToPageRecords_ReturnsSingleRecordWithoutGroupsOrMatches(t *testing.T) {
+	aggregateParams := AggregateParams{Aggregates: []AggregateItem{
+		{Op: memoryConstants.AggregateCount},
+		{Op: memoryConstants.AggregateSum, Key: "count"},
+	}}
+
+	pageRecords := aggregateParams.ConvertToPageRecords(AggregateGroups{}, createTestAggregateFormat())
+
+	assert.Equal(t, []diskModels.PageRecord{{"count": 0, "sum_count": nil}}, pageRecords)
+}
+
+func TestUnit_ConvertToPageRecords_AggregatesDecimalsExactly(t *testing.T) {
+	format := diskModels.Format{
+		"amount": diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2},
+	}
+	aggregateParams := AggregateParams{Aggregates: []AggregateItem{
+		{Op: memoryConstants.AggregateSum, Key: "amount"},
+		{Op: memoryConstants.AggregateAvg, Key: "amount"},
+		{Op: memoryConstants.AggregateMax, Key: "amount"},
+	}}
+	pageOne := AggregateGroups{}
+	pageTwo := AggregateGroups{}
+	aggregateParams.Accumulate(pageOne, diskModels.PageRecord{"amount": "0.10"}, format)
+	aggregateParams.Accumulate(pageOne, diskModels.PageRecord{"amount": "0.20"}, format)
+	aggregateParams.Accumulate(pageTwo, diskModels.PageRecord{"amount": "10.00"}, format)
+	aggregateParams.Accumulate(pageTwo, diskModels.PageRecord{"amount": nil}, format)
+	aggregateParams.Merge(pageOne, pageTwo, format)
+
+	pageRecords := aggregateParams.ConvertToPageRecords(pageOne, format)
+
+	assert.Equal(t, []diskModels.PageRecord{
+		{"sum_amount": "10.30", "avg_amount": "3.43", "max_amount": "10.00"},
+	}, pageRecords)
+}
@@ -0,0 +1,353 @@
+package memoryModels
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"slices"
+	"strconv"
+	"time"
+)
+
+func (f *BlobFormatter) AlterFormat(alter diskModels.Alter) (diskModels.Format, error) {
+	if slices.Contains(f.Partition.Keys, alter.Key) {
+		return nil, errors.New(fmt.Sprintf("key %s cannot be altered because belongs to partition", alter.Key))
+	}
+	format := diskModels.Format{}
+	for key, formatItem := range f.Format {
+		formatItem.UniqueWith = slices.Clone(formatItem.UniqueWith)
+		format[key] = formatItem
+	}
+	formatItem, exists := format[alter.Key]
+	if !exists && alter.Op != memoryConstants.AlterAdd && slices.Contains(memoryConstants.GetAlterOps(), alter.Op) {
+		return nil, errors.New(fmt.Sprintf("key %s does not exist in %s", alter.Key, f.Name))
+	}
+	switch alter.Op {
+	case memoryConstants.AlterAdd:
+		if exists {
+			return nil, errors.New(fmt.Sprintf("key %s already exists in %s", alter.Key, f.Name))
+		}
+		if !alter.Optional && alter.Default == nil {
+			return nil, errors.New(fmt.Sprintf("key %s must be optional or have a default", alter.Key))
+		}
+		format[alter.Key] = diskModels.FormatItem{KeyType: alter.KeyType, Optional: alter.Optional, Default: alter.Default, Scale: alter.Scale, Values: alter.Values}
+	case memoryConstants.AlterDrop:
+		if len(format) == 1 {
+			return nil, errors.New(fmt.Sprintf("key %s is the last key in %s", alter.Key, f.Name))
+		}
+		for key, otherItem := range format {
+			if slices.Contains(otherItem.UniqueWith, alter.Key) {
+				return nil, errors.New(fmt.Sprintf("key %s belongs to unique constraint on %s", alter.Key, key))
+			}
+		}
+		delete(format, alter.Key)
+	case memoryConstants.AlterRename:
+		if _, ok := format[alter.NewKey]; ok {
+			return nil, errors.New(fmt.Sprintf("key %s already exists in %s", alter.NewKey, f.Name))
+		}
+		delete(format, alter.Key)
+		format[alter.NewKey] = formatItem
+		for key, otherItem := range format {
+			if index := slices.Index(otherItem.UniqueWith, alter.Key); index >= 0 {
+				otherItem.UniqueWith[index] = alter.NewKey
+				format[key] = otherItem
+			}
+		}
+	case memoryConstants.AlterType:
+		if !slices.Contains(memoryConstants.GetWidenedTypes(formatItem.KeyType), alter.KeyType) {
+			return nil, errors.New(fmt.Sprintf("key %s cannot be changed from %s to %s", alter.Key, formatItem.KeyType, alter.KeyType))
+		}
+		formatItem.KeyType = alter.KeyType
+		formatItem.Default = widenValue(formatItem.Default, alter.KeyType)
+		format[alter.Key] = formatItem
+	case memoryConstants.AlterUnique:
+		if formatItem.Unique {
+			return nil, errors.New(fmt.Sprintf("key %s is already unique in %s", alter.Key, f.Name))
+		}
+		formatItem.Unique = true
+		format[alter.Key] = formatItem
+	default:
+		return nil, errors.New(fmt.Sprintf("alter operation %s does not exist", alter.Op))
+	}
+	formatter := CreateFormatterWithPartition(f.Name, format, f.Partition)
+	if err := formatter.HasFormatStructure(); err != nil {
+		return nil, err
+	}
+	return format, nil
+}
+
+// AlterState reports the progress of the page rewrite started by Blob.Alter. A failed rewrite
+// keeps its alter file so the alter resumes on the next alter or the next time the blob is loaded.
+type AlterState struct {
+	Op         string `json:"op"`
+	Key        string `json:"key"`
+	Running    bool   `json:"running"`
+	PagesDone  int    `json:"pagesDone"`
+	PagesTotal int    `json:"pagesTotal"`
+	Error      string `json:"error,omitempty"`
+	done       chan struct{}
+}
+
+func (b *Blob) Alter(alter diskModels.Alter) error {
+	if err := b.WaitAlter(); err != nil {
+		if err = b.retryAlter(); err != nil {
+			return errors.New(fmt.Sprintf("previous alter on %s failed to resume: %s", b.blob, err.Error()))
+		}
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.alterState != nil && b.alterState.Running {
+		return errors.New(fmt.Sprintf("alter %s on %s is still running", b.alterState.Op, b.blob))
+	}
+	formatter := b.getFormatter()
+	format, err := formatter.AlterFormat(alter)
+	if err != nil {
+		return err
+	}
+	alter.Format = format
+	alter.IndexType = ""
+	if keyIndexItem, ok := b.keyIndexMap.GetAll()[alter.Key]; ok && alter.Op != memoryConstants.AlterDrop {
+		alter.IndexType = keyIndexItem.IndexType
+	}
+	if alter.Op == memoryConstants.AlterUnique {
+		if err = b.checkUniqueData(append([]string{alter.Key}, format[alter.Key].UniqueWith...)); err != nil {
+			return err
+		}
+	}
+	if err = b.formatDiskManager.WriteAlter(b.db, b.blob, alter); err != nil {
+		return err
+	}
+	previousFormat := diskModels.Format{}
+	for key, formatItem := range b.format {
+		previousFormat[key] = formatItem
+	}
+	if err = b.startAlter(alter); err != nil {
+		if rollbackErr := b.rollbackAlter(previousFormat); rollbackErr != nil {
+			return errors.New(fmt.Sprintf("%s: rollback failed: %s", err.Error(), rollbackErr.Error()))
+		}
+		return err
+	}
+	b.alterState = &AlterState{
+		Op:         alter.Op,
+		Key:        alter.Key,
+		Running:    true,
+		PagesTotal: len(b.pageMap.GetAll()),
+		done:       make(chan struct{}),
+	}
+	go b.runAlter(alter)
+	return nil
+}
+
+func (b *Blob) GetAlterState() (AlterState, bool) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.alterState == nil {
+		return AlterState{}, false
+	}
+	return *b.alterState, true
+}
+
+// WaitAlter blocks until the running alter finishes and returns its error.
+func (b *Blob) WaitAlter() error {
+	b.m.Lock()
+	alterState := b.alterState
+	b.m.Unlock()
+	if alterState == nil {
+		return nil
+	}
+	<-alterState.done
+	b.m.Lock()
+	defer b.m.Unlock()
+	if alterState.Error != "" {
+		return errors.New(alterState.Error)
+	}
+	return nil
+}
+
+func (b *Blob) resumeAlter() error {
+	alter, err := b.formatDiskManager.GetAlter(b.db, b.blob)
+	if err != nil || alter == nil {
+		return err
+	}
+	b.m.Lock()
+	err = b.startAlter(*alter)
+	b.m.Unlock()
+	if err != nil {
+		return err
+	}
+	return b.runAlter(*alter)
+}
+
+// retryAlter resumes a failed alter from its alter file so later alters on the blob do not wait for a reload.
+func (b *Blob) retryAlter() error {
+	b.m.Lock()
+	alterState := b.alterState
+	b.alterState = nil
+	b.m.Unlock()
+	err := b.resumeAlter()
+	if err != nil && alterState != nil {
+		b.m.Lock()
+		alterState.Error = err.Error()
+		b.alterState = alterState
+		b.m.Unlock()
+	}
+	return err
+}
+
+func (b *Blob) runAlter(alter diskModels.Alter) error {
+	err := b.finishAlter(alter)
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.alterState != nil && b.alterState.Running {
+		b.alterState.Running = false
+		if err != nil {
+			b.alterState.Error = err.Error()
+		}
+		close(b.alterState.done)
+	}
+	return err
+}
+
+func (b *Blob) startAlter(alter diskModels.Alter) error {
+	var addValue any
+	if alter.Op == memoryConstants.AlterAdd {
+		formatter := b.getFormatter()
+		value, err := formatter.convertNullableValue(alter.Key, alter.Format[alter.Key].Default, alter.Format[alter.Key])
+		if err != nil {
+			return err
+		}
+		addValue = value
+	}
+	if err := b.formatDiskManager.Update(b.db, b.blob, alter.Format); err != nil {
+		return err
+	}
+	b.setFormat(alter.Format)
+	if alter.Op == memoryConstants.AlterUnique {
+		return nil
+	}
+	if _, ok := b.keyIndexMap.GetAll()[alter.Key]; ok && alter.Op != memoryConstants.AlterAdd {
+		if err := b.keyIndexMap.Delete(alter.Key); err != nil {
+			return err
+		}
+	}
+	b.pageMap.SetTransform(getAlterTransform(alter, addValue))
+	return nil
+}
+
+func (b *Blob) rollbackAlter(format diskModels.Format) error {
+	if err := b.formatDiskManager.Update(b.db, b.blob, format); err != nil {
+		return err
+	}
+	b.setFormat(format)
+	return b.formatDiskManager.DeleteAlter(b.db, b.blob)
+}
+
+func (b *Blob) finishAlter(alter diskModels.Alter) error {
+	pages := b.pageMap.GetAll()
+	for index, page := range pages {
+		if err := b.rewritePage(page, index+1, len(pages)); err != nil {
+			return errors.New(fmt.Sprintf("alter %s on %s failed to rewrite pages: %s", alter.Op, b.blob, err.Error()))
+		}
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.pageMap.SetTransform(nil)
+	if alter.IndexType != "" {
+		key := alter.Key
+		if alter.Op == memoryConstants.AlterRename {
+			key = alter.NewKey
+		}
+		_, exists := b.keyIndexMap.GetAll()[key]
+		if !exists && slices.Contains(memoryConstants.GetKeyIndexTypes(b.format[key].KeyType), alter.IndexType) {
+			if err := b.createKeyIndex(key, alter.IndexType); err != nil {
+				return errors.New(fmt.Sprintf("alter %s on %s failed to rebuild index on %s: %s", alter.Op, b.blob, key, err.Error()))
+			}
+		}
+	}
+	if err := b.ensureUniqueKeyIndexes(); err != nil {
+		return errors.New(fmt.Sprintf("alter %s on %s failed to index unique keys: %s", alter.Op, b.blob, err.Error()))
+	}
+	return b.formatDiskManager.DeleteAlter(b.db, b.blob)
+}
+
+func (b *Blob) rewritePage(page *Page, pagesDone int, pagesTotal int) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if err := page.rewrite(); err != nil {
+		return err
+	}
+	if b.alterState != nil {
+		b.alterState.PagesDone = pagesDone
+		b.alterState.PagesTotal = pagesTotal
+	}
+	return nil
+}
+
+func (b *Blob) setFormat(format diskModels.Format) {
+	for key := range b.format {
+		delete(b.format, key)
+	}
+	for key, formatItem := range format {
+		b.format[key] = formatItem
+	}
+}
+
+func getAlterTransform(alter diskModels.Alter, addValue any) PageRecordTransform {
+	return func(pageRecord diskModels.PageRecord) diskModels.PageRecord {
+		newRecord := diskModels.PageRecord{}
+		for key, value := range pageRecord {
+			newRecord[key] = value
+		}
+		value, ok := newRecord[alter.Key]
+		switch alter.Op {
+		case memoryConstants.AlterAdd:
+			if !ok {
+				newRecord[alter.Key] = addValue
+			}
+		case memoryConstants.AlterDrop:
+			delete(newRecord, alter.Key)
+		case memoryConstants.AlterRename:
+			if ok {
+				newRecord[alter.NewKey] = value
+				delete(newRecord, alter.Key)
+			}
+		case memoryConstants.AlterType:
+			if ok {
+				newRecord[alter.Key] = widenValue(value, alter.KeyType)
+			}
+		}
+		return newRecord
+	}
+}
+
+func widenValue(value any, keyType string) any {
+	if value == nil {
+		return nil
+	}
+	switch keyType {
+	case memoryConstants.Float:
+		if number, err := memoryUtils.ConvertToFloat64(value); err == nil {
+			return number
+		}
+	case memoryConstants.DateTime:
+		if dateString, ok := value.(string); ok {
+			if parsed, err := time.Parse(time.DateOnly, dateString); err == nil {
+				return memoryUtils.FormatDateTime(parsed)
+			}
+		}
+	case memoryConstants.String:
+		switch typedValue := value.(type) {
+		case string:
+			return typedValue
+		case bool:
+			return strconv.FormatBool(typedValue)
+		default:
+			if number, err := memoryUtils.ConvertToFloat64(value); err == nil {
+				return strconv.FormatFloat(number, 'f', -1, 64)
+			}
+		}
+	}
+	return value
+}
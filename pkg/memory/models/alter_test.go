@@ -0,0 +1,502 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func createTestAlterFormatter() BlobFormatter {
+	return CreateFormatterWithPartition("orders", diskModels.Format{
+		"region": {KeyType: memoryConstants.String},
+		"org":    {KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"code"}},
+		"code":   {KeyType: memoryConstants.String},
+		"qty":    {KeyType: memoryConstants.Int, Default: 1},
+	}, diskModels.Partition{Keys: []string{"region"}})
+}
+
+func TestUnit_AlterFormat_AddsKey(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	format, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterAdd, Key: "note", KeyType: memoryConstants.String, Optional: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.FormatItem{KeyType: memoryConstants.String, Optional: true}, format["note"])
+	assert.NotContains(t, formatter.Format, "note")
+}
+
+func TestUnit_AlterFormat_FailsOnAddWithoutDefault(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	_, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterAdd, Key: "note", KeyType: memoryConstants.String})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must be optional or have a default")
+}
+
+func TestUnit_AlterFormat_FailsOnPartitionKey(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	_, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "region", NewKey: "area"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "belongs to partition")
+}
+
+func TestUnit_AlterFormat_FailsOnDropOfUniqueWithKey(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	_, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "code"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "belongs to unique constraint on org")
+}
+
+func TestUnit_AlterFormat_RenamesKeyAndUniqueWith(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	format, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+
+	assert.Nil(t, err)
+	assert.NotContains(t, format, "code")
+	assert.Equal(t, []string{"sku"}, format["org"].UniqueWith)
+	assert.Equal(t, []string{"code"}, formatter.Format["org"].UniqueWith)
+}
+
+func TestUnit_AlterFormat_WidensTypeAndDefault(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	format, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterType, Key: "qty", KeyType: memoryConstants.String})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.FormatItem{KeyType: memoryConstants.String, Default: "1"}, format["qty"])
+}
+
+func TestUnit_AlterFormat_FailsOnNarrowingType(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	_, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterType, Key: "code", KeyType: memoryConstants.Int})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot be changed from string to int")
+}
+
+func TestUnit_AlterFormat_MakesKeyUnique(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	format, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterUnique, Key: "code"})
+	_, uniqueErr := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterUnique, Key: "org"})
+
+	assert.Nil(t, err)
+	assert.True(t, format["code"].Unique)
+	assert.False(t, formatter.Format["code"].Unique)
+	assert.NotNil(t, uniqueErr)
+	assert.Contains(t, uniqueErr.Error(), "key org is already unique in orders")
+}
+
+func TestUnit_AlterFormat_FailsOnUnknownKey(t *testing.T) {
+	formatter := createTestAlterFormatter()
+
+	_, err := formatter.AlterFormat(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "missing"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "key missing does not exist")
+}
+
+func TestUnit_GetAlterTransform_TransformsRecord(t *testing.T) {
+	record := diskModels.PageRecord{"qty": 2, "code": "a"}
+
+	assert.Equal(t, diskModels.PageRecord{"qty": 2, "code": "a", "note": "none"}, getAlterTransform(diskModels.Alter{Op: memoryConstants.AlterAdd, Key: "note"}, "none")(record))
+	assert.Equal(t, diskModels.PageRecord{"qty": 2}, getAlterTransform(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "code"}, nil)(record))
+	assert.Equal(t, diskModels.PageRecord{"qty": 2, "sku": "a"}, getAlterTransform(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"}, nil)(record))
+	assert.Equal(t, diskModels.PageRecord{"qty": float64(2), "code": "a"}, getAlterTransform(diskModels.Alter{Op: memoryConstants.AlterType, Key: "qty", KeyType: memoryConstants.Float}, nil)(record))
+	assert.Equal(t, diskModels.PageRecord{"qty": 2, "code": "a"}, record)
+}
+
+func TestUnit_WidenValue_WidensValues(t *testing.T) {
+	assert.Equal(t, float64(3), widenValue(3, memoryConstants.Float))
+	assert.Equal(t, "2.5", widenValue(2.5, memoryConstants.String))
+	assert.Equal(t, "true", widenValue(true, memoryConstants.String))
+	assert.Equal(t, "2024-01-02T00:00:00Z", widenValue("2024-01-02", memoryConstants.DateTime))
+	assert.Nil(t, widenValue(nil, memoryConstants.String))
+}
+
+func TestUnit_Alter_RewritesPages(t *testing.T) {
+	data := diskModels.PageRecords{
+		"id1": {"code": "a", "qty": 1},
+	}
+	var writtenData diskModels.PageRecords
+	var updatedFormat diskModels.Format
+	alterDeleted := false
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, data diskModels.PageRecords) error {
+		writtenData = data
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		updatedFormat = format
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		alterDeleted = true
+		return nil
+	}
+	m := &sync.Mutex{}
+	blob := createTestBlob("db", "orders", "dataLocation", false, m, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+	waitErr := blob.WaitAlter()
+	alterState, _ := blob.GetAlterState()
+
+	assert.Nil(t, err)
+	assert.Nil(t, waitErr)
+	assert.False(t, alterState.Running)
+	assert.Equal(t, 1, alterState.PagesDone)
+	assert.Equal(t, 1, alterState.PagesTotal)
+	assert.Equal(t, diskModels.PageRecords{"id1": {"sku": "a", "qty": 1}}, writtenData)
+	assert.Equal(t, diskModels.Format{
+		"sku": {KeyType: memoryConstants.String},
+		"qty": {KeyType: memoryConstants.Int},
+	}, updatedFormat)
+	assert.Equal(t, updatedFormat, blob.format)
+	assert.True(t, alterDeleted)
+}
+
+func TestUnit_Alter_FailsOnWriteAlterError(t *testing.T) {
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		return assert.AnError
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "code"})
+
+	assert.Equal(t, assert.AnError, err)
+	assert.Contains(t, blob.format, "code")
+}
+
+func TestUnit_Alter_FailsOnRewriteError(t *testing.T) {
+	alterDeleted := false
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return diskModels.PageRecords{"id1": {"code": "a", "qty": 1}}, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, data diskModels.PageRecords) error {
+		return assert.AnError
+	}
+	var writtenAlter *diskModels.Alter
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		writtenAlter = &alter
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.GetAlterFunc = func(db string, blob string) (*diskModels.Alter, error) {
+		return writtenAlter, nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		alterDeleted = true
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+	waitErr := blob.WaitAlter()
+	alterState, _ := blob.GetAlterState()
+	nextErr := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "qty"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, waitErr)
+	assert.Contains(t, waitErr.Error(), "alter rename on orders failed to rewrite pages")
+	assert.False(t, alterState.Running)
+	assert.Equal(t, waitErr.Error(), alterState.Error)
+	assert.NotNil(t, nextErr)
+	assert.Contains(t, nextErr.Error(), "previous alter on orders failed to resume")
+	assert.False(t, alterDeleted)
+	assert.NotContains(t, blob.format, "code")
+	assert.Contains(t, blob.format, "qty")
+}
+
+func TestUnit_Alter_ResumesFailedAlterOnNextAlter(t *testing.T) {
+	data := diskModels.PageRecords{"id1": {"code": "a", "qty": 1}}
+	writeErr := assert.AnError
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, pageRecords diskModels.PageRecords) error {
+		if writeErr != nil {
+			return writeErr
+		}
+		data = pageRecords
+		return nil
+	}
+	var writtenAlter *diskModels.Alter
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		writtenAlter = &alter
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.GetAlterFunc = func(db string, blob string) (*diskModels.Alter, error) {
+		return writtenAlter, nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		writtenAlter = nil
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	renameErr := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+	renameWaitErr := blob.WaitAlter()
+	writeErr = nil
+	dropErr := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "qty"})
+	dropWaitErr := blob.WaitAlter()
+	alterState, _ := blob.GetAlterState()
+
+	assert.Nil(t, renameErr)
+	assert.NotNil(t, renameWaitErr)
+	assert.Nil(t, dropErr)
+	assert.Nil(t, dropWaitErr)
+	assert.Equal(t, memoryConstants.AlterDrop, alterState.Op)
+	assert.Equal(t, "", alterState.Error)
+	assert.Equal(t, diskModels.PageRecords{"id1": {"sku": "a"}}, data)
+	assert.Equal(t, diskModels.Format{"sku": {KeyType: memoryConstants.String}}, blob.format)
+	assert.Nil(t, writtenAlter)
+}
+
+func TestUnit_Alter_ReadsWhileAlterIsRunning(t *testing.T) {
+	data := diskModels.PageRecords{"id1": {"code": "a", "qty": 1}}
+	finishing := make(chan bool)
+	release := make(chan bool)
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, pageRecords diskModels.PageRecords) error {
+		data = pageRecords
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		finishing <- true
+		<-release
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{"code": {}})
+	diskManagers.MockKeyIndexManagerInstance.DeleteFunc = func(db string, blob string, key string) error {
+		return nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		return nil
+	}
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+	<-finishing
+	pageRecordItems, readErr := blob.GetFullScan([]FilterItem{}, GetOperationParams{})
+	release <- true
+	waitErr := blob.WaitAlter()
+
+	assert.Nil(t, err)
+	assert.Nil(t, readErr)
+	assert.Equal(t, 1, len(pageRecordItems))
+	assert.Equal(t, diskModels.PageRecord{"sku": "a", "qty": 1}, pageRecordItems[0].PageRecord)
+	assert.Nil(t, waitErr)
+}
+
+func TestUnit_Alter_WaitsForRunningAlter(t *testing.T) {
+	data := diskModels.PageRecords{"id1": {"code": "a", "qty": 1}}
+	finishing := make(chan bool, 2)
+	release := make(chan bool, 2)
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, pageRecords diskModels.PageRecords) error {
+		data = pageRecords
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		finishing <- true
+		<-release
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	renameErr := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+	<-finishing
+	dropErr := make(chan error)
+	go func() {
+		dropErr <- blob.Alter(diskModels.Alter{Op: memoryConstants.AlterDrop, Key: "qty"})
+	}()
+	release <- true
+	release <- true
+	waitErr := <-dropErr
+
+	assert.Nil(t, renameErr)
+	assert.Nil(t, waitErr)
+	assert.Nil(t, blob.WaitAlter())
+	assert.Equal(t, diskModels.PageRecords{"id1": {"sku": "a"}}, data)
+}
+
+func TestUnit_Alter_RollsBackOnStartAlterError(t *testing.T) {
+	var updatedFormat diskModels.Format
+	alterDeleted := false
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		updatedFormat = format
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		alterDeleted = true
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{"code": {}})
+	diskManagers.MockKeyIndexManagerInstance.DeleteFunc = func(db string, blob string, key string) error {
+		return assert.AnError
+	}
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterRename, Key: "code", NewKey: "sku"})
+
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	}, updatedFormat)
+	assert.Equal(t, updatedFormat, blob.format)
+	assert.True(t, alterDeleted)
+}
+
+func createTestUniqueAlterBlob(data diskModels.PageRecords, writeAlterCalled *bool, pageWriteCalled *bool, indexCreated *string) Blob {
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, data diskModels.PageRecords) error {
+		*pageWriteCalled = true
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteAlterFunc = func(db string, blob string, alter diskModels.Alter) error {
+		*writeAlterCalled = true
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.UpdateFunc = func(db string, blob string, format diskModels.Format) error {
+		return nil
+	}
+	diskManagers.MockFormatManagerInstance.DeleteAlterFunc = func(db string, blob string) error {
+		return nil
+	}
+	blob := createTestBlob("db", "orders", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"code": {KeyType: memoryConstants.String},
+		"qty":  {KeyType: memoryConstants.Int},
+	})
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		*indexCreated = keyIndexItem.Key
+		return nil
+	}
+	return blob
+}
+
+func TestUnit_Alter_MakesKeyUniqueAndIndexesIt(t *testing.T) {
+	writeAlterCalled, pageWriteCalled, indexCreated := false, false, ""
+	blob := createTestUniqueAlterBlob(diskModels.PageRecords{
+		"id1": {"code": "a", "qty": 1},
+		"id2": {"code": "b", "qty": 1},
+	}, &writeAlterCalled, &pageWriteCalled, &indexCreated)
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterUnique, Key: "code"})
+	waitErr := blob.WaitAlter()
+
+	assert.Nil(t, err)
+	assert.Nil(t, waitErr)
+	assert.True(t, blob.format["code"].Unique)
+	assert.False(t, pageWriteCalled)
+	assert.Equal(t, "code", indexCreated)
+	assert.Equal(t, memoryConstants.KeyIndexHash, blob.GetKeyIndexes()["code"].IndexType)
+}
+
+func TestUnit_Alter_FailsOnUniqueConflictInStoredRecords(t *testing.T) {
+	writeAlterCalled, pageWriteCalled, indexCreated := false, false, ""
+	blob := createTestUniqueAlterBlob(diskModels.PageRecords{
+		"id1": {"code": "a", "qty": 1},
+		"id2": {"code": "a", "qty": 2},
+	}, &writeAlterCalled, &pageWriteCalled, &indexCreated)
+
+	err := blob.Alter(diskModels.Alter{Op: memoryConstants.AlterUnique, Key: "code"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unique constraint on code violated")
+	assert.False(t, writeAlterCalled)
+	assert.False(t, blob.format["code"].Unique)
+	assert.Equal(t, "", indexCreated)
+}
@@ -1,11 +1,15 @@
 package memoryModels
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"io/fs"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -51,6 +55,9 @@ func (bm *BlobMap) Get(blob string) (*Blob, error) {
 		return blobObj, nil
 	}
 	blobObj, err := bm.createBlobFunc(bm.db, blob, bm.dataLocation, bm.dataCaching)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, BlobNotFoundError{DB: bm.db, Blob: blob}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +97,7 @@ func (bm *BlobMap) ConvertToPageRecords() []diskModels.PageRecord {
 			"name":      blobName,
 			"format":    blob.format.ConvertToPageRecords(),
 			"partition": blob.partition.ConvertToPageRecords(),
+			"indexes":   blob.GetKeyIndexes().ConvertToPageRecords(),
 		})
 	}
 	return pageRecords
@@ -103,11 +111,14 @@ type Blob struct {
 	db                   string
 	pageMap              PageMapI
 	indexMap             IndexMapI
+	keyIndexMap          KeyIndexMapI
 	partitionMap         PartitionMapI
 	partition            diskModels.Partition
 	format               diskModels.Format
 	indexDiskManager     diskManagers.IndexManager
 	partitionDiskManager diskManagers.PartitionManager
+	formatDiskManager    diskManagers.FormatManager
+	alterState           *AlterState
 }
 
 func CreateBlob(db string, blob string, dataLocation string, dataCaching bool) (Blob, error) {
@@ -127,6 +138,7 @@ func CreateBlob(db string, blob string, dataLocation string, dataCaching bool) (
 		partition:            diskModels.Partition{},
 		indexDiskManager:     indexDiskManager,
 		partitionDiskManager: partitionDiskManager,
+		formatDiskManager:    formatDiskManager,
 	}
 
 	format, err := formatDiskManager.Get(db, blob)
@@ -134,6 +146,7 @@ func CreateBlob(db string, blob string, dataLocation string, dataCaching bool) (
 		return blobStruct, err
 	}
 	blobStruct.format = format
+	blobStruct.keyIndexMap = NewKeyIndexMap(db, blob, dataLocation, format, dataCaching)
 
 	if err := blobStruct.pageMap.Initialize(); err != nil {
 		return blobStruct, err
@@ -141,6 +154,9 @@ func CreateBlob(db string, blob string, dataLocation string, dataCaching bool) (
 	if err := blobStruct.indexMap.Initialize(); err != nil {
 		return blobStruct, err
 	}
+	if err := blobStruct.keyIndexMap.Initialize(); err != nil {
+		return blobStruct, err
+	}
 
 	if partition, err := partitionDiskManager.GetPartition(db, blob); err == nil {
 		if err := blobStruct.partitionMap.Initialize(); err != nil {
@@ -149,6 +165,13 @@ func CreateBlob(db string, blob string, dataLocation string, dataCaching bool) (
 		blobStruct.partition = partition
 	}
 
+	if err := blobStruct.migrate(); err != nil {
+		return blobStruct, err
+	}
+	if err := blobStruct.resumeAlter(); err != nil {
+		return blobStruct, err
+	}
+
 	return blobStruct, nil
 }
 
@@ -183,6 +206,10 @@ func InitializeBlob(db string, blob string, dataLocation string, format diskMode
 		_ = blobDiskManager.Delete(db, blob)
 		return Blob{}, err
 	}
+	if err := formatDiskManager.WriteMigrations(db, blob, memoryConstants.GetMigrations()); err != nil {
+		_ = blobDiskManager.Delete(db, blob)
+		return Blob{}, err
+	}
 	if err := pageDiskManager.Initialize(db, blob); err != nil {
 		_ = blobDiskManager.Delete(db, blob)
 		return Blob{}, err
@@ -205,21 +232,31 @@ func InitializeBlob(db string, blob string, dataLocation string, format diskMode
 		partitionObj = *partition
 	}
 
-	return Blob{
+	blobStruct := Blob{
 		m:                    &sync.Mutex{},
 		blob:                 blob,
 		db:                   db,
 		pageMap:              pageMap,
 		indexMap:             NewIndexMap(db, blob, dataLocation, dataCaching),
+		keyIndexMap:          NewKeyIndexMap(db, blob, dataLocation, format, dataCaching),
 		partitionMap:         NewPartitionMap(db, blob, dataLocation, pageMap),
 		partition:            partitionObj,
 		format:               format,
 		indexDiskManager:     indexDiskManager,
 		partitionDiskManager: partitionDiskManager,
-	}, nil
+		formatDiskManager:    formatDiskManager,
+	}
+	if err := blobStruct.ensureUniqueKeyIndexes(); err != nil {
+		_ = blobDiskManager.Delete(db, blob)
+		return Blob{}, err
+	}
+	return blobStruct, nil
 }
 
-func (b *Blob) GetByRecordId(pageRecordId string) (PageRecordsMap, error) {
+func (b *Blob) GetByRecordId(pageRecordId string, getOperationParams GetOperationParams) (PageRecordsMap, error) {
+	if err := getOperationParams.Validate(b.format); err != nil {
+		return PageRecordsMap{}, err
+	}
 	indexFiles, err := b.indexMap.GetByPrefix(b.indexDiskManager.GetPageRecordIdPrefix(pageRecordId))
 	if err != nil {
 		return PageRecordsMap{}, nil
@@ -257,7 +294,7 @@ func (b *Blob) GetByRecordId(pageRecordId string) (PageRecordsMap, error) {
 			}
 			return PageRecordsMap{
 				pageFile: {
-					pageRecordId: formattedRecord,
+					pageRecordId: getOperationParams.Project(formattedRecord, b.format),
 				},
 			}, nil
 		}
@@ -265,84 +302,92 @@ func (b *Blob) GetByRecordId(pageRecordId string) (PageRecordsMap, error) {
 	return PageRecordsMap{}, nil
 }
 
-func (b *Blob) GetFullScan(filterItems []FilterItem) (PageRecordsMap, error) {
+func (b *Blob) GetFullScan(filterItems []FilterItem, getOperationParams GetOperationParams) (PageRecordItems, error) {
 	filter := Filter{FilterItems: filterItems, Format: b.format}
 	err := filter.ConvertFilterItems()
 	if err != nil {
-		return PageRecordsMap{}, err
+		return PageRecordItems{}, err
 	}
-	var wg sync.WaitGroup
-	total := PageRecordsMap{}
-	pages := b.pageMap.GetAll()
-	for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
-		var groups [memoryConstants.SearchThreadCount]diskModels.PageRecords
-		threadItem := i
-		threadIndex := 0
-		for threadItem < len(pages) && threadIndex < memoryConstants.SearchThreadCount {
-			wg.Add(1)
-			go b.SearchPage(pages[threadItem], filter, &groups, &wg, threadIndex)
-			threadIndex++
-			threadItem++
-		}
-		wg.Wait()
-		currentFileIndex := i
-
-		for _, groupItem := range groups {
-			if len(groupItem) == 0 {
-				currentFileIndex++
-				continue
-			}
-			total[pages[currentFileIndex].GetFileName()] = groupItem
-			currentFileIndex++
-		}
+	if err = getOperationParams.Validate(b.format); err != nil {
+		return PageRecordItems{}, err
 	}
-	return total, nil
+	getOperationParams.RankBy(filter)
+	pages, err := b.filterIndexedPages(b.pageMap.GetAll(), filter)
+	if err != nil {
+		return PageRecordItems{}, err
+	}
+	if pageRecordItems, ok, err := b.searchOrderedPages(pages, filter, getOperationParams); ok || err != nil {
+		return pageRecordItems, err
+	}
+	return b.searchPages(pages, filter, getOperationParams), nil
 }
 
-func (b *Blob) GetByPartition(searchPartition SearchPartition, filterItems []FilterItem) (PageRecordsMap, error) {
+func (b *Blob) GetByPartition(searchPartition SearchPartition, filterItems []FilterItem, getOperationParams GetOperationParams) (PageRecordItems, error) {
 	if b.partition.Keys == nil {
-		return PageRecordsMap{}, nil
+		return PageRecordItems{}, nil
 	}
 	filter := Filter{FilterItems: filterItems, Format: b.format}
 	err := filter.ConvertFilterItems()
 	if err != nil {
-		return PageRecordsMap{}, err
+		return PageRecordItems{}, err
 	}
-	hashKeyFiles, err := b.FilterHashKeyFiles(b.partitionMap.GetAllHashKeys(), searchPartition)
+	if err = getOperationParams.Validate(b.format); err != nil {
+		return PageRecordItems{}, err
+	}
+	getOperationParams.RankBy(filter)
+	pages, err := b.getPartitionPages(searchPartition, filter)
 	if err != nil {
-		return PageRecordsMap{}, err
+		return PageRecordItems{}, err
 	}
-	total := PageRecordsMap{}
-	for _, hashKeyFile := range hashKeyFiles {
-		pages, err := b.partitionMap.GetByHash(hashKeyFile)
+	pages, err = b.filterIndexedPages(pages, filter)
+	if err != nil {
+		return PageRecordItems{}, err
+	}
+	if pageRecordItems, ok, err := b.searchOrderedPages(pages, filter, getOperationParams); ok || err != nil {
+		return pageRecordItems, err
+	}
+	return b.searchPages(pages, filter, getOperationParams), nil
+}
+
+func (b *Blob) Aggregate(searchPartition SearchPartition, filterItems []FilterItem, aggregateParams AggregateParams) ([]diskModels.PageRecord, error) {
+	filter := Filter{FilterItems: filterItems, Format: b.format}
+	err := filter.ConvertFilterItems()
+	if err != nil {
+		return []diskModels.PageRecord{}, err
+	}
+	if err = aggregateParams.Validate(b.format); err != nil {
+		return []diskModels.PageRecord{}, err
+	}
+	pages := b.pageMap.GetAll()
+	if b.IsPartition() {
+		pages, err = b.getPartitionPages(searchPartition, filter)
 		if err != nil {
-			return total, err
+			return []diskModels.PageRecord{}, err
 		}
-		var wg sync.WaitGroup
-		for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
-			var groups [memoryConstants.SearchThreadCount]diskModels.PageRecords
-			threadItem := i
-			threadIndex := 0
-			for threadItem < len(pages) && threadIndex < memoryConstants.SearchThreadCount {
-				wg.Add(1)
-				go b.SearchPage(pages[threadItem], filter, &groups, &wg, threadIndex)
-				threadIndex++
-				threadItem++
-			}
-			wg.Wait()
-			currentFileIndex := i
+	}
+	pages, err = b.filterIndexedPages(pages, filter)
+	if err != nil {
+		return []diskModels.PageRecord{}, err
+	}
+	total := AggregateGroups{}
+	var wg sync.WaitGroup
+	for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
+		var groups [memoryConstants.SearchThreadCount]AggregateGroups
+		threadItem := i
+		threadIndex := 0
+		for threadItem < len(pages) && threadIndex < memoryConstants.SearchThreadCount {
+			wg.Add(1)
+			go b.SearchPageAggregate(pages[threadItem], filter, aggregateParams, &groups, &wg, threadIndex)
+			threadIndex++
+			threadItem++
+		}
+		wg.Wait()
 
-			for _, groupItem := range groups {
-				if len(groupItem) == 0 {
-					currentFileIndex++
-					continue
-				}
-				total[pages[currentFileIndex].GetFileName()] = groupItem
-				currentFileIndex++
-			}
+		for _, groupItem := range groups {
+			aggregateParams.Merge(total, groupItem, b.format)
 		}
 	}
-	return total, nil
+	return aggregateParams.ConvertToPageRecords(total, b.format), nil
 }
 
 func (b *Blob) AddWithPartition(insertPageRecords []diskModels.PageRecord) (PageRecordsMap, error) {
@@ -352,21 +397,40 @@ func (b *Blob) AddWithPartition(insertPageRecords []diskModels.PageRecord) (Page
 	b.m.Lock()
 	defer b.m.Unlock()
 	formatter := CreateFormatterWithPartition(b.blob, b.format, b.partition)
-	hashKeyMap := make(map[string][]diskModels.PageRecord)
+	hashKeyMap := make(map[string]diskModels.PageRecords)
 	for _, insertPageRecord := range insertPageRecords {
+		pageRecordId, insertPageRecord, err := b.splitRecordId(insertPageRecord)
+		if err != nil {
+			return PageRecordsMap{}, err
+		}
 		newInsertRecord, err := formatter.FormatRecord(insertPageRecord)
 		if err != nil {
 			return PageRecordsMap{}, err
 		}
-		hashKey, err := b.partitionDiskManager.GetHashKey(b.partition, newInsertRecord)
+		hashKey, err := b.getHashKey(newInsertRecord)
 		if err != nil {
 			return PageRecordsMap{}, err
 		}
 		_, ok := hashKeyMap[hashKey]
 		if !ok {
-			hashKeyMap[hashKey] = []diskModels.PageRecord{}
+			hashKeyMap[hashKey] = diskModels.PageRecords{}
+		}
+		if _, ok := hashKeyMap[hashKey][pageRecordId]; ok {
+			return PageRecordsMap{}, errors.New(fmt.Sprintf("record with id %s is duplicated", pageRecordId))
 		}
-		hashKeyMap[hashKey] = append(hashKeyMap[hashKey], newInsertRecord)
+		hashKeyMap[hashKey][pageRecordId] = newInsertRecord
+	}
+	uniquePageRecords := diskModels.PageRecords{}
+	for _, pageRecords := range hashKeyMap {
+		for pageRecordId, pageRecord := range pageRecords {
+			uniquePageRecords[pageRecordId] = pageRecord
+		}
+	}
+	if err := b.checkUnique(uniquePageRecords); err != nil {
+		return PageRecordsMap{}, err
+	}
+	if err := b.ensurePartitionCatalog(); err != nil {
+		return PageRecordsMap{}, err
 	}
 	total := PageRecordsMap{}
 	for hashKey, pageRecords := range hashKeyMap {
@@ -379,8 +443,14 @@ func (b *Blob) AddWithPartition(insertPageRecords []diskModels.PageRecord) (Page
 				total[pageFile] = data
 			}
 		}
+		for _, pageRecord := range pageRecords {
+			if err = b.partitionMap.UpdateCatalog(hashKey, b.getPartitionValues(pageRecord), partitionTotal.Count()); err != nil {
+				return total, err
+			}
+			break
+		}
 	}
-	return total, nil
+	return total, b.keyIndexMap.AddRecords(total)
 }
 
 func (b *Blob) Add(insertPageRecords []diskModels.PageRecord) (PageRecordsMap, error) {
@@ -403,13 +473,29 @@ func (b *Blob) Add(insertPageRecords []diskModels.PageRecord) (PageRecordsMap, e
 	}
 	total := PageRecordsMap{}
 	total[currentPage.GetFileName()] = diskModels.PageRecords{}
-	indexes := diskModels.IndexRecords{}
+	insertPageRecordIds := []string{}
+	formattedInsertRecords := diskModels.PageRecords{}
 	for _, insertPageRecord := range insertPageRecords {
+		lastRecordId, insertPageRecord, err := b.splitRecordId(insertPageRecord)
+		if err != nil {
+			return total, err
+		}
+		if _, ok := formattedInsertRecords[lastRecordId]; ok {
+			return total, errors.New(fmt.Sprintf("record with id %s is duplicated", lastRecordId))
+		}
 		formattedInsertRecord, err := formatter.FormatRecord(insertPageRecord)
 		if err != nil {
 			return total, err
 		}
-		lastRecordId := uuid.New().String()
+		insertPageRecordIds = append(insertPageRecordIds, lastRecordId)
+		formattedInsertRecords[lastRecordId] = formattedInsertRecord
+	}
+	if err = b.checkUnique(formattedInsertRecords); err != nil {
+		return total, err
+	}
+	indexes := diskModels.IndexRecords{}
+	for _, lastRecordId := range insertPageRecordIds {
+		formattedInsertRecord := formattedInsertRecords[lastRecordId]
 		pageRecords[lastRecordId] = formattedInsertRecord
 		total[currentPage.GetFileName()][lastRecordId] = formattedInsertRecord
 		indexes[lastRecordId] = currentPage.GetFileName()
@@ -432,8 +518,10 @@ func (b *Blob) Add(insertPageRecords []diskModels.PageRecord) (PageRecordsMap, e
 		delete(total, currentPage.GetFileName())
 		return total, err
 	}
-	err = b.addIndexes(indexes)
-	return total, err
+	if err = b.addIndexes(indexes); err != nil {
+		return total, err
+	}
+	return total, b.keyIndexMap.AddRecords(total)
 }
 
 func (b *Blob) UpdateByIndex(pageRecordId string, updateRecord diskModels.PageRecord) (PageRecordsMap, error) {
@@ -474,6 +562,18 @@ func (b *Blob) UpdateByIndex(pageRecordId string, updateRecord diskModels.PageRe
 			if !ok {
 				return PageRecordsMap{}, fmt.Errorf("record with id %s not found in page %s", pageRecordId, pageFile)
 			}
+			if b.touchesUniqueKey(updateRecordFormatted) {
+				pageRecord := diskModels.PageRecord{}
+				for key, value := range data[pageRecordId] {
+					pageRecord[key] = value
+				}
+				for key, value := range updateRecordFormatted {
+					pageRecord[key] = value
+				}
+				if err = b.checkUnique(diskModels.PageRecords{pageRecordId: pageRecord}); err != nil {
+					return PageRecordsMap{}, err
+				}
+			}
 			for key, value := range updateRecordFormatted {
 				data[pageRecordId][key] = value
 			}
@@ -481,14 +581,15 @@ func (b *Blob) UpdateByIndex(pageRecordId string, updateRecord diskModels.PageRe
 			if err != nil {
 				return PageRecordsMap{}, err
 			}
-			return PageRecordsMap{
+			total := PageRecordsMap{
 				pageFile: {
 					pageRecordId: updateRecordFormatted,
 				},
-			}, nil
+			}
+			return total, b.keyIndexMap.UpdateRecords(total, updateRecordFormatted)
 		}
 	}
-	return PageRecordsMap{}, nil
+	return PageRecordsMap{}, RecordNotFoundError{PageRecordId: pageRecordId}
 }
 
 func (b *Blob) UpdateByPartition(updateRecord diskModels.PageRecord, searchPartition SearchPartition, filterItems []FilterItem) (PageRecordsMap, error) {
@@ -511,12 +612,26 @@ func (b *Blob) UpdateByPartition(updateRecord diskModels.PageRecord, searchParti
 	if err != nil {
 		return PageRecordsMap{}, err
 	}
+	hashKeyFiles = b.FilterBucketKeyFiles(hashKeyFiles, filter)
+	if b.touchesUniqueKey(updateRecordFormatted) {
+		pages, err := b.getPartitionPages(searchPartition, filter)
+		if err != nil {
+			return PageRecordsMap{}, err
+		}
+		if err = b.checkUniqueUpdate(pages, filter, updateRecordFormatted); err != nil {
+			return PageRecordsMap{}, err
+		}
+	}
 	total := PageRecordsMap{}
 	for _, hashKeyFile := range hashKeyFiles {
 		pages, err := b.partitionMap.GetByHash(hashKeyFile)
 		if err != nil {
 			return total, err
 		}
+		pages, err = b.filterIndexedPages(pages, filter)
+		if err != nil {
+			return total, err
+		}
 		var wg sync.WaitGroup
 		for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
 			var groups [memoryConstants.SearchThreadCount]diskModels.PageRecords
@@ -541,7 +656,7 @@ func (b *Blob) UpdateByPartition(updateRecord diskModels.PageRecord, searchParti
 			}
 		}
 	}
-	return total, nil
+	return total, b.keyIndexMap.UpdateRecords(total, updateRecordFormatted)
 }
 
 func (b *Blob) Update(updateRecord diskModels.PageRecord, filterItems []FilterItem) (PageRecordsMap, error) {
@@ -552,7 +667,10 @@ func (b *Blob) Update(updateRecord diskModels.PageRecord, filterItems []FilterIt
 	if err != nil {
 		return nil, err
 	}
-	pages := b.pageMap.GetAll()
+	pages, err := b.filterIndexedPages(b.pageMap.GetAll(), filter)
+	if err != nil {
+		return nil, err
+	}
 	var formatter BlobFormatter
 	if b.partition.Keys == nil {
 		formatter = CreateFormatter(b.blob, b.format)
@@ -563,6 +681,9 @@ func (b *Blob) Update(updateRecord diskModels.PageRecord, filterItems []FilterIt
 	if err != nil {
 		return nil, err
 	}
+	if err = b.checkUniqueUpdate(pages, filter, updateRecordFormatted); err != nil {
+		return nil, err
+	}
 	total := PageRecordsMap{}
 	var wg sync.WaitGroup
 	for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
@@ -587,7 +708,7 @@ func (b *Blob) Update(updateRecord diskModels.PageRecord, filterItems []FilterIt
 			currentFileIndex++
 		}
 	}
-	return total, nil
+	return total, b.keyIndexMap.UpdateRecords(total, updateRecordFormatted)
 }
 
 func (b *Blob) DeleteByIndex(pageRecordId string) (PageRecordsMap, error) {
@@ -630,14 +751,20 @@ func (b *Blob) DeleteByIndex(pageRecordId string) (PageRecordsMap, error) {
 			if err != nil {
 				return PageRecordsMap{}, err
 			}
-			return PageRecordsMap{
+			if b.IsPartition() {
+				if err = b.updatePartitionCatalog(deletedRecord, -1); err != nil {
+					return PageRecordsMap{}, err
+				}
+			}
+			total := PageRecordsMap{
 				pageFile: {
 					pageRecordId: deletedRecord,
 				},
-			}, nil
+			}
+			return total, b.keyIndexMap.DeleteRecords(total)
 		}
 	}
-	return PageRecordsMap{}, nil
+	return PageRecordsMap{}, RecordNotFoundError{PageRecordId: pageRecordId}
 }
 
 func (b *Blob) DeleteByPartition(searchPartition SearchPartition, filterItems []FilterItem) (PageRecordsMap, error) {
@@ -655,12 +782,21 @@ func (b *Blob) DeleteByPartition(searchPartition SearchPartition, filterItems []
 	if err != nil {
 		return PageRecordsMap{}, err
 	}
+	hashKeyFiles = b.FilterBucketKeyFiles(hashKeyFiles, filter)
+	if err = b.ensurePartitionCatalog(); err != nil {
+		return PageRecordsMap{}, err
+	}
 	total := PageRecordsMap{}
 	for _, hashKeyFile := range hashKeyFiles {
 		pages, err := b.partitionMap.GetByHash(hashKeyFile)
 		if err != nil {
 			return total, err
 		}
+		pages, err = b.filterIndexedPages(pages, filter)
+		if err != nil {
+			return total, err
+		}
+		deleted := 0
 		var wg sync.WaitGroup
 		for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
 			var groups [memoryConstants.SearchThreadCount]diskModels.PageRecords
@@ -685,11 +821,17 @@ func (b *Blob) DeleteByPartition(searchPartition SearchPartition, filterItems []
 					pageRecordIds = append(pageRecordIds, pageRecordId)
 				}
 				total[pages[currentFileIndex].GetFileName()] = groupItem
+				deleted += len(groupItem)
 				currentFileIndex++
 			}
 		}
+		if deleted > 0 {
+			if err = b.partitionMap.UpdateCatalog(hashKeyFile, nil, -deleted); err != nil {
+				return total, err
+			}
+		}
 	}
-	return total, nil
+	return total, b.keyIndexMap.DeleteRecords(total)
 }
 
 func (b *Blob) Delete(filterItems []FilterItem) (PageRecordsMap, error) {
@@ -700,7 +842,10 @@ func (b *Blob) Delete(filterItems []FilterItem) (PageRecordsMap, error) {
 	if err != nil {
 		return PageRecordsMap{}, err
 	}
-	pages := b.pageMap.GetAll()
+	pages, err := b.filterIndexedPages(b.pageMap.GetAll(), filter)
+	if err != nil {
+		return PageRecordsMap{}, err
+	}
 	total := PageRecordsMap{}
 	var wg sync.WaitGroup
 	for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
@@ -729,67 +874,579 @@ func (b *Blob) Delete(filterItems []FilterItem) (PageRecordsMap, error) {
 			currentFileIndex++
 		}
 	}
-	return total, nil
+	return total, b.keyIndexMap.DeleteRecords(total)
 }
 
-func (b *Blob) SearchPage(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int) {
-	defer wg.Done()
-	if page == nil {
-		return
+func (b *Blob) DropPartition(searchPartition SearchPartition) (PageRecordsMap, error) {
+	if !b.IsPartition() {
+		return PageRecordsMap{}, fmt.Errorf("blob %s is not partitioned", b.blob)
 	}
-	var formatter BlobFormatter
-	if b.IsPartition() {
-		formatter = CreateFormatterWithPartition(b.blob, b.format, b.partition)
-	} else {
-		formatter = CreateFormatter(b.blob, b.format)
+	for _, partitionKey := range b.partition.Keys {
+		if _, ok := searchPartition[partitionKey]; !ok {
+			return PageRecordsMap{}, fmt.Errorf("partition key %s is required to drop a partition", partitionKey)
+		}
 	}
-	groupItem := diskModels.PageRecords{}
-	pageData, err := page.Read()
+	b.m.Lock()
+	defer b.m.Unlock()
+	hashKeyFiles, err := b.FilterHashKeyFiles(b.partitionMap.GetAllHashKeys(), searchPartition)
 	if err != nil {
-		return
+		return PageRecordsMap{}, err
 	}
-	for key, record := range pageData {
-		if passes, _ := filter.Passes(record); passes {
-			formattedRecord, err := formatter.FormatRecord(record)
-			if err == nil {
-				groupItem[key] = formattedRecord
+	if len(hashKeyFiles) == 0 {
+		partitionValues := []string{}
+		for _, partitionKey := range b.partition.Keys {
+			partitionValues = append(partitionValues, fmt.Sprintf("%s=%v", partitionKey, searchPartition[partitionKey]))
+		}
+		return PageRecordsMap{}, fmt.Errorf("partition %s not found in %s", strings.Join(partitionValues, ","), b.blob)
+	}
+	if err = b.ensurePartitionCatalog(); err != nil {
+		return PageRecordsMap{}, err
+	}
+	total := PageRecordsMap{}
+	for _, hashKeyFile := range hashKeyFiles {
+		pages, err := b.partitionMap.GetByHash(hashKeyFile)
+		if err != nil {
+			return total, err
+		}
+		dropped := PageRecordsMap{}
+		pageRecordIds := []string{}
+		for _, page := range pages {
+			data, err := page.Read()
+			if err != nil {
+				return total, err
+			}
+			dropped[page.GetFileName()] = data
+			for pageRecordId := range data {
+				pageRecordIds = append(pageRecordIds, pageRecordId)
+			}
+		}
+		if err = b.deleteIndexes(pageRecordIds); err != nil {
+			return total, err
+		}
+		if err = b.keyIndexMap.DeleteRecords(dropped); err != nil {
+			return total, err
+		}
+		for _, page := range pages {
+			if isPhantomFile, err := b.pageMap.Delete(page.GetFileName()); err != nil && !isPhantomFile {
+				return total, err
 			}
 		}
+		if err = b.partitionMap.Drop(hashKeyFile); err != nil {
+			return total, err
+		}
+		for pageFile, data := range dropped {
+			total[pageFile] = data
+		}
 	}
-	groups[index] = groupItem
+	return total, nil
 }
 
-func (b *Blob) SearchPageUpdate(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int, updateRecordFormatted diskModels.PageRecord) {
-	defer wg.Done()
-	if page == nil {
-		return
-	}
-	groupItem := diskModels.PageRecords{}
-	pageData, err := page.Read()
-	if err != nil {
-		return
+func (b *Blob) Truncate() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if err := b.pageMap.Truncate(); err != nil {
+		return err
 	}
-	affected := false
-	for pageRecordId, record := range pageData {
-		if passes, _ := filter.Passes(record); passes {
-			for key, value := range updateRecordFormatted {
-				pageData[pageRecordId][key] = value
-			}
-			groupItem[pageRecordId] = pageData[pageRecordId]
-			affected = true
-		}
+	if err := b.indexMap.Truncate(); err != nil {
+		return err
 	}
-	if affected {
-		err = page.Write(pageData)
-		if err != nil {
-			return
+	if b.IsPartition() {
+		if err := b.partitionMap.Truncate(); err != nil {
+			return err
 		}
 	}
-	groups[index] = groupItem
+	return b.keyIndexMap.Truncate()
 }
 
-func (b *Blob) SearchPageDelete(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int) {
-	defer wg.Done()
+func (b *Blob) CreateKeyIndex(key string, indexType string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.createKeyIndex(key, indexType)
+}
+
+func (b *Blob) createKeyIndex(key string, indexType string) error {
+	formatItem, ok := b.format[key]
+	if !ok {
+		return fmt.Errorf("key %s does not exist in %s", key, b.blob)
+	}
+	if indexType == "" {
+		indexType = memoryConstants.KeyIndexHash
+	}
+	if !slices.Contains(memoryConstants.GetKeyIndexTypes(formatItem.KeyType), indexType) {
+		return fmt.Errorf("index type %s not allowed on key %s of type %s", indexType, key, formatItem.KeyType)
+	}
+	pageRecordsMap := PageRecordsMap{}
+	for _, page := range b.pageMap.GetAll() {
+		pageRecords, err := page.Read()
+		if err != nil {
+			return err
+		}
+		pageRecordsMap[page.GetFileName()] = pageRecords
+	}
+	return b.keyIndexMap.Create(diskModels.KeyIndexItem{Key: key, IndexType: indexType}, pageRecordsMap)
+}
+
+func (b *Blob) migrate() error {
+	migrations, err := b.formatDiskManager.GetMigrations(b.db, b.blob)
+	if err != nil {
+		return err
+	}
+	for _, migration := range memoryConstants.GetMigrations() {
+		if slices.Contains(migrations, migration) {
+			continue
+		}
+		switch migration {
+		case memoryConstants.MigrationUTCDateTime:
+			err = b.normalizeLegacyDateTimes()
+		case memoryConstants.MigrationUniqueKeyIndexes:
+			err = b.ensureUniqueKeyIndexes()
+		case memoryConstants.MigrationShardedKeyIndexes:
+			err = b.rebuildKeyIndexes()
+		}
+		if err != nil {
+			return err
+		}
+		migrations = append(migrations, migration)
+		if err = b.formatDiskManager.WriteMigrations(b.db, b.blob, migrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Blob) normalizeLegacyDateTimes() error {
+	if !hasDateTimeKeys(b.format) {
+		return nil
+	}
+	rewritten := false
+	for _, page := range b.pageMap.GetAll() {
+		pageRecords, err := page.Read()
+		if err != nil {
+			return err
+		}
+		changed := false
+		for _, pageRecord := range pageRecords {
+			for key, value := range pageRecord {
+				formatItem, ok := b.format[key]
+				if !ok || slices.Contains(b.partition.Keys, key) {
+					continue
+				}
+				normalizedValue, normalized := normalizeLegacyValue(value, formatItem)
+				pageRecord[key] = normalizedValue
+				changed = changed || normalized
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err = page.Write(pageRecords); err != nil {
+			return err
+		}
+		rewritten = true
+	}
+	if !rewritten {
+		return nil
+	}
+	for key, keyIndexItem := range b.keyIndexMap.GetAll() {
+		if b.format[key].KeyType != memoryConstants.DateTime {
+			continue
+		}
+		if err := b.keyIndexMap.Delete(key); err != nil {
+			return err
+		}
+		if err := b.createKeyIndex(key, keyIndexItem.IndexType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Blob) DeleteKeyIndex(key string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.keyIndexMap.Delete(key)
+}
+
+func (b *Blob) GetFormat() diskModels.Format {
+	b.m.Lock()
+	defer b.m.Unlock()
+	format := diskModels.Format{}
+	for key, formatItem := range b.format {
+		format[key] = formatItem
+	}
+	return format
+}
+
+func (b *Blob) GetKeyIndexes() diskModels.KeyIndexes {
+	if b.keyIndexMap == nil {
+		return diskModels.KeyIndexes{}
+	}
+	return b.keyIndexMap.GetAll()
+}
+
+func (b *Blob) filterIndexedPages(pages []*Page, filter Filter) ([]*Page, error) {
+	pageFiles, ok, err := b.keyIndexMap.GetPageFiles(filter.FilterItems)
+	if err != nil || !ok {
+		return pages, err
+	}
+	indexedPages := []*Page{}
+	for _, page := range pages {
+		if page != nil && pageFiles[page.GetFileName()] {
+			indexedPages = append(indexedPages, page)
+		}
+	}
+	return indexedPages, nil
+}
+
+func (b *Blob) searchPages(pages []*Page, filter Filter, getOperationParams GetOperationParams) PageRecordItems {
+	var wg sync.WaitGroup
+	total := PageRecordsMap{}
+	for i := 0; i < len(pages); i += memoryConstants.SearchThreadCount {
+		var groups [memoryConstants.SearchThreadCount]diskModels.PageRecords
+		threadItem := i
+		threadIndex := 0
+		for threadItem < len(pages) && threadIndex < memoryConstants.SearchThreadCount {
+			wg.Add(1)
+			go b.SearchPage(pages[threadItem], filter, &groups, &wg, threadIndex)
+			threadIndex++
+			threadItem++
+		}
+		wg.Wait()
+		currentFileIndex := i
+
+		for _, groupItem := range groups {
+			if len(groupItem) == 0 {
+				currentFileIndex++
+				continue
+			}
+			total[pages[currentFileIndex].GetFileName()] = groupItem
+			currentFileIndex++
+		}
+		if getOperationParams.IsSatisfied(total.Count()) {
+			break
+		}
+	}
+	return getOperationParams.Apply(total.ToItems(), b.format)
+}
+
+func (b *Blob) searchOrderedPages(pages []*Page, filter Filter, getOperationParams GetOperationParams) (PageRecordItems, bool, error) {
+	if getOperationParams.Limit == 0 || len(getOperationParams.Sort) == 0 {
+		return nil, false, nil
+	}
+	orderedKeyIndex, ok := b.keyIndexMap.GetOrdered(getOperationParams.Sort[0].Key)
+	if !ok {
+		return nil, false, nil
+	}
+	pageMap := make(map[string]*Page)
+	for _, page := range pages {
+		if page != nil {
+			pageMap[page.GetFileName()] = page
+		}
+	}
+	formatter := b.getFormatter()
+	required := getOperationParams.Offset + getOperationParams.Limit
+	pageRecordsMap := PageRecordsMap{}
+	pageRecordItems := PageRecordItems{}
+	var lastEntry diskModels.OrderedIndexEntry
+	var lastIsNull bool
+	var walkErr error
+	err := orderedKeyIndex.Walk(getOperationParams.Sort[0].Direction, func(entry diskModels.OrderedIndexEntry, isNull bool) bool {
+		if len(pageRecordItems) >= required && (isNull != lastIsNull || entry.Value != lastEntry.Value) {
+			return false
+		}
+		page, ok := pageMap[entry.PageFile]
+		if !ok {
+			return true
+		}
+		pageRecords, ok := pageRecordsMap[entry.PageFile]
+		if !ok {
+			if pageRecords, walkErr = page.Read(); walkErr != nil {
+				return false
+			}
+			pageRecordsMap[entry.PageFile] = pageRecords
+		}
+		pageRecord, ok := pageRecords[entry.PageRecordId]
+		if !ok {
+			return true
+		}
+		var passes bool
+		if passes, walkErr = filter.Passes(pageRecord); walkErr != nil {
+			return false
+		}
+		if !passes {
+			return true
+		}
+		formattedRecord, err := formatter.FormatRecord(pageRecord)
+		if err != nil {
+			return true
+		}
+		pageRecordItems = append(pageRecordItems, PageRecordItem{
+			PageFile:     entry.PageFile,
+			PageRecordId: entry.PageRecordId,
+			PageRecord:   formattedRecord,
+		})
+		lastEntry, lastIsNull = entry, isNull
+		return true
+	})
+	if err == nil {
+		err = walkErr
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return getOperationParams.Apply(pageRecordItems, b.format), true, nil
+}
+
+func (b *Blob) getFormatter() BlobFormatter {
+	if b.IsPartition() {
+		return CreateFormatterWithPartition(b.blob, b.format, b.partition)
+	}
+	return CreateFormatter(b.blob, b.format)
+}
+
+func (b *Blob) checkUnique(pageRecords diskModels.PageRecords) error {
+	for _, uniqueKeys := range b.format.GetUniqueKeys() {
+		uniqueValues := make(map[string]string)
+		inValues := []any{}
+		for pageRecordId, pageRecord := range pageRecords {
+			uniqueValue, ok := b.getUniqueValue(uniqueKeys, pageRecord)
+			if !ok {
+				continue
+			}
+			if conflictId, ok := uniqueValues[uniqueValue]; ok {
+				return b.uniqueError(uniqueKeys, pageRecordId, conflictId, pageRecord)
+			}
+			uniqueValues[uniqueValue] = pageRecordId
+			inValues = append(inValues, pageRecord[uniqueKeys[0]])
+		}
+		if len(uniqueValues) == 0 {
+			continue
+		}
+		pages, err := b.filterIndexedPages(b.pageMap.GetAll(), Filter{
+			FilterItems: []FilterItem{{Key: uniqueKeys[0], Op: memoryConstants.OpIn, Value: inValues}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, page := range pages {
+			if page == nil {
+				continue
+			}
+			pageData, err := page.Read()
+			if err != nil {
+				return err
+			}
+			for pageRecordId, pageRecord := range pageData {
+				if _, ok := pageRecords[pageRecordId]; ok {
+					continue
+				}
+				uniqueValue, ok := b.getUniqueValue(uniqueKeys, pageRecord)
+				if !ok {
+					continue
+				}
+				if conflictId, ok := uniqueValues[uniqueValue]; ok {
+					return b.uniqueError(uniqueKeys, conflictId, pageRecordId, pageRecords[conflictId])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Blob) checkUniqueData(uniqueKeys []string) error {
+	uniqueValues := make(map[string]string)
+	for _, page := range b.pageMap.GetAll() {
+		pageData, err := page.Read()
+		if err != nil {
+			return err
+		}
+		for pageRecordId, pageRecord := range pageData {
+			uniqueValue, ok := b.getUniqueValue(uniqueKeys, pageRecord)
+			if !ok {
+				continue
+			}
+			if conflictId, ok := uniqueValues[uniqueValue]; ok {
+				return b.uniqueError(uniqueKeys, pageRecordId, conflictId, pageRecord)
+			}
+			uniqueValues[uniqueValue] = pageRecordId
+		}
+	}
+	return nil
+}
+
+func (b *Blob) ensureUniqueKeyIndexes() error {
+	keyIndexes := b.keyIndexMap.GetAll()
+	for _, uniqueKeys := range b.format.GetUniqueKeys() {
+		key := uniqueKeys[0]
+		if _, ok := keyIndexes[key]; ok {
+			continue
+		}
+		if !slices.Contains(memoryConstants.GetKeyIndexTypes(b.format[key].KeyType), memoryConstants.KeyIndexHash) {
+			continue
+		}
+		if err := b.createKeyIndex(key, memoryConstants.KeyIndexHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Blob) rebuildKeyIndexes() error {
+	for key, keyIndexItem := range b.keyIndexMap.GetAll() {
+		if err := b.keyIndexMap.Delete(key); err != nil {
+			return err
+		}
+		if err := b.createKeyIndex(key, keyIndexItem.IndexType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Blob) getUniqueValue(uniqueKeys []string, pageRecord diskModels.PageRecord) (string, bool) {
+	values := []string{}
+	for _, key := range uniqueKeys {
+		value, ok := GetKeyIndexValue(pageRecord[key], b.format[key].KeyType)
+		if !ok {
+			return "", false
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, "\x00"), true
+}
+
+func (b *Blob) uniqueError(uniqueKeys []string, pageRecordId string, conflictId string, pageRecord diskModels.PageRecord) error {
+	values := []string{}
+	for _, key := range uniqueKeys {
+		values = append(values, fmt.Sprintf("%s=%v", key, pageRecord[key]))
+	}
+	return errors.New(fmt.Sprintf(
+		"unique constraint on %s violated by record %s: record %s already has %s",
+		strings.Join(uniqueKeys, ", "),
+		pageRecordId,
+		conflictId,
+		strings.Join(values, ", "),
+	))
+}
+
+func (b *Blob) touchesUniqueKey(updateRecord diskModels.PageRecord) bool {
+	for key := range updateRecord {
+		if b.format.IsUniqueKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Blob) checkUniqueUpdate(pages []*Page, filter Filter, updateRecordFormatted diskModels.PageRecord) error {
+	if !b.touchesUniqueKey(updateRecordFormatted) {
+		return nil
+	}
+	pageRecords := diskModels.PageRecords{}
+	for _, pageRecordItem := range b.searchPages(pages, filter, GetOperationParams{}) {
+		pageRecord := diskModels.PageRecord{}
+		for key, value := range pageRecordItem.PageRecord {
+			pageRecord[key] = value
+		}
+		for key, value := range updateRecordFormatted {
+			pageRecord[key] = value
+		}
+		pageRecords[pageRecordItem.PageRecordId] = pageRecord
+	}
+	return b.checkUnique(pageRecords)
+}
+
+func (b *Blob) getPartitionPages(searchPartition SearchPartition, filter Filter) ([]*Page, error) {
+	hashKeyFiles, err := b.FilterHashKeyFiles(b.partitionMap.GetAllHashKeys(), searchPartition)
+	if err != nil {
+		return nil, err
+	}
+	hashKeyFiles = b.FilterBucketKeyFiles(hashKeyFiles, filter)
+	pages := []*Page{}
+	for _, hashKeyFile := range hashKeyFiles {
+		hashPages, err := b.partitionMap.GetByHash(hashKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, hashPages...)
+	}
+	slices.SortFunc(pages, func(a *Page, b *Page) int {
+		return strings.Compare(a.GetFileName(), b.GetFileName())
+	})
+	return pages, nil
+}
+
+func (b *Blob) SearchPageAggregate(page *Page, filter Filter, aggregateParams AggregateParams, groups *[memoryConstants.SearchThreadCount]AggregateGroups, wg *sync.WaitGroup, index int) {
+	defer wg.Done()
+	if page == nil {
+		return
+	}
+	groupItem := AggregateGroups{}
+	pageData, err := page.Read()
+	if err != nil {
+		return
+	}
+	for _, record := range pageData {
+		if passes, _ := filter.Passes(record); passes {
+			aggregateParams.Accumulate(groupItem, record, b.format)
+		}
+	}
+	groups[index] = groupItem
+}
+
+func (b *Blob) SearchPage(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int) {
+	defer wg.Done()
+	if page == nil {
+		return
+	}
+	formatter := b.getFormatter()
+	groupItem := diskModels.PageRecords{}
+	pageData, err := page.Read()
+	if err != nil {
+		return
+	}
+	for key, record := range pageData {
+		if passes, _ := filter.Passes(record); passes {
+			formattedRecord, err := formatter.FormatRecord(record)
+			if err == nil {
+				groupItem[key] = formattedRecord
+			}
+		}
+	}
+	groups[index] = groupItem
+}
+
+func (b *Blob) SearchPageUpdate(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int, updateRecordFormatted diskModels.PageRecord) {
+	defer wg.Done()
+	if page == nil {
+		return
+	}
+	groupItem := diskModels.PageRecords{}
+	pageData, err := page.Read()
+	if err != nil {
+		return
+	}
+	affected := false
+	for pageRecordId, record := range pageData {
+		if passes, _ := filter.Passes(record); passes {
+			for key, value := range updateRecordFormatted {
+				pageData[pageRecordId][key] = value
+			}
+			groupItem[pageRecordId] = pageData[pageRecordId]
+			affected = true
+		}
+	}
+	if affected {
+		err = page.Write(pageData)
+		if err != nil {
+			return
+		}
+	}
+	groups[index] = groupItem
+}
+
+func (b *Blob) SearchPageDelete(page *Page, filter Filter, groups *[memoryConstants.SearchThreadCount]diskModels.PageRecords, wg *sync.WaitGroup, index int) {
+	defer wg.Done()
 	groupItem := diskModels.PageRecords{}
 	if page == nil {
 		return
@@ -812,7 +1469,7 @@ func (b *Blob) SearchPageDelete(page *Page, filter Filter, groups *[memoryConsta
 		if len(pageData) == 0 {
 			isPhantomFile, err := b.pageMap.Delete(page.GetFileName())
 			if (err == nil || isPhantomFile) && b.partition.Keys != nil {
-				hashKey, err := b.partitionDiskManager.GetHashKey(b.partition, groupItem[pageRecordIds[0]])
+				hashKey, err := b.getHashKey(groupItem[pageRecordIds[0]])
 				if err == nil {
 					_ = b.partitionMap.Delete(hashKey, page.GetFileName())
 				}
@@ -823,31 +1480,40 @@ func (b *Blob) SearchPageDelete(page *Page, filter Filter, groups *[memoryConsta
 				return
 			}
 		}
-		b.deleteIndexes(pageRecordIds)
+		_ = b.deleteIndexes(pageRecordIds)
 	}
 	groups[index] = groupItem
 }
 
 func (b *Blob) FilterHashKeyFiles(hashKeys []string, searchPartition SearchPartition) ([]string, error) {
+	valueHashes := make(map[string]string)
+	for _, partitionKey := range b.partition.Keys {
+		value, ok := searchPartition[partitionKey]
+		if !ok {
+			continue
+		}
+		valueHash, err := b.getPartitionKeyItem(partitionKey, value)
+		if err != nil {
+			return nil, err
+		}
+		valueHashes[partitionKey] = valueHash
+	}
 	var foundFiles []string
 	for _, partitionHashKeyFileName := range hashKeys {
 		currentChar := 0
 		found := true
 		for _, partitionKey := range b.partition.Keys {
-			_, ok := searchPartition[partitionKey]
-			if !ok {
-				currentChar += 28
-				continue
-			}
-			valueHash, err := b.partitionDiskManager.GetHashKeyItem(partitionKey, diskModels.PageRecord(searchPartition))
-			if err != nil {
-				return nil, err
+			keyLength := GetPartitionKeyLength(b.partition, partitionKey)
+			if currentChar+keyLength > len(partitionHashKeyFileName) {
+				found = false
+				break
 			}
-			if partitionHashKeyFileName[currentChar:currentChar+len(valueHash)] != valueHash {
+			valueHash, ok := valueHashes[partitionKey]
+			if ok && partitionHashKeyFileName[currentChar:currentChar+keyLength] != valueHash {
 				found = false
 				break
 			}
-			currentChar += 28
+			currentChar += keyLength
 		}
 		if found {
 			foundFiles = append(foundFiles, partitionHashKeyFileName)
@@ -856,11 +1522,155 @@ func (b *Blob) FilterHashKeyFiles(hashKeys []string, searchPartition SearchParti
 	return foundFiles, nil
 }
 
+func (b *Blob) FilterBucketKeyFiles(hashKeys []string, filter Filter) []string {
+	if len(b.partition.Buckets) == 0 {
+		return hashKeys
+	}
+	foundFiles := []string{}
+	for _, partitionHashKeyFileName := range hashKeys {
+		if b.bucketsMayPass(partitionHashKeyFileName, filter) {
+			foundFiles = append(foundFiles, partitionHashKeyFileName)
+		}
+	}
+	return foundFiles
+}
+
+func (b *Blob) bucketsMayPass(partitionHashKeyFileName string, filter Filter) bool {
+	currentChar := 0
+	for _, partitionKey := range b.partition.Keys {
+		keyLength := GetPartitionKeyLength(b.partition, partitionKey)
+		if currentChar+keyLength > len(partitionHashKeyFileName) {
+			return true
+		}
+		label := partitionHashKeyFileName[currentChar : currentChar+keyLength]
+		currentChar += keyLength
+		bucket, ok := b.partition.Buckets[partitionKey]
+		if !ok {
+			continue
+		}
+		lower, upper, err := GetBucketRange(bucket, label)
+		if err != nil {
+			continue
+		}
+		for _, filterItem := range filter.FilterItems {
+			if filterItem.Key == partitionKey && !BucketMayPass(filterItem, b.format[partitionKey].KeyType, lower, upper) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *Blob) GetPartitions() ([]diskModels.PageRecord, error) {
+	if !b.IsPartition() {
+		return nil, fmt.Errorf("blob %s is not partitioned", b.blob)
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	if err := b.ensurePartitionCatalog(); err != nil {
+		return nil, err
+	}
+	catalog, _ := b.partitionMap.GetCatalog()
+	return catalog.ConvertToPageRecords(), nil
+}
+
+func (b *Blob) ensurePartitionCatalog() error {
+	if _, ok := b.partitionMap.GetCatalog(); ok {
+		return nil
+	}
+	catalog := diskModels.PartitionCatalog{}
+	for _, hashKeyFile := range b.partitionMap.GetAllHashKeys() {
+		pages, err := b.partitionMap.GetByHash(hashKeyFile)
+		if err != nil {
+			return err
+		}
+		catalogItem := diskModels.PartitionCatalogItem{Pages: len(pages)}
+		for _, page := range pages {
+			data, err := page.Read()
+			if err != nil {
+				return err
+			}
+			for _, pageRecord := range data {
+				if catalogItem.Values == nil {
+					catalogItem.Values = b.getPartitionValues(pageRecord)
+				}
+				catalogItem.Records++
+			}
+		}
+		catalog[hashKeyFile] = catalogItem
+	}
+	return b.partitionMap.SetCatalog(catalog)
+}
+
+func (b *Blob) updatePartitionCatalog(pageRecord diskModels.PageRecord, recordDelta int) error {
+	if err := b.ensurePartitionCatalog(); err != nil {
+		return err
+	}
+	hashKey, err := b.getHashKey(pageRecord)
+	if err != nil {
+		return err
+	}
+	return b.partitionMap.UpdateCatalog(hashKey, b.getPartitionValues(pageRecord), recordDelta)
+}
+
+func (b *Blob) getPartitionValues(pageRecord diskModels.PageRecord) map[string]any {
+	values := make(map[string]any)
+	for _, partitionKey := range b.partition.Keys {
+		value := pageRecord[partitionKey]
+		if bucket, ok := b.partition.Buckets[partitionKey]; ok {
+			label, err := GetBucketLabel(bucket, value, b.format[partitionKey].KeyType)
+			if err == nil {
+				value, err = DescribeBucketLabel(bucket, label)
+			}
+			if err != nil {
+				value = nil
+			}
+		}
+		values[partitionKey] = value
+	}
+	return values
+}
+
+func (b *Blob) getHashKey(pageRecord diskModels.PageRecord) (string, error) {
+	if len(b.partition.Buckets) == 0 {
+		return b.partitionDiskManager.GetHashKey(b.partition, pageRecord)
+	}
+	partitionRecord := diskModels.PageRecord{}
+	for _, partitionKey := range b.partition.Keys {
+		value, ok := pageRecord[partitionKey]
+		if !ok {
+			continue
+		}
+		if bucket, ok := b.partition.Buckets[partitionKey]; ok {
+			label, err := GetBucketLabel(bucket, value, b.format[partitionKey].KeyType)
+			if err != nil {
+				return "", err
+			}
+			value = label
+		}
+		partitionRecord[partitionKey] = value
+	}
+	return b.partitionDiskManager.GetHashKey(b.partition, partitionRecord)
+}
+
+func (b *Blob) getPartitionKeyItem(partitionKey string, value any) (string, error) {
+	formatter := BlobFormatter{}
+	convertedValue, err := formatter.convertRecordValue(value, b.format[partitionKey])
+	if err != nil {
+		return "", err
+	}
+	bucket, ok := b.partition.Buckets[partitionKey]
+	if !ok {
+		return b.partitionDiskManager.GetHashKeyItem(partitionKey, diskModels.PageRecord{partitionKey: convertedValue})
+	}
+	return GetBucketLabel(bucket, convertedValue, b.format[partitionKey].KeyType)
+}
+
 func (b *Blob) IsPartition() bool {
 	return b.partition.Keys != nil
 }
 
-func (b *Blob) addRecordsByPartition(hashKeyFile string, insertPageRecords []diskModels.PageRecord) (PageRecordsMap, error) {
+func (b *Blob) addRecordsByPartition(hashKeyFile string, insertPageRecords diskModels.PageRecords) (PageRecordsMap, error) {
 	pages, err := b.partitionMap.GetByHash(hashKeyFile)
 	if err != nil {
 		return PageRecordsMap{}, err
@@ -883,12 +1693,10 @@ func (b *Blob) addRecordsByPartition(hashKeyFile string, insertPageRecords []dis
 	if err != nil {
 		return PageRecordsMap{}, err
 	}
-	lastPageRecordId := ""
 	total := PageRecordsMap{}
 	total[currentPage.GetFileName()] = diskModels.PageRecords{}
 	indexes := diskModels.IndexRecords{}
-	for _, insertPageRecord := range insertPageRecords {
-		lastPageRecordId = uuid.New().String()
+	for lastPageRecordId, insertPageRecord := range insertPageRecords {
 		pageRecords[lastPageRecordId] = insertPageRecord
 		total[currentPage.GetFileName()][lastPageRecordId] = insertPageRecord
 		indexes[lastPageRecordId] = currentPage.GetFileName()
@@ -918,6 +1726,47 @@ func (b *Blob) addRecordsByPartition(hashKeyFile string, insertPageRecords []dis
 	return total, b.addIndexes(indexes)
 }
 
+func (b *Blob) splitRecordId(insertPageRecord diskModels.PageRecord) (string, diskModels.PageRecord, error) {
+	value, ok := insertPageRecord[memoryConstants.IdKey]
+	if !ok {
+		return uuid.New().String(), insertPageRecord, nil
+	}
+	pageRecordId, ok := value.(string)
+	if !ok || pageRecordId == "" {
+		return "", nil, fmt.Errorf("%s %+v must be a non empty string", memoryConstants.IdKey, value)
+	}
+	if b.recordIdExists(pageRecordId) {
+		return "", nil, fmt.Errorf("record with id %s already exists", pageRecordId)
+	}
+	pageRecord := diskModels.PageRecord{}
+	for key, value := range insertPageRecord {
+		if key != memoryConstants.IdKey {
+			pageRecord[key] = value
+		}
+	}
+	return pageRecordId, pageRecord, nil
+}
+
+func (b *Blob) recordIdExists(pageRecordId string) bool {
+	indexFiles, err := b.indexMap.GetByPrefix(b.indexDiskManager.GetPageRecordIdPrefix(pageRecordId))
+	if err != nil {
+		return false
+	}
+	for _, indexFile := range indexFiles {
+		if indexFile == nil {
+			continue
+		}
+		indexRecords, err := indexFile.Read()
+		if err != nil {
+			continue
+		}
+		if _, ok := indexRecords[pageRecordId]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *Blob) addIndexes(indexes diskModels.IndexRecords) error {
 	indexFileMap := make(map[string]diskModels.IndexRecords)
 	indexPrefixMap := make(map[string]string)
@@ -968,7 +1817,7 @@ func (b *Blob) addIndexes(indexes diskModels.IndexRecords) error {
 	return nil
 }
 
-func (b *Blob) deleteIndexes(pageRecordIds []string) {
+func (b *Blob) deleteIndexes(pageRecordIds []string) error {
 	pageRecordIdMap := make(map[string][]string)
 	indexMap := make(map[string][]*Index)
 	for _, pageRecordId := range pageRecordIds {
@@ -977,7 +1826,7 @@ func (b *Blob) deleteIndexes(pageRecordIds []string) {
 			pageRecordIdMap[prefix] = []string{}
 			indexes, err := b.indexMap.GetByPrefix(prefix)
 			if err != nil {
-				continue
+				return err
 			}
 			indexMap[prefix] = indexes
 		}
@@ -987,9 +1836,13 @@ func (b *Blob) deleteIndexes(pageRecordIds []string) {
 	for prefix, indexes := range indexMap {
 		for _, index := range indexes {
 			length, err := index.Delete(pageRecordIdMap[prefix])
-			if err == nil && length == 0 {
+			if err != nil {
+				return err
+			}
+			if length == 0 {
 				_ = b.indexMap.Delete(prefix, index.GetFileName())
 			}
 		}
 	}
+	return nil
 }
@@ -1,15 +1,19 @@
 package memoryModels
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/test/utils"
 	"github.com/stretchr/testify/assert"
+	"io/fs"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func createTestBlobMap(db string, dataLocation string, dataCaching bool, m sync.Locker) BlobMap {
@@ -44,6 +48,7 @@ func createTestBlob(
 		format:               format,
 		indexDiskManager:     diskManagers.MockIndexManagerInstance,
 		partitionDiskManager: diskManagers.MockPartitionManagerInstance,
+		formatDiskManager:    diskManagers.MockFormatManagerInstance,
 	}
 }
 
@@ -205,6 +210,18 @@ func TestUnit_Get_FailsOnGetBlobError(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestUnit_Get_ReturnsBlobNotFoundOnMissingBlob(t *testing.T) {
+	m := testUtils.CreateMockMutex(func() {}, func() {})
+	blobMap := createTestBlobMap("db", "dataLocation", true, m)
+	blobMap.createBlobFunc = func(db string, blob string, dataLocation string, dataCaching bool) (Blob, error) {
+		return Blob{}, fs.ErrNotExist
+	}
+
+	_, err := blobMap.Get("blob")
+
+	assert.Equal(t, BlobNotFoundError{DB: "db", Blob: "blob"}, err)
+}
+
 func TestUnit_Delete_DeletesBlob(t *testing.T) {
 	expectedDB := "db"
 	expectedDataLocation := "dataLocation"
@@ -396,6 +413,7 @@ func TestUnit_CreateBlob_CreatesBlob(t *testing.T) {
 	getFormatCalled := false
 	getAllPagesCalled := false
 	getAllIndexesCalled := false
+	getAllKeyIndexesCalled := false
 	getPartitionCalled := false
 	getAllHashKeysCalled := false
 	getByHashKeyCalled := false
@@ -405,6 +423,9 @@ func TestUnit_CreateBlob_CreatesBlob(t *testing.T) {
 		assert.Equal(t, expectedBlob, blob)
 		return expectedFormat, nil
 	}
+	diskManagers.MockFormatManagerInstance.GetAlterFunc = func(db string, blob string) (*diskModels.Alter, error) {
+		return nil, nil
+	}
 	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
 		getAllPagesCalled = true
 		assert.Equal(t, expectedDB, db)
@@ -437,11 +458,18 @@ func TestUnit_CreateBlob_CreatesBlob(t *testing.T) {
 		return diskModels.PartitionPages{}, nil
 	}
 
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		getAllKeyIndexesCalled = true
+		assert.Equal(t, expectedDB, db)
+		assert.Equal(t, expectedBlob, blob)
+		return diskModels.KeyIndexes{}, nil
+	}
 	result, err := CreateBlob(expectedDB, expectedBlob, dataLocation, true)
 
 	assert.True(t, getFormatCalled)
 	assert.True(t, getAllPagesCalled)
 	assert.True(t, getAllIndexesCalled)
+	assert.True(t, getAllKeyIndexesCalled)
 	assert.True(t, getPartitionCalled)
 	assert.True(t, getAllHashKeysCalled)
 	assert.True(t, getByHashKeyCalled)
@@ -465,6 +493,7 @@ func TestUnit_CreateBlob_CreatesBlobWithoutPartition(t *testing.T) {
 	getFormatCalled := false
 	getAllPagesCalled := false
 	getAllIndexesCalled := false
+	getAllKeyIndexesCalled := false
 	getPartitionCalled := false
 	getAllHashKeysCalled := false
 	getByHashKeyCalled := false
@@ -472,6 +501,9 @@ func TestUnit_CreateBlob_CreatesBlobWithoutPartition(t *testing.T) {
 		getFormatCalled = true
 		return expectedFormat, nil
 	}
+	diskManagers.MockFormatManagerInstance.GetAlterFunc = func(db string, blob string) (*diskModels.Alter, error) {
+		return nil, nil
+	}
 	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
 		getAllPagesCalled = true
 		return diskModels.Pages{}, nil
@@ -493,11 +525,18 @@ func TestUnit_CreateBlob_CreatesBlobWithoutPartition(t *testing.T) {
 		return diskModels.PartitionPages{}, nil
 	}
 
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		getAllKeyIndexesCalled = true
+		assert.Equal(t, expectedDB, db)
+		assert.Equal(t, expectedBlob, blob)
+		return diskModels.KeyIndexes{}, nil
+	}
 	result, err := CreateBlob(expectedDB, expectedBlob, dataLocation, true)
 
 	assert.True(t, getFormatCalled)
 	assert.True(t, getAllPagesCalled)
 	assert.True(t, getAllIndexesCalled)
+	assert.True(t, getAllKeyIndexesCalled)
 	assert.True(t, getPartitionCalled)
 	assert.False(t, getAllHashKeysCalled)
 	assert.False(t, getByHashKeyCalled)
@@ -617,6 +656,7 @@ func TestUnit_CreateBlob_FailsOnInitializePartitionsError(t *testing.T) {
 	getFormatCalled := false
 	getAllPagesCalled := false
 	getAllIndexesCalled := false
+	getAllKeyIndexesCalled := false
 	getPartitionCalled := false
 	diskManagers.MockFormatManagerInstance.GetFunc = func(db string, blob string) (diskModels.Format, error) {
 		getFormatCalled = true
@@ -638,11 +678,18 @@ func TestUnit_CreateBlob_FailsOnInitializePartitionsError(t *testing.T) {
 		return []string{}, assert.AnError
 	}
 
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		getAllKeyIndexesCalled = true
+		assert.Equal(t, expectedDB, db)
+		assert.Equal(t, expectedBlob, blob)
+		return diskModels.KeyIndexes{}, nil
+	}
 	_, err := CreateBlob(expectedDB, expectedBlob, dataLocation, true)
 
 	assert.True(t, getFormatCalled)
 	assert.True(t, getAllPagesCalled)
 	assert.True(t, getAllIndexesCalled)
+	assert.True(t, getAllKeyIndexesCalled)
 	assert.True(t, getPartitionCalled)
 	assert.NotNil(t, err)
 }
@@ -1048,3 +1095,492 @@ func TestUnit_InitializeBlob_FailsOnPartitionsInitError(t *testing.T) {
 	assert.True(t, deleteBlobCalled)
 	assert.NotNil(t, err)
 }
+
+func createTestUniqueBlob(format diskModels.Format, data diskModels.PageRecords, writeCalled *bool) Blob {
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: "page1"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return data, nil
+	}
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, data diskModels.PageRecords) error {
+		*writeCalled = true
+		return nil
+	}
+	blob := createTestBlob("db", "members", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, format)
+	_ = blob.pageMap.Initialize()
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+	return blob
+}
+
+func TestUnit_Add_FailsOnUniqueConflictWithStoredRecord(t *testing.T) {
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"email": {KeyType: memoryConstants.String, Unique: true},
+		"name":  {KeyType: memoryConstants.String},
+	}, diskModels.PageRecords{
+		"existing-id": {"email": "a@test.com", "name": "a"},
+	}, &writeCalled)
+
+	_, err := blob.Add([]diskModels.PageRecord{{"email": "a@test.com", "name": "b"}})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "record existing-id already has email=a@test.com")
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_Add_FailsOnCompositeUniqueConflictWithinRecords(t *testing.T) {
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"org":  {KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"name"}},
+		"name": {KeyType: memoryConstants.String},
+	}, diskModels.PageRecords{}, &writeCalled)
+
+	_, err := blob.Add([]diskModels.PageRecord{
+		{"org": "a", "name": "b"},
+		{"org": "a", "name": "b"},
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unique constraint on org, name violated")
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_CheckUnique_PassesOnDistinctAndNilValues(t *testing.T) {
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"org":  {KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"name"}},
+		"name": {KeyType: memoryConstants.String},
+	}, diskModels.PageRecords{
+		"existing-id": {"org": "a", "name": "b"},
+	}, &writeCalled)
+
+	err := blob.checkUnique(diskModels.PageRecords{
+		"id1":         {"org": "a", "name": "c"},
+		"id2":         {"org": "a", "name": nil},
+		"id3":         {"org": "a", "name": nil},
+		"existing-id": {"org": "a", "name": "b"},
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestUnit_Update_FailsOnUniqueConflict(t *testing.T) {
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"email": {KeyType: memoryConstants.String, Unique: true},
+		"name":  {KeyType: memoryConstants.String},
+	}, diskModels.PageRecords{
+		"id1": {"email": "a@test.com", "name": "a"},
+		"id2": {"email": "b@test.com", "name": "b"},
+	}, &writeCalled)
+
+	_, err := blob.Update(diskModels.PageRecord{"email": "a@test.com"}, []FilterItem{
+		{Key: "name", Op: memoryConstants.OpEqual, Value: "b"},
+	})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "violated by record id2: record id1 already has email=a@test.com")
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_UpdateByIndex_FailsOnUnknownRecordId(t *testing.T) {
+	diskManagers.MockIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return pageRecordId[:1]
+	}
+	blob := createTestBlob("db", "members", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"name": {KeyType: memoryConstants.String},
+	})
+
+	_, err := blob.UpdateByIndex("missing", diskModels.PageRecord{"name": "a"})
+
+	assert.NotNil(t, err)
+	assert.True(t, errors.As(err, &RecordNotFoundError{}))
+	assert.Contains(t, err.Error(), "record with id missing does not exist")
+}
+
+func TestUnit_DeleteByIndex_FailsOnUnknownRecordId(t *testing.T) {
+	diskManagers.MockIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return pageRecordId[:1]
+	}
+	blob := createTestBlob("db", "members", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{
+		"name": {KeyType: memoryConstants.String},
+	})
+
+	_, err := blob.DeleteByIndex("missing")
+
+	assert.NotNil(t, err)
+	assert.True(t, errors.As(err, &RecordNotFoundError{}))
+	assert.Contains(t, err.Error(), "record with id missing does not exist")
+}
+
+func createTestBucketBlob() Blob {
+	return createTestBlob(
+		"db",
+		"blob",
+		"dataLocation",
+		false,
+		&sync.Mutex{},
+		diskModels.Partition{
+			Keys: []string{"region", "day", "qty"},
+			Buckets: map[string]diskModels.PartitionBucket{
+				"day": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay},
+				"qty": {Type: memoryConstants.PartitionRange, Bounds: []float64{10}},
+			},
+		},
+		diskModels.Format{
+			"region": diskModels.FormatItem{KeyType: memoryConstants.String},
+			"day":    diskModels.FormatItem{KeyType: memoryConstants.Date},
+			"qty":    diskModels.FormatItem{KeyType: memoryConstants.Int},
+		},
+	)
+}
+
+func TestUnit_SearchOrderedPages_FailsOnFilterError(t *testing.T) {
+	writeCalled := false
+	format := diskModels.Format{"age": {KeyType: memoryConstants.Int}}
+	blob := createTestUniqueBlob(format, diskModels.PageRecords{"id1": {"age": true}}, &writeCalled)
+	blob.keyIndexMap = createTestOrderedKeyIndexMap(map[string]diskModels.OrderedIndex{
+		"age": {Entries: []diskModels.OrderedIndexEntry{{Value: 1, PageRecordId: "id1", PageFile: "page1"}}},
+	})
+	page, _ := blob.pageMap.Get("page1")
+
+	_, ok, err := blob.searchOrderedPages([]*Page{page}, Filter{
+		FilterItems: []FilterItem{{Key: "age", Op: memoryConstants.OpGreater, Value: 0}},
+		Format:      format,
+	}, GetOperationParams{Limit: 1, Sort: []SortItem{{Key: "age"}}})
+
+	assert.True(t, ok)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "corrupt record")
+}
+
+func TestUnit_NormalizeLegacyDateTimes_RewritesLocalDateTimesAsUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("test", 2*60*60)
+	defer func() { time.Local = local }()
+	writeCalled := false
+	var written diskModels.PageRecords
+	blob := createTestUniqueBlob(diskModels.Format{
+		"name":    {KeyType: memoryConstants.String},
+		"created": {KeyType: memoryConstants.DateTime},
+		"seen":    {KeyType: memoryConstants.Array + "<" + memoryConstants.DateTime + ">"},
+		"meta": {KeyType: memoryConstants.Object, Format: diskModels.Format{
+			"updated": {KeyType: memoryConstants.DateTime},
+		}},
+	}, diskModels.PageRecords{
+		"id1": {
+			"name":    "2023-01-02 03:04:05",
+			"created": "2023-01-02 03:04:05",
+			"seen":    []any{"2023-01-02 03:04:05", "2023-01-02T03:04:05Z"},
+			"meta":    map[string]any{"updated": "2023-01-02 03:04:05"},
+		},
+	}, &writeCalled)
+	diskManagers.MockPageManagerInstance.WriteDataFunc = func(db string, blob string, pageFileName string, data diskModels.PageRecords) error {
+		written = data
+		return nil
+	}
+
+	err := blob.normalizeLegacyDateTimes()
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecords{
+		"id1": {
+			"name":    "2023-01-02 03:04:05",
+			"created": "2023-01-02T01:04:05Z",
+			"seen":    []any{"2023-01-02T01:04:05Z", "2023-01-02T03:04:05Z"},
+			"meta":    map[string]any{"updated": "2023-01-02T01:04:05Z"},
+		},
+	}, written)
+}
+
+func TestUnit_NormalizeLegacyDateTimes_SkipsPartitionKeys(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("test", 2*60*60)
+	defer func() { time.Local = local }()
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"created": {KeyType: memoryConstants.DateTime},
+	}, diskModels.PageRecords{
+		"id1": {"created": "2023-01-02 03:04:05"},
+	}, &writeCalled)
+	blob.partition = diskModels.Partition{Keys: []string{"created"}}
+
+	err := blob.normalizeLegacyDateTimes()
+
+	assert.Nil(t, err)
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_Migrate_RunsPendingMigrationsOnce(t *testing.T) {
+	getMigrationsFunc := diskManagers.MockFormatManagerInstance.GetMigrationsFunc
+	writeMigrationsFunc := diskManagers.MockFormatManagerInstance.WriteMigrationsFunc
+	defer func() {
+		diskManagers.MockFormatManagerInstance.GetMigrationsFunc = getMigrationsFunc
+		diskManagers.MockFormatManagerInstance.WriteMigrationsFunc = writeMigrationsFunc
+	}()
+	migrations := []string{}
+	diskManagers.MockFormatManagerInstance.GetMigrationsFunc = func(db string, blob string) ([]string, error) {
+		return migrations, nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteMigrationsFunc = func(db string, blob string, written []string) error {
+		migrations = written
+		return nil
+	}
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"created": {KeyType: memoryConstants.DateTime},
+	}, diskModels.PageRecords{
+		"id1": {"created": "2023-01-02 03:04:05"},
+	}, &writeCalled)
+
+	assert.Nil(t, blob.migrate())
+	assert.True(t, writeCalled)
+	assert.Equal(t, memoryConstants.GetMigrations(), migrations)
+
+	writeCalled = false
+	assert.Nil(t, blob.migrate())
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_NormalizeLegacyDateTimes_SkipsPagesWithoutLegacyValues(t *testing.T) {
+	writeCalled := false
+	blob := createTestUniqueBlob(diskModels.Format{
+		"created": {KeyType: memoryConstants.DateTime},
+	}, diskModels.PageRecords{
+		"id1": {"created": "2023-01-02T03:04:05Z"},
+	}, &writeCalled)
+
+	err := blob.normalizeLegacyDateTimes()
+
+	assert.Nil(t, err)
+	assert.False(t, writeCalled)
+}
+
+func TestUnit_FilterHashKeyFiles_FiltersByBucketLabel(t *testing.T) {
+	blob := createTestBucketBlob()
+	regionHash := strings.Repeat("a", 28)
+	hashKeys := []string{
+		regionHash + "202403150000.json",
+		regionHash + "202403160000.json",
+		regionHash + "202403150001.json",
+	}
+
+	result, err := blob.FilterHashKeyFiles(hashKeys, SearchPartition{"day": "2024-03-15", "qty": 12})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{regionHash + "202403150001.json"}, result)
+}
+
+func TestUnit_FilterHashKeyFiles_HashesFormattedSearchValues(t *testing.T) {
+	hashedValues := diskModels.PageRecord{}
+	diskManagers.MockPartitionManagerInstance.GetHashKeyItemFunc = func(partitionKey string, pageRecord diskModels.PageRecord) (string, error) {
+		hashedValues[partitionKey] = pageRecord[partitionKey]
+		return strings.Repeat("a", 28), nil
+	}
+	blob := createTestBlob("db", "blob", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{
+		Keys: []string{"tenant", "created", "amount"},
+	}, diskModels.Format{
+		"tenant":  diskModels.FormatItem{KeyType: memoryConstants.Uuid},
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+		"amount":  diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2},
+	})
+
+	_, err := blob.FilterHashKeyFiles([]string{}, SearchPartition{
+		"tenant":  "6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"created": "2024-03-15T12:30:00+02:00",
+		"amount":  10.5,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{
+		"tenant":  "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"created": "2024-03-15T10:30:00Z",
+		"amount":  "10.50",
+	}, hashedValues)
+}
+
+func TestUnit_FilterHashKeyFiles_FailsOnInvalidBucketValue(t *testing.T) {
+	blob := createTestBucketBlob()
+
+	_, err := blob.FilterHashKeyFiles([]string{}, SearchPartition{"day": "someday"})
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_FilterBucketKeyFiles_PrunesByRangeFilters(t *testing.T) {
+	blob := createTestBucketBlob()
+	regionHash := strings.Repeat("a", 28)
+	hashKeys := []string{
+		regionHash + "202403140000.json",
+		regionHash + "202403150001.json",
+		regionHash + "202403160000.json",
+		regionHash + "202403170001.json",
+	}
+	filter := Filter{
+		FilterItems: []FilterItem{
+			{Key: "day", Op: memoryConstants.OpBetween, Value: []any{"2024-03-15", "2024-03-16"}},
+			{Key: "region", Op: memoryConstants.OpEqual, Value: "eu"},
+		},
+		Format: blob.format,
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+
+	result := blob.FilterBucketKeyFiles(hashKeys, filter)
+	ranged := blob.FilterBucketKeyFiles(result, Filter{FilterItems: []FilterItem{{Key: "qty", Op: memoryConstants.OpLess, Value: 10}}})
+
+	assert.Equal(t, []string{regionHash + "202403150001.json", regionHash + "202403160000.json"}, result)
+	assert.Equal(t, []string{regionHash + "202403160000.json"}, ranged)
+}
+
+func TestUnit_FilterBucketKeyFiles_KeepsFilesWithoutBuckets(t *testing.T) {
+	blob := createTestBlob("db", "blob", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{Keys: []string{"region"}}, diskModels.Format{
+		"region": diskModels.FormatItem{KeyType: memoryConstants.String},
+	})
+	hashKeys := []string{strings.Repeat("a", 28) + ".json"}
+
+	result := blob.FilterBucketKeyFiles(hashKeys, Filter{FilterItems: []FilterItem{{Key: "region", Op: memoryConstants.OpEqual, Value: "eu"}}})
+
+	assert.Equal(t, hashKeys, result)
+}
+
+func TestUnit_GetPartitionValues_DescribesBucketValues(t *testing.T) {
+	blob := createTestBucketBlob()
+
+	values := blob.getPartitionValues(diskModels.PageRecord{"region": "eu", "day": "2024-03-15", "qty": 12})
+
+	assert.Equal(t, map[string]any{"region": "eu", "day": "2024-03-15", "qty": "[10, inf)"}, values)
+}
+
+func TestUnit_GetPartitions_FailsOnBlobWithoutPartition(t *testing.T) {
+	blob := createTestBlob("db", "blob", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{})
+
+	_, err := blob.GetPartitions()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not partitioned")
+}
+
+func TestUnit_DropPartition_FailsOnBlobWithoutPartition(t *testing.T) {
+	blob := createTestBlob("db", "blob", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{}, diskModels.Format{})
+
+	_, err := blob.DropPartition(SearchPartition{"region": "eu"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not partitioned")
+}
+
+func TestUnit_DropPartition_FailsOnMissingPartitionKey(t *testing.T) {
+	blob := createTestBucketBlob()
+
+	_, err := blob.DropPartition(SearchPartition{"region": "eu", "day": "2024-03-15"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "partition key qty is required")
+}
+
+func createTestDropPartitionBlob(indexWriteErr error, pageDeleteCalled *bool, partitionDeleteCalled *bool, catalog diskModels.PartitionCatalog) Blob {
+	pageFile := "page.json"
+	diskManagers.MockPageManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Pages, error) {
+		return diskModels.Pages{{FileName: pageFile}}, nil
+	}
+	diskManagers.MockPageManagerInstance.GetDataFunc = func(db string, blob string, pageFileName string) (diskModels.PageRecords, error) {
+		return diskModels.PageRecords{"id1": {"region": "eu"}}, nil
+	}
+	diskManagers.MockPageManagerInstance.DeleteFunc = func(db string, blob string, pageFileName string) (bool, error) {
+		*pageDeleteCalled = true
+		return false, nil
+	}
+	diskManagers.MockIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.Indexes, error) {
+		return diskModels.Indexes{"i": {FileNames: []string{"index.json"}}}, nil
+	}
+	diskManagers.MockIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return pageRecordId[0:1]
+	}
+	diskManagers.MockIndexManagerInstance.GetDataFunc = func(db string, blob string, indexFileName string) (diskModels.IndexRecords, error) {
+		return diskModels.IndexRecords{"id1": pageFile}, nil
+	}
+	diskManagers.MockIndexManagerInstance.WriteDataFunc = func(db string, blob string, indexFileName string, data diskModels.IndexRecords) error {
+		return indexWriteErr
+	}
+	diskManagers.MockIndexManagerInstance.DeleteFunc = func(db string, blob string, indexFileName string) (bool, error) {
+		return false, nil
+	}
+	diskManagers.MockPartitionManagerInstance.GetAllFunc = func(db string, blob string) ([]string, error) {
+		return []string{strings.Repeat("a", 28) + ".json"}, nil
+	}
+	diskManagers.MockPartitionManagerInstance.GetByHashKeyFunc = func(db string, blob string, hashKeyFileName string) (diskModels.PartitionPages, error) {
+		return diskModels.PartitionPages{{FileName: pageFile}}, nil
+	}
+	diskManagers.MockPartitionManagerInstance.GetHashKeyItemFunc = func(partitionKey string, pageRecord diskModels.PageRecord) (string, error) {
+		if pageRecord[partitionKey] == "eu" {
+			return strings.Repeat("a", 28), nil
+		}
+		return strings.Repeat("b", 28), nil
+	}
+	diskManagers.MockPartitionManagerInstance.DeleteFunc = func(db string, blob string, hashKeyFileName string) error {
+		*partitionDeleteCalled = true
+		return nil
+	}
+	diskManagers.MockPartitionManagerInstance.GetCatalogFunc = func(db string, blob string) (diskModels.PartitionCatalog, error) {
+		return catalog, nil
+	}
+	diskManagers.MockPartitionManagerInstance.WriteCatalogFunc = func(db string, blob string, writtenCatalog diskModels.PartitionCatalog) error {
+		for hashKeyFile := range catalog {
+			delete(catalog, hashKeyFile)
+		}
+		for hashKeyFile, catalogItem := range writtenCatalog {
+			catalog[hashKeyFile] = catalogItem
+		}
+		return nil
+	}
+	blob := createTestBlob("db", "blob", "dataLocation", false, &sync.Mutex{}, diskModels.Partition{Keys: []string{"region"}}, diskModels.Format{
+		"region": diskModels.FormatItem{KeyType: memoryConstants.String},
+	})
+	blob.keyIndexMap = createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+	_ = blob.pageMap.Initialize()
+	_ = blob.indexMap.Initialize()
+	_ = blob.partitionMap.Initialize()
+	return blob
+}
+
+func TestUnit_DropPartition_DropsPartitionAndCatalogEntry(t *testing.T) {
+	pageDeleteCalled := false
+	partitionDeleteCalled := false
+	hashKeyFile := strings.Repeat("a", 28) + ".json"
+	catalog := diskModels.PartitionCatalog{hashKeyFile: {Values: map[string]any{"region": "eu"}, Records: 1, Pages: 1}}
+	blob := createTestDropPartitionBlob(nil, &pageDeleteCalled, &partitionDeleteCalled, catalog)
+
+	dropped, err := blob.DropPartition(SearchPartition{"region": "eu"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, PageRecordsMap{"page.json": {"id1": {"region": "eu"}}}, dropped)
+	assert.True(t, pageDeleteCalled)
+	assert.True(t, partitionDeleteCalled)
+	assert.Equal(t, diskModels.PartitionCatalog{}, catalog)
+}
+
+func TestUnit_DropPartition_FailsOnUnknownPartition(t *testing.T) {
+	pageDeleteCalled := false
+	partitionDeleteCalled := false
+	blob := createTestDropPartitionBlob(nil, &pageDeleteCalled, &partitionDeleteCalled, diskModels.PartitionCatalog{})
+
+	_, err := blob.DropPartition(SearchPartition{"region": "us"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "partition region=us not found in blob")
+	assert.False(t, pageDeleteCalled)
+	assert.False(t, partitionDeleteCalled)
+}
+
+func TestUnit_DropPartition_FailsOnDeleteIndexesError(t *testing.T) {
+	pageDeleteCalled := false
+	partitionDeleteCalled := false
+	blob := createTestDropPartitionBlob(assert.AnError, &pageDeleteCalled, &partitionDeleteCalled, diskModels.PartitionCatalog{})
+
+	_, err := blob.DropPartition(SearchPartition{"region": "eu"})
+
+	assert.Equal(t, assert.AnError, err)
+	assert.False(t, pageDeleteCalled)
+	assert.False(t, partitionDeleteCalled)
+}
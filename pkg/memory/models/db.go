@@ -1,7 +1,6 @@
 package memoryModels
 
 import (
-	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"sync"
@@ -57,7 +56,7 @@ func (dbm *DBMap) GetBlobMap(db string) (*BlobMap, error) {
 		return blobMap, nil
 	}
 	if !dbm.dbDiskManager.Exists(db) {
-		return nil, fmt.Errorf("db %s does not exist", db)
+		return nil, DBNotFoundError{DB: db}
 	}
 	blobMap := NewBlobMap(db, dbm.dataLocation, dbm.dataCaching)
 	dbm.itemMap[db] = &blobMap
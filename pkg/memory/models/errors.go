@@ -0,0 +1,28 @@
+package memoryModels
+
+import "fmt"
+
+type RecordNotFoundError struct {
+	PageRecordId string
+}
+
+func (e RecordNotFoundError) Error() string {
+	return fmt.Sprintf("record with id %s does not exist", e.PageRecordId)
+}
+
+type DBNotFoundError struct {
+	DB string
+}
+
+func (e DBNotFoundError) Error() string {
+	return fmt.Sprintf("db %s does not exist", e.DB)
+}
+
+type BlobNotFoundError struct {
+	DB   string
+	Blob string
+}
+
+func (e BlobNotFoundError) Error() string {
+	return fmt.Sprintf("blob %s does not exist in db %s", e.Blob, e.DB)
+}
@@ -1,11 +1,18 @@
 package memoryModels
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,9 +20,19 @@ import (
 type SearchPartition map[string]any
 
 type FilterItem struct {
-	Key   string `json:"key,required"`
-	Op    string `json:"op,required"`
-	Value any    `json:"value,required"`
+	Key   string       `json:"key,required"`
+	Op    string       `json:"op,required"`
+	Value any          `json:"value,required"`
+	Items []FilterItem `json:"items,omitempty"`
+}
+
+func (fi *FilterItem) IsGroup() bool {
+	switch fi.Op {
+	case memoryConstants.FilterAnd, memoryConstants.FilterOr, memoryConstants.FilterNot:
+		return true
+	default:
+		return false
+	}
 }
 
 type Filter struct {
@@ -24,223 +41,547 @@ type Filter struct {
 	converted   bool
 }
 
-type GetOperationParams struct {
-	Limit  int    `json:"limit"`
-	Offset int    `json:"offset"`
-	Sort   string `json:"sort"`
-}
-
 func (f *Filter) Passes(record diskModels.PageRecord) (bool, error) {
 	if f.FilterItems == nil {
 		return true, nil
 	}
-	for _, filterItem := range f.FilterItems {
-		value, ok := record[filterItem.Key]
+	return f.passesItems(f.FilterItems, memoryConstants.FilterAnd, record)
+}
+
+func (f *Filter) passesItems(filterItems []FilterItem, op string, record diskModels.PageRecord) (bool, error) {
+	for _, filterItem := range filterItems {
+		result, err := f.passesItem(filterItem, record)
+		if err != nil {
+			return false, err
+		}
+		if op == memoryConstants.FilterOr && result {
+			return true, nil
+		}
+		if op == memoryConstants.FilterAnd && !result {
+			return false, nil
+		}
+	}
+	return op == memoryConstants.FilterAnd, nil
+}
+
+func (f *Filter) passesItem(filterItem FilterItem, record diskModels.PageRecord) (bool, error) {
+	switch filterItem.Op {
+	case memoryConstants.FilterAnd, memoryConstants.FilterOr:
+		return f.passesItems(filterItem.Items, filterItem.Op, record)
+	case memoryConstants.FilterNot:
+		result, err := f.passesItems(filterItem.Items, memoryConstants.FilterAnd, record)
+		return !result && err == nil, err
+	}
+	value := getRecordValue(record, filterItem.Key)
+	switch filterItem.Op {
+	case memoryConstants.OpIsNull:
+		return value == nil, nil
+	case memoryConstants.OpIsNotNull:
+		return value != nil, nil
+	}
+	if value == nil {
+		return false, nil
+	}
+	formatItem, _ := getFormatItem(f.Format, filterItem.Key)
+	if elementType, ok := memoryConstants.GetArrayElementType(formatItem.KeyType); ok {
+		return f.checkArray(filterItem.Value, value, elementType, filterItem.Op)
+	}
+	return f.checkValue(filterItem.Value, value, formatItem.KeyType, filterItem.Op)
+}
+
+func (f *Filter) checkValue(compare any, value any, keyType string, op string) (bool, error) {
+	switch keyType {
+	case memoryConstants.String:
+		stringValue, ok := value.(string)
 		if !ok {
-			return false, errors.New(fmt.Sprintf("'%s' not found in record", filterItem.Key))
-		}
-		result := true
-		switch f.Format[filterItem.Key].KeyType {
-		case memoryConstants.String:
-			_, ok = value.(string)
-			if !ok {
-				return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
-			}
-			result = f.checkString(filterItem.Value.(string), value.(string), filterItem.Op)
-		case memoryConstants.Int:
-			value, err := memoryUtils.ConvertToInt(value)
-			if err != nil {
-				return false, errors.New(fmt.Sprintf("corrupt record with value %+v: %s", value, err.Error()))
-			}
-			result = f.checkInt(filterItem.Value.(int), value, filterItem.Op)
-		case memoryConstants.Float:
-			value, err := memoryUtils.ConvertToFloat64(value)
-			if err != nil {
-				return false, errors.New(fmt.Sprintf("corrupt record with value %+v: %s", value, err.Error()))
-			}
-			result = f.checkFloat(filterItem.Value.(float64), value, filterItem.Op)
-		case memoryConstants.Date:
-			_, ok = value.(string)
-			if !ok {
-				return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
-			}
-			result = f.checkDate(filterItem.Value.(string), value.(string), filterItem.Op)
-		case memoryConstants.DateTime:
-			_, ok = value.(string)
-			if !ok {
-				return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
-			}
-			compare, err := memoryUtils.ConvertToInt(filterItem.Value)
-			if err != nil {
-				return false, errors.New(fmt.Sprintf("could not convert %+v to int in filter", compare))
-			}
-			result = f.checkDateTime(filterItem.Value.(int64), value.(string), filterItem.Op)
-		case memoryConstants.Bool:
-			_, ok = value.(bool)
-			if !ok {
-				return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
-			}
-			result = f.checkBool(filterItem.Value.(bool), value.(bool), filterItem.Op)
-		default:
-			return false, errors.New(fmt.Sprintf("format type %s not known in filter", f.Format[filterItem.Key].KeyType))
+			return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+		}
+		return f.checkString(compare, stringValue, op), nil
+	case memoryConstants.Int:
+		value, err := memoryUtils.ConvertToInt(value)
+		if err != nil {
+			return false, errors.New(fmt.Sprintf("corrupt record with value %+v: %s", value, err.Error()))
+		}
+		return f.checkInt(compare, value, op), nil
+	case memoryConstants.Float:
+		value, err := memoryUtils.ConvertToFloat64(value)
+		if err != nil {
+			return false, errors.New(fmt.Sprintf("corrupt record with value %+v: %s", value, err.Error()))
+		}
+		return f.checkFloat(compare, value, op), nil
+	case memoryConstants.Date:
+		stringValue, ok := value.(string)
+		if !ok {
+			return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+		}
+		return f.checkDate(compare, stringValue, op), nil
+	case memoryConstants.DateTime:
+		stringValue, ok := value.(string)
+		if !ok {
+			return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+		}
+		return f.checkDateTime(compare, stringValue, op), nil
+	case memoryConstants.Bool:
+		boolValue, ok := value.(bool)
+		if !ok {
+			return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+		}
+		return f.checkBool(compare, boolValue, op), nil
+	case memoryConstants.Decimal:
+		decimalValue, err := memoryUtils.ConvertToDecimal(value)
+		if err != nil {
+			return false, errors.New(fmt.Sprintf("corrupt record with value %+v: %s", value, err.Error()))
 		}
+		return f.checkDecimal(compare, decimalValue, op), nil
+	case memoryConstants.Uuid, memoryConstants.Enum, memoryConstants.Bytes:
+		stringValue, ok := value.(string)
+		if !ok {
+			return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+		}
+		return checkOrdered(compare, stringValue, op), nil
+	default:
+		return false, errors.New(fmt.Sprintf("format type %s not known in filter", keyType))
+	}
+}
 
-		if !result {
-			return false, nil
+func (f *Filter) checkArray(compare any, value any, elementType string, op string) (bool, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return false, errors.New(fmt.Sprintf("record is corrupt value %+v", value))
+	}
+	elementOp := memoryConstants.OpEqual
+	if op == memoryConstants.OpAny {
+		elementOp = memoryConstants.OpIn
+	}
+	for _, element := range values {
+		if element == nil {
+			continue
+		}
+		result, err := f.checkValue(compare, element, elementType, elementOp)
+		if err != nil || result {
+			return result, err
 		}
 	}
-	return true, nil
+	return false, nil
 }
 
 func (f *Filter) ConvertFilterItems() error {
-	i := 0
-	for _, filterItem := range f.FilterItems {
-		switch f.Format[filterItem.Key].KeyType {
-		case memoryConstants.Date:
-			fallthrough
-		case memoryConstants.String:
-			value, ok := filterItem.Value.(string)
-			if !ok {
-				return errors.New(fmt.Sprintf("%+v could not be converted to string", filterItem.Value))
-			}
-			f.FilterItems[i].Value = value
-		case memoryConstants.Int:
-			value, err := memoryUtils.ConvertToInt(filterItem.Value)
-			if err != nil {
-				return errors.New(fmt.Sprintf("could not convert %+v to int in filter", filterItem.Value))
-			}
-			f.FilterItems[i].Value = value
-		case memoryConstants.Float:
-			value, err := memoryUtils.ConvertToFloat64(filterItem.Value)
-			if err != nil {
-				return errors.New(fmt.Sprintf("could not convert %+v to int in filter", filterItem.Value))
-			}
-			f.FilterItems[i].Value = value
-		case memoryConstants.DateTime:
-			value, err := memoryUtils.ConvertToInt(filterItem.Value)
-			if err != nil {
-				return errors.New(fmt.Sprintf("could not convert %+v to int in filter", filterItem.Value))
-			}
-			f.FilterItems[i].Value = int64(value)
-		case memoryConstants.Bool:
-			value, ok := filterItem.Value.(bool)
-			if !ok {
-				return errors.New(fmt.Sprintf("%+v could not be converted to bool", filterItem.Value))
-			}
-			f.FilterItems[i].Value = value
-		}
-		i++
+	if f.converted {
+		return nil
 	}
+	filterItems, err := f.convertFilterItems(f.FilterItems)
+	if err != nil {
+		return err
+	}
+	f.FilterItems = filterItems
+	f.converted = true
 	return nil
 }
 
-func (f *Filter) checkString(compare string, value string, op string) bool {
-	switch op {
-	case "CONTAINS_CS":
-		return strings.Contains(value, compare)
-	case "CONTAINS":
-		return strings.Contains(strings.ToLower(value), strings.ToLower(compare))
-	case "PREFIX_CS":
-		return strings.HasPrefix(value, compare)
-	case "PREFIX":
-		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(compare))
-	case "SUFFIX_CS":
-		return strings.HasSuffix(value, compare)
-	case "SUFFIX":
-		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(compare))
-	case "=":
-		return value == compare
+func (f *Filter) convertFilterItems(filterItems []FilterItem) ([]FilterItem, error) {
+	if filterItems == nil {
+		return nil, nil
+	}
+	convertedItems := []FilterItem{}
+	for _, filterItem := range filterItems {
+		convertedItem, err := f.convertFilterItem(filterItem)
+		if err != nil {
+			return nil, err
+		}
+		convertedItems = append(convertedItems, convertedItem)
+	}
+	return convertedItems, nil
+}
+
+func (f *Filter) convertFilterItem(filterItem FilterItem) (FilterItem, error) {
+	if filterItem.IsGroup() {
+		if len(filterItem.Items) == 0 {
+			return filterItem, errors.New(fmt.Sprintf("filter group %s has no items", filterItem.Op))
+		}
+		items, err := f.convertFilterItems(filterItem.Items)
+		if err != nil {
+			return filterItem, err
+		}
+		filterItem.Items = items
+		return filterItem, nil
+	}
+	formatItem, ok := getFormatItem(f.Format, filterItem.Key)
+	if !ok {
+		return filterItem, errors.New(fmt.Sprintf("filter key %s does not exist in format", filterItem.Key))
+	}
+	if !slices.Contains(memoryConstants.GetFilterOps(formatItem.KeyType), filterItem.Op) {
+		return filterItem, errors.New(fmt.Sprintf("operator %s not allowed on key %s of type %s", filterItem.Op, filterItem.Key, formatItem.KeyType))
+	}
+	valueItem := formatItem
+	valueItem.KeyType = memoryConstants.GetBaseType(formatItem.KeyType)
+	switch filterItem.Op {
+	case memoryConstants.OpIsNull, memoryConstants.OpIsNotNull:
+		filterItem.Value = nil
+	case memoryConstants.OpIn, memoryConstants.OpNotIn, memoryConstants.OpBetween, memoryConstants.OpAny:
+		values, err := f.convertFilterValues(filterItem.Value, valueItem)
+		if err != nil {
+			return filterItem, err
+		}
+		if filterItem.Op == memoryConstants.OpBetween && len(values) != 2 {
+			return filterItem, errors.New(fmt.Sprintf("%s on key %s requires exactly 2 values", filterItem.Op, filterItem.Key))
+		}
+		filterItem.Value = values
+	case memoryConstants.OpRegex, memoryConstants.OpLike, memoryConstants.OpILike:
+		pattern, ok := filterItem.Value.(string)
+		if !ok {
+			return filterItem, errors.New(fmt.Sprintf("%+v could not be converted to string pattern", filterItem.Value))
+		}
+		compiledPattern, err := compileFilterPattern(pattern, filterItem.Op)
+		if err != nil {
+			return filterItem, errors.New(fmt.Sprintf("invalid %s pattern %s on key %s: %s", filterItem.Op, pattern, filterItem.Key, err.Error()))
+		}
+		filterItem.Value = compiledPattern
+	case memoryConstants.OpMatch:
+		text, ok := filterItem.Value.(string)
+		if !ok {
+			return filterItem, errors.New(fmt.Sprintf("%+v could not be converted to string", filterItem.Value))
+		}
+		terms := memoryUtils.GetUniqueTokens(text)
+		if len(terms) == 0 {
+			return filterItem, errors.New(fmt.Sprintf("%s on key %s has no searchable terms", filterItem.Op, filterItem.Key))
+		}
+		filterItem.Value = terms
 	default:
-		return false
+		value, err := f.convertFilterValue(filterItem.Value, valueItem)
+		if err != nil {
+			return filterItem, err
+		}
+		filterItem.Value = value
 	}
+	return filterItem, nil
 }
 
-func (f *Filter) checkInt(compare int, value int, op string) bool {
-	switch op {
-	case "=":
-		return compare == value
-	case ">":
-		return value > compare
-	case ">=":
-		return value >= compare
-	case "<":
-		return value < compare
-	case "<=":
-		return value <= compare
+func (f *Filter) convertFilterValues(value any, formatItem diskModels.FormatItem) ([]any, error) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		return nil, errors.New(fmt.Sprintf("%+v is not a list of values", value))
+	}
+	values := []any{}
+	for i := 0; i < reflectValue.Len(); i++ {
+		convertedValue, err := f.convertFilterValue(reflectValue.Index(i).Interface(), formatItem)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, convertedValue)
+	}
+	return values, nil
+}
+
+func (f *Filter) convertFilterValue(value any, formatItem diskModels.FormatItem) (any, error) {
+	switch formatItem.KeyType {
+	case memoryConstants.Date:
+		if convertedValue, ok := value.(string); ok {
+			if _, err := time.Parse(time.DateOnly, convertedValue); err == nil {
+				return convertedValue, nil
+			}
+		}
+		timeValue, err := memoryUtils.ConvertToTime(value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%+v is not a valid date", value))
+		}
+		return timeValue.Format(time.DateOnly), nil
+	case memoryConstants.String:
+		convertedValue, ok := value.(string)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("%+v could not be converted to string", value))
+		}
+		return convertedValue, nil
+	case memoryConstants.Int:
+		convertedValue, err := memoryUtils.ConvertToInt(value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("could not convert %+v to int in filter", value))
+		}
+		return convertedValue, nil
+	case memoryConstants.Float:
+		convertedValue, err := memoryUtils.ConvertToFloat64(value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("could not convert %+v to float in filter", value))
+		}
+		return convertedValue, nil
+	case memoryConstants.DateTime:
+		convertedValue, err := memoryUtils.ConvertToTime(value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("could not convert %+v to datetime in filter", value))
+		}
+		return memoryUtils.ConvertToUnixSeconds(convertedValue), nil
+	case memoryConstants.Bool:
+		convertedValue, ok := value.(bool)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("%+v could not be converted to bool", value))
+		}
+		return convertedValue, nil
+	case memoryConstants.Decimal:
+		convertedValue, err := memoryUtils.ConvertToDecimal(value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("could not convert %+v to decimal in filter", value))
+		}
+		return convertedValue, nil
+	case memoryConstants.Uuid, memoryConstants.Enum, memoryConstants.Bytes:
+		formatter := BlobFormatter{}
+		return formatter.convertRecordValue(value, formatItem)
 	default:
-		return false
+		return nil, errors.New(fmt.Sprintf("format type %s not known in filter", formatItem.KeyType))
 	}
 }
 
-func (f *Filter) checkFloat(compare float64, value float64, op string) bool {
+func (f *Filter) checkString(compare any, value string, op string) bool {
 	switch op {
-	case "=":
-		return compare == value
-	case ">":
-		return value > compare
-	case ">=":
-		return value >= compare
-	case "<":
-		return value < compare
-	case "<=":
-		return value <= compare
+	case memoryConstants.OpContainsCS:
+		return strings.Contains(value, compare.(string))
+	case memoryConstants.OpContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(compare.(string)))
+	case memoryConstants.OpPrefixCS:
+		return strings.HasPrefix(value, compare.(string))
+	case memoryConstants.OpPrefix:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(compare.(string)))
+	case memoryConstants.OpSuffixCS:
+		return strings.HasSuffix(value, compare.(string))
+	case memoryConstants.OpSuffix:
+		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(compare.(string)))
+	case memoryConstants.OpRegex, memoryConstants.OpLike, memoryConstants.OpILike:
+		return compare.(*regexp.Regexp).MatchString(value)
+	case memoryConstants.OpMatch:
+		return getMatchScore(compare.([]string), value) > 0
 	default:
-		return false
+		return checkOrdered(compare, value, op)
 	}
 }
 
-func (f *Filter) checkDate(compare string, value string, op string) bool {
-	filterValueDate, err := time.Parse("2006-01-02", compare)
-	if err != nil {
-		return false
+func (f *Filter) GetMatchItems() []FilterItem {
+	return f.getMatchItems(f.FilterItems)
+}
+
+func (f *Filter) getMatchItems(filterItems []FilterItem) []FilterItem {
+	matchItems := []FilterItem{}
+	for _, filterItem := range filterItems {
+		switch filterItem.Op {
+		case memoryConstants.OpMatch:
+			matchItems = append(matchItems, filterItem)
+		case memoryConstants.FilterAnd, memoryConstants.FilterOr:
+			matchItems = append(matchItems, f.getMatchItems(filterItem.Items)...)
+		}
 	}
-	valueDate, err := time.Parse("2006-01-02", value)
-	if err != nil {
+	return matchItems
+}
+
+func (f *Filter) checkInt(compare any, value int, op string) bool {
+	return checkOrdered(compare, value, op)
+}
+
+func (f *Filter) checkFloat(compare any, value float64, op string) bool {
+	return checkOrdered(compare, value, op)
+}
+
+func (f *Filter) checkDate(compare any, value string, op string) bool {
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
 		return false
 	}
 	switch op {
-	case "=":
-		return filterValueDate == valueDate
-	case ">":
-		return valueDate.After(filterValueDate)
-	case ">=":
-		return valueDate.After(filterValueDate) || filterValueDate == valueDate
-	case "<":
-		return valueDate.Before(filterValueDate)
-	case "<=":
-		return valueDate.Before(filterValueDate) || filterValueDate == valueDate
+	case memoryConstants.OpRegex, memoryConstants.OpLike, memoryConstants.OpILike:
+		return compare.(*regexp.Regexp).MatchString(value)
 	default:
-		return false
+		return checkOrdered(compare, value, op)
 	}
 }
 
-func (f *Filter) checkDateTime(compare int64, value string, op string) bool {
-	filterValueDateTime := time.Unix(compare, 0)
-	valueDateTime, err := time.Parse(time.DateTime, value)
+func (f *Filter) checkDateTime(compare any, value string, op string) bool {
+	valueDateTime, err := memoryUtils.ConvertToTime(value)
 	if err != nil {
 		return false
 	}
+	return checkOrdered(compare, memoryUtils.ConvertToUnixSeconds(valueDateTime), op)
+}
+
+func (f *Filter) checkDecimal(compare any, value *big.Rat, op string) bool {
+	switch op {
+	case memoryConstants.OpIn, memoryConstants.OpNotIn:
+		contains := slices.ContainsFunc(compare.([]any), func(item any) bool {
+			return value.Cmp(item.(*big.Rat)) == 0
+		})
+		return contains == (op == memoryConstants.OpIn)
+	case memoryConstants.OpBetween:
+		bounds := compare.([]any)
+		return value.Cmp(bounds[0].(*big.Rat)) >= 0 && value.Cmp(bounds[1].(*big.Rat)) <= 0
+	default:
+		return checkOrdered(0, value.Cmp(compare.(*big.Rat)), op)
+	}
+}
+
+func (f *Filter) checkBool(compare any, value bool, op string) bool {
 	switch op {
-	case "=":
-		return filterValueDateTime == valueDateTime
-	case ">":
-		return valueDateTime.After(filterValueDateTime)
-	case ">=":
-		return valueDateTime.After(filterValueDateTime) || filterValueDateTime == valueDateTime
-	case "<":
-		return valueDateTime.Before(filterValueDateTime)
-	case "<=":
-		return valueDateTime.Before(filterValueDateTime) || filterValueDateTime == valueDateTime
+	case memoryConstants.OpEqual:
+		return compare.(bool) == value
+	case memoryConstants.OpNotEqual:
+		return compare.(bool) != value
+	case memoryConstants.OpIn:
+		return slices.Contains(compare.([]any), any(value))
+	case memoryConstants.OpNotIn:
+		return !slices.Contains(compare.([]any), any(value))
 	default:
 		return false
 	}
 }
 
-func (f *Filter) checkBool(compare bool, value bool, op string) bool {
+func checkOrdered[T cmp.Ordered](compare any, value T, op string) bool {
 	switch op {
-	case "=":
-		return compare == value
+	case memoryConstants.OpEqual:
+		return value == compare.(T)
+	case memoryConstants.OpNotEqual:
+		return value != compare.(T)
+	case memoryConstants.OpGreater:
+		return value > compare.(T)
+	case memoryConstants.OpGreaterEqual:
+		return value >= compare.(T)
+	case memoryConstants.OpLess:
+		return value < compare.(T)
+	case memoryConstants.OpLessEqual:
+		return value <= compare.(T)
+	case memoryConstants.OpIn:
+		return slices.Contains(compare.([]any), any(value))
+	case memoryConstants.OpNotIn:
+		return !slices.Contains(compare.([]any), any(value))
+	case memoryConstants.OpBetween:
+		bounds := compare.([]any)
+		return value >= bounds[0].(T) && value <= bounds[1].(T)
 	default:
 		return false
 	}
 }
+
+func getMatchScore(terms []string, value string) float64 {
+	tokens := memoryUtils.Tokenize(value)
+	if len(tokens) == 0 {
+		return 0
+	}
+	counts := make(map[string]int)
+	for _, token := range tokens {
+		counts[token]++
+	}
+	score := 0.0
+	for _, term := range terms {
+		if counts[term] == 0 {
+			return 0
+		}
+		score += 1 + math.Log(float64(counts[term]))
+	}
+	return score / math.Sqrt(float64(len(tokens)))
+}
+
+func compileFilterPattern(pattern string, op string) (*regexp.Regexp, error) {
+	if op == memoryConstants.OpRegex {
+		return regexp.Compile(pattern)
+	}
+	var builder strings.Builder
+	if op == memoryConstants.OpILike {
+		builder.WriteString("(?i)")
+	}
+	builder.WriteString("(?s)^")
+	escaped := false
+	for _, char := range pattern {
+		switch {
+		case escaped:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '%':
+			builder.WriteString(".*")
+		case char == '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	if escaped {
+		builder.WriteString(regexp.QuoteMeta("\\"))
+	}
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
+
+func getFormatItem(format diskModels.Format, key string) (diskModels.FormatItem, bool) {
+	keys := strings.Split(key, memoryConstants.KeySeparator)
+	formatItem, ok := format[keys[0]]
+	for _, pathKey := range keys[1:] {
+		if !ok || formatItem.KeyType != memoryConstants.Object {
+			return diskModels.FormatItem{}, false
+		}
+		formatItem, ok = formatItem.Format[pathKey]
+	}
+	return formatItem, ok
+}
+
+func getRecordValue(record diskModels.PageRecord, key string) any {
+	keys := strings.Split(key, memoryConstants.KeySeparator)
+	value := record[keys[0]]
+	for _, pathKey := range keys[1:] {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[pathKey]
+	}
+	return value
+}
+
+func compareFormatValues(a any, b any, keyType string) int {
+	switch keyType {
+	case memoryConstants.Int:
+		aValue, aErr := memoryUtils.ConvertToInt(a)
+		bValue, bErr := memoryUtils.ConvertToInt(b)
+		if aErr != nil || bErr != nil {
+			return compareMissing(aErr == nil, bErr == nil)
+		}
+		return cmp.Compare(aValue, bValue)
+	case memoryConstants.Float:
+		aValue, aErr := memoryUtils.ConvertToFloat64(a)
+		bValue, bErr := memoryUtils.ConvertToFloat64(b)
+		if aErr != nil || bErr != nil {
+			return compareMissing(aErr == nil, bErr == nil)
+		}
+		return cmp.Compare(aValue, bValue)
+	case memoryConstants.Decimal:
+		aValue, aErr := memoryUtils.ConvertToDecimal(a)
+		bValue, bErr := memoryUtils.ConvertToDecimal(b)
+		if aErr != nil || bErr != nil {
+			return compareMissing(aErr == nil, bErr == nil)
+		}
+		return aValue.Cmp(bValue)
+	case memoryConstants.DateTime:
+		aValue, aErr := memoryUtils.ConvertToTime(a)
+		bValue, bErr := memoryUtils.ConvertToTime(b)
+		if aErr != nil || bErr != nil {
+			return compareMissing(aErr == nil, bErr == nil)
+		}
+		return aValue.Compare(bValue)
+	case memoryConstants.Bool:
+		aValue, aOk := a.(bool)
+		bValue, bOk := b.(bool)
+		if !aOk || !bOk {
+			return compareMissing(aOk, bOk)
+		}
+		return cmp.Compare(strconv.FormatBool(aValue), strconv.FormatBool(bValue))
+	default:
+		aValue, aOk := a.(string)
+		bValue, bOk := b.(string)
+		if !aOk || !bOk {
+			return compareMissing(aOk, bOk)
+		}
+		return strings.Compare(aValue, bValue)
+	}
+}
+
+func compareMissing(aOk bool, bOk bool) int {
+	if aOk == bOk {
+		return 0
+	}
+	if !aOk {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,396 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func createTestFilterFormat() diskModels.Format {
+	return diskModels.Format{
+		"status": diskModels.FormatItem{KeyType: memoryConstants.String},
+		"count":  diskModels.FormatItem{KeyType: memoryConstants.Int},
+	}
+}
+
+func createTestFilter(t *testing.T, filterItems []FilterItem) Filter {
+	filter := Filter{FilterItems: filterItems, Format: createTestFilterFormat()}
+	assert.Nil(t, filter.ConvertFilterItems())
+	return filter
+}
+
+func TestUnit_Passes_PassesFlatFilterItemsAsAnd(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{
+		{Key: "status", Op: "=", Value: "a"},
+		{Key: "count", Op: ">", Value: 2},
+	})
+
+	passes, err := filter.Passes(diskModels.PageRecord{"status": "a", "count": 3})
+	assert.Nil(t, err)
+	assert.True(t, passes)
+
+	passes, err = filter.Passes(diskModels.PageRecord{"status": "a", "count": 1})
+	assert.Nil(t, err)
+	assert.False(t, passes)
+}
+
+func TestUnit_Passes_PassesOrGroup(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{
+		{Op: memoryConstants.FilterOr, Items: []FilterItem{
+			{Key: "status", Op: "=", Value: "a"},
+			{Key: "status", Op: "=", Value: "b"},
+		}},
+	})
+
+	passesA, _ := filter.Passes(diskModels.PageRecord{"status": "a", "count": 1})
+	passesB, _ := filter.Passes(diskModels.PageRecord{"status": "b", "count": 1})
+	passesC, _ := filter.Passes(diskModels.PageRecord{"status": "c", "count": 1})
+
+	assert.True(t, passesA)
+	assert.True(t, passesB)
+	assert.False(t, passesC)
+}
+
+func TestUnit_Passes_PassesNestedNotGroup(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{
+		{Key: "count", Op: ">=", Value: 1},
+		{Op: memoryConstants.FilterNot, Items: []FilterItem{
+			{Op: memoryConstants.FilterAnd, Items: []FilterItem{
+				{Key: "status", Op: "=", Value: "a"},
+				{Key: "count", Op: ">", Value: 5},
+			}},
+		}},
+	})
+
+	passesSmall, _ := filter.Passes(diskModels.PageRecord{"status": "a", "count": 2})
+	passesLarge, _ := filter.Passes(diskModels.PageRecord{"status": "a", "count": 6})
+	passesOther, _ := filter.Passes(diskModels.PageRecord{"status": "b", "count": 6})
+
+	assert.True(t, passesSmall)
+	assert.False(t, passesLarge)
+	assert.True(t, passesOther)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnEmptyGroup(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Op: memoryConstants.FilterOr}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_ConvertFilterItems_ConvertsNestedItemsWithoutMutatingSource(t *testing.T) {
+	filterItems := []FilterItem{
+		{Op: memoryConstants.FilterOr, Items: []FilterItem{
+			{Key: "count", Op: "=", Value: "4"},
+		}},
+	}
+	filter := Filter{FilterItems: filterItems, Format: createTestFilterFormat()}
+
+	err := filter.ConvertFilterItems()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, filter.FilterItems[0].Items[0].Value)
+	assert.Equal(t, "4", filterItems[0].Items[0].Value)
+}
+
+func TestUnit_Passes_PassesComparisonOperators(t *testing.T) {
+	record := diskModels.PageRecord{"status": "b", "count": 4}
+	filterItemSets := map[bool][]FilterItem{
+		true: {
+			{Key: "status", Op: memoryConstants.OpNotEqual, Value: "a"},
+			{Key: "status", Op: memoryConstants.OpIn, Value: []any{"a", "b"}},
+			{Key: "count", Op: memoryConstants.OpNotIn, Value: []any{1, "2"}},
+			{Key: "count", Op: memoryConstants.OpBetween, Value: []any{4, 6}},
+			{Key: "count", Op: memoryConstants.OpIsNotNull},
+		},
+		false: {
+			{Key: "status", Op: memoryConstants.OpNotEqual, Value: "b"},
+			{Key: "status", Op: memoryConstants.OpNotIn, Value: []string{"b"}},
+			{Key: "count", Op: memoryConstants.OpIn, Value: []int{1, 2}},
+			{Key: "count", Op: memoryConstants.OpBetween, Value: []any{5, 6}},
+			{Key: "count", Op: memoryConstants.OpIsNull},
+		},
+	}
+
+	for expected, filterItems := range filterItemSets {
+		for _, filterItem := range filterItems {
+			filter := createTestFilter(t, []FilterItem{filterItem})
+			passes, err := filter.Passes(record)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, passes, "%+v", filterItem)
+		}
+	}
+}
+
+func TestUnit_Passes_PassesIsNullOnMissingKey(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{{Key: "count", Op: memoryConstants.OpIsNull}})
+
+	passes, err := filter.Passes(diskModels.PageRecord{"status": "a"})
+
+	assert.Nil(t, err)
+	assert.True(t, passes)
+}
+
+func TestUnit_Passes_FailsComparisonOnMissingKeyWithoutError(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{{Key: "count", Op: ">", Value: 1}})
+
+	passes, err := filter.Passes(diskModels.PageRecord{"status": "a"})
+
+	assert.Nil(t, err)
+	assert.False(t, passes)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnUnknownOperator(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "count", Op: "CONTAINS", Value: 1}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnUnknownKey(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "unknown", Op: "=", Value: 1}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnInvalidBetween(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "count", Op: memoryConstants.OpBetween, Value: []any{1}}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Passes_PassesPatternOperators(t *testing.T) {
+	record := diskModels.PageRecord{"status": "Error: disk_full (code 28)", "count": 1}
+	expectations := map[string]bool{
+		"REGEX:^Error: [a-z_]+":    true,
+		"REGEX:^error":             false,
+		"LIKE:Error:%(code __)":    true,
+		"LIKE:error:%":             false,
+		"ILIKE:error:%":            true,
+		"LIKE:Error: disk\\_full%": true,
+		"LIKE:Error: disk\\%full%": false,
+	}
+
+	for expression, expected := range expectations {
+		op, pattern, _ := strings.Cut(expression, ":")
+		filter := createTestFilter(t, []FilterItem{{Key: "status", Op: op, Value: pattern}})
+		passes, err := filter.Passes(record)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, passes, expression)
+	}
+}
+
+func TestUnit_Passes_PassesLikeOnDate(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "day", Op: memoryConstants.OpLike, Value: "2024-03-%"}},
+		Format:      diskModels.Format{"day": diskModels.FormatItem{KeyType: memoryConstants.Date}},
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+
+	march, _ := filter.Passes(diskModels.PageRecord{"day": "2024-03-15"})
+	april, _ := filter.Passes(diskModels.PageRecord{"day": "2024-04-15"})
+
+	assert.True(t, march)
+	assert.False(t, april)
+}
+
+func TestUnit_Passes_PassesDateTimeAcrossZonesAndEpochs(t *testing.T) {
+	format := diskModels.Format{"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime}}
+	values := []any{"2024-03-15T12:30:00+02:00", 1710498600, int64(1710498600000)}
+	for _, value := range values {
+		filter := Filter{
+			FilterItems: []FilterItem{{Key: "created", Op: memoryConstants.OpEqual, Value: value}},
+			Format:      format,
+		}
+		assert.Nil(t, filter.ConvertFilterItems())
+
+		current, err := filter.Passes(diskModels.PageRecord{"created": "2024-03-15T10:30:00Z"})
+		assert.Nil(t, err)
+		legacy, err := filter.Passes(diskModels.PageRecord{"created": "2024-03-15 10:30:00"})
+		assert.Nil(t, err)
+
+		assert.True(t, current)
+		assert.True(t, legacy)
+	}
+}
+
+func TestUnit_ConvertFilterItems_FailsOnInvalidDateTime(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "created", Op: memoryConstants.OpGreater, Value: "yesterday"}},
+		Format:      diskModels.Format{"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime}},
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnInvalidRegex(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "status", Op: memoryConstants.OpRegex, Value: "("}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Passes_PassesMatchOnAllStemmedTerms(t *testing.T) {
+	filter := createTestFilter(t, []FilterItem{{Key: "status", Op: memoryConstants.OpMatch, Value: "Boxes JUMPING"}})
+
+	matches, err := filter.Passes(diskModels.PageRecord{"status": "the box jumped twice", "count": 1})
+	assert.Nil(t, err)
+	partial, err := filter.Passes(diskModels.PageRecord{"status": "the box stayed", "count": 1})
+	assert.Nil(t, err)
+
+	assert.True(t, matches)
+	assert.False(t, partial)
+	assert.Equal(t, []string{"box", "jump"}, filter.FilterItems[0].Value)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnMatchWithoutTerms(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "status", Op: memoryConstants.OpMatch, Value: "?!"}},
+		Format:      createTestFilterFormat(),
+	}
+
+	err := filter.ConvertFilterItems()
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Passes_PassesArrayAndDottedPathOperators(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{
+			{Key: "tags", Op: memoryConstants.OpContains, Value: "red"},
+			{Key: "scores", Op: memoryConstants.OpAny, Value: []any{3, 4}},
+			{Key: "address.city", Op: memoryConstants.OpEqual, Value: "Paris"},
+		},
+		Format: diskModels.Format{
+			"tags":   diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.String)},
+			"scores": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.Int)},
+			"address": diskModels.FormatItem{KeyType: memoryConstants.Object, Format: diskModels.Format{
+				"city": diskModels.FormatItem{KeyType: memoryConstants.String},
+			}},
+		},
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+
+	passes, err := filter.Passes(diskModels.PageRecord{
+		"tags":    []any{"blue", "red"},
+		"scores":  []any{float64(1), float64(4)},
+		"address": map[string]any{"city": "Paris"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, passes)
+
+	passes, err = filter.Passes(diskModels.PageRecord{
+		"tags":    []any{"reddish"},
+		"scores":  []any{float64(3)},
+		"address": map[string]any{"city": "Paris"},
+	})
+	assert.Nil(t, err)
+	assert.False(t, passes)
+
+	passes, err = filter.Passes(diskModels.PageRecord{
+		"tags":    []any{"red"},
+		"scores":  []any{float64(3)},
+		"address": nil,
+	})
+	assert.Nil(t, err)
+	assert.False(t, passes)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnInvalidNestedKeys(t *testing.T) {
+	format := diskModels.Format{
+		"tags": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.String)},
+		"address": diskModels.FormatItem{KeyType: memoryConstants.Object, Format: diskModels.Format{
+			"city": diskModels.FormatItem{KeyType: memoryConstants.String},
+		}},
+	}
+	filterItems := []FilterItem{
+		{Key: "address.street", Op: memoryConstants.OpEqual, Value: "a"},
+		{Key: "tags.city", Op: memoryConstants.OpEqual, Value: "a"},
+		{Key: "tags", Op: memoryConstants.OpEqual, Value: "a"},
+		{Key: "address", Op: memoryConstants.OpContains, Value: "a"},
+	}
+
+	for _, filterItem := range filterItems {
+		filter := Filter{FilterItems: []FilterItem{filterItem}, Format: format}
+		assert.NotNil(t, filter.ConvertFilterItems())
+	}
+}
+
+func TestUnit_Passes_PassesScalarExtensionTypes(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{
+			{Key: "amount", Op: memoryConstants.OpGreater, Value: "0.1"},
+			{Key: "amounts", Op: memoryConstants.OpContains, Value: 0.3},
+			{Key: "id", Op: memoryConstants.OpEqual, Value: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8"},
+			{Key: "status", Op: memoryConstants.OpIn, Value: []any{"paid"}},
+		},
+		Format: diskModels.Format{
+			"amount":  diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2},
+			"amounts": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.Decimal), Scale: 2},
+			"id":      diskModels.FormatItem{KeyType: memoryConstants.Uuid},
+			"status":  diskModels.FormatItem{KeyType: memoryConstants.Enum, Values: []string{"new", "paid"}},
+		},
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+	record := func(amount string) diskModels.PageRecord {
+		return diskModels.PageRecord{
+			"amount":  amount,
+			"amounts": []any{"0.10", "0.30"},
+			"id":      "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"status":  "paid",
+		}
+	}
+
+	passes, err := filter.Passes(record("0.11"))
+	assert.Nil(t, err)
+	assert.True(t, passes)
+
+	passes, err = filter.Passes(record("0.10"))
+	assert.Nil(t, err)
+	assert.False(t, passes)
+}
+
+func TestUnit_ConvertFilterItems_FailsOnInvalidScalarExtensionValues(t *testing.T) {
+	format := diskModels.Format{
+		"amount": diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2},
+		"status": diskModels.FormatItem{KeyType: memoryConstants.Enum, Values: []string{"new"}},
+		"id":     diskModels.FormatItem{KeyType: memoryConstants.Uuid},
+	}
+	filterItems := []FilterItem{
+		{Key: "amount", Op: memoryConstants.OpEqual, Value: "abc"},
+		{Key: "status", Op: memoryConstants.OpEqual, Value: "paid"},
+		{Key: "status", Op: memoryConstants.OpGreater, Value: "new"},
+		{Key: "id", Op: memoryConstants.OpIn, Value: []any{"abc"}},
+	}
+
+	for _, filterItem := range filterItems {
+		filter := Filter{FilterItems: []FilterItem{filterItem}, Format: format}
+		assert.NotNil(t, filter.ConvertFilterItems(), "%+v", filterItem)
+	}
+}
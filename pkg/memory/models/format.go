@@ -1,11 +1,15 @@
 package memoryModels
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"math/big"
+	"reflect"
 	"regexp"
 	"slices"
 	"time"
@@ -81,23 +85,71 @@ func (f *BlobFormatter) HasPartitionStructure() error {
 		if !ok {
 			return errors.New(fmt.Sprintf("Partition key %s not found in Format", partitionKey))
 		}
+		if f.Format[partitionKey].Optional {
+			return errors.New(fmt.Sprintf("Partition key %s cannot be optional", partitionKey))
+		}
+		if memoryConstants.IsNestedType(f.Format[partitionKey].KeyType) {
+			return errors.New(fmt.Sprintf("Partition key %s cannot be of type %s", partitionKey, f.Format[partitionKey].KeyType))
+		}
+	}
+	for partitionKey, bucket := range f.Partition.Buckets {
+		if err := f.checkPartitionBucket(partitionKey, bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *BlobFormatter) checkPartitionBucket(partitionKey string, bucket diskModels.PartitionBucket) error {
+	if !slices.Contains(f.Partition.Keys, partitionKey) {
+		return errors.New(fmt.Sprintf("Partition bucket key %s is not a partition key", partitionKey))
+	}
+	if !slices.Contains(memoryConstants.GetPartitionTypes(), bucket.Type) {
+		return errors.New(fmt.Sprintf("Partition type %s on key %s is not known", bucket.Type, partitionKey))
+	}
+	keyType := f.Format[partitionKey].KeyType
+	if !slices.Contains(memoryConstants.GetPartitionKeyTypes(bucket.Type), keyType) {
+		return errors.New(fmt.Sprintf("Partition key %s of type %s cannot use %s partitioning", partitionKey, keyType, bucket.Type))
+	}
+	switch bucket.Type {
+	case memoryConstants.PartitionRange:
+		if bucket.Period != "" {
+			return errors.New(fmt.Sprintf("Partition key %s cannot use a period with range partitioning", partitionKey))
+		}
+		if len(bucket.Bounds) == 0 || len(bucket.Bounds) > memoryConstants.PartitionMaxBounds {
+			return errors.New(fmt.Sprintf("Partition key %s requires between 1 and %d range bounds", partitionKey, memoryConstants.PartitionMaxBounds))
+		}
+		for i := 1; i < len(bucket.Bounds); i++ {
+			if bucket.Bounds[i] <= bucket.Bounds[i-1] {
+				return errors.New(fmt.Sprintf("Partition key %s range bounds must be increasing", partitionKey))
+			}
+		}
+	case memoryConstants.PartitionTime:
+		if len(bucket.Bounds) > 0 {
+			return errors.New(fmt.Sprintf("Partition key %s cannot use bounds with time partitioning", partitionKey))
+		}
+		if !slices.Contains(memoryConstants.GetPartitionPeriods(), bucket.Period) {
+			return errors.New(fmt.Sprintf("Partition period %s on key %s is not known", bucket.Period, partitionKey))
+		}
 	}
 	return nil
 }
 
 func (f *BlobFormatter) FormatRecord(pageRecord diskModels.PageRecord) (diskModels.PageRecord, error) {
-	if len(f.Format) != len(pageRecord) {
-		return nil, errors.New("record does not match Format length")
+	for key := range pageRecord {
+		if _, ok := f.Format[key]; !ok {
+			return nil, errors.New(fmt.Sprintf("key %s does not exist in %s", key, f.Name))
+		}
 	}
 	newRecord := make(map[string]any)
-	for key, value := range pageRecord {
-		formatItem, ok := f.Format[key]
+	for key, formatItem := range f.Format {
+		value, ok := pageRecord[key]
 		if !ok {
-			return nil, errors.New(fmt.Sprintf("key %s does not exist in %s", key, f.Name))
+			value = formatItem.Default
 		}
-		newValue, err := f.convertRecordValue(value, formatItem)
+		newValue, err := f.convertNullableValue(key, value, formatItem)
 		if err != nil {
-			return nil, errors.New(fmt.Sprintf("error on key %s: %s", key, err.Error()))
+			return nil, err
 		}
 		newRecord[key] = newValue
 	}
@@ -116,19 +168,105 @@ func (f *BlobFormatter) FormatUpdateRecord(pageRecord diskModels.PageRecord) (di
 				return nil, errors.New(fmt.Sprintf("key %s cannot be updated because belongs to partition", key))
 			}
 		}
-		newValue, err := f.convertRecordValue(value, formatItem)
+		newValue, err := f.convertNullableValue(key, value, formatItem)
 		if err != nil {
-			return nil, errors.New(fmt.Sprintf("error on key %s: %s", key, err.Error()))
+			return nil, err
 		}
 		newRecord[key] = newValue
 	}
 	return newRecord, nil
 }
 
+func (f *BlobFormatter) convertNullableValue(key string, value any, formatItem diskModels.FormatItem) (any, error) {
+	if value == nil {
+		if !formatItem.Optional {
+			return nil, errors.New(fmt.Sprintf("key %s is required in %s", key, f.Name))
+		}
+		return nil, nil
+	}
+	newValue, err := f.convertRecordValue(value, formatItem)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("error on key %s: %s", key, err.Error()))
+	}
+	return newValue, nil
+}
+
 func (f *BlobFormatter) checkFormatItem(key string, formatItem diskModels.FormatItem) error {
 	if !slices.Contains(memoryConstants.GetFormatTypes(), formatItem.KeyType) {
 		return errors.New(fmt.Sprintf("key type %s does not exist on key %s", formatItem.KeyType, key))
 	}
+	if err := f.checkNestedFormat(key, formatItem); err != nil {
+		return err
+	}
+	if err := f.checkTypeParams(key, formatItem); err != nil {
+		return err
+	}
+	if formatItem.Default != nil {
+		if _, err := f.convertRecordValue(formatItem.Default, formatItem); err != nil {
+			return errors.New(fmt.Sprintf("default on key %s is invalid: %s", key, err.Error()))
+		}
+	}
+	if len(formatItem.UniqueWith) > 0 && !formatItem.Unique {
+		return errors.New(fmt.Sprintf("key %s has unique keys but is not unique", key))
+	}
+	if formatItem.Unique && memoryConstants.IsNestedType(formatItem.KeyType) {
+		return errors.New(fmt.Sprintf("key %s of type %s cannot be unique", key, formatItem.KeyType))
+	}
+	for i, uniqueKey := range formatItem.UniqueWith {
+		if uniqueItem, ok := f.Format[uniqueKey]; !ok || uniqueKey == key || memoryConstants.IsNestedType(uniqueItem.KeyType) {
+			return errors.New(fmt.Sprintf("unique key %s on key %s is invalid", uniqueKey, key))
+		}
+		if slices.Contains(formatItem.UniqueWith[:i], uniqueKey) {
+			return errors.New(fmt.Sprintf("unique key %s on key %s is duplicated", uniqueKey, key))
+		}
+	}
+	return nil
+}
+
+func (f *BlobFormatter) checkTypeParams(key string, formatItem diskModels.FormatItem) error {
+	baseType := memoryConstants.GetBaseType(formatItem.KeyType)
+	if formatItem.Scale != 0 && baseType != memoryConstants.Decimal {
+		return errors.New(fmt.Sprintf("key %s of type %s cannot have a scale", key, formatItem.KeyType))
+	}
+	if formatItem.Scale < 0 || formatItem.Scale > memoryConstants.DecimalMaxScale {
+		return errors.New(fmt.Sprintf("scale on key %s must be between 0 and %d", key, memoryConstants.DecimalMaxScale))
+	}
+	if baseType != memoryConstants.Enum {
+		if formatItem.Values != nil {
+			return errors.New(fmt.Sprintf("key %s of type %s cannot have enum values", key, formatItem.KeyType))
+		}
+		return nil
+	}
+	if len(formatItem.Values) == 0 {
+		return errors.New(fmt.Sprintf("key %s of type %s requires enum values", key, formatItem.KeyType))
+	}
+	for i, value := range formatItem.Values {
+		if slices.Contains(formatItem.Values[:i], value) {
+			return errors.New(fmt.Sprintf("enum value %s on key %s is duplicated", value, key))
+		}
+	}
+	return nil
+}
+
+func (f *BlobFormatter) checkNestedFormat(key string, formatItem diskModels.FormatItem) error {
+	if !memoryConstants.HasNestedFormat(formatItem.KeyType) {
+		if formatItem.Format != nil {
+			return errors.New(fmt.Sprintf("key %s of type %s cannot have a nested format", key, formatItem.KeyType))
+		}
+		return nil
+	}
+	if len(formatItem.Format) == 0 {
+		return errors.New(fmt.Sprintf("key %s of type %s requires a nested format", key, formatItem.KeyType))
+	}
+	for nestedKey, nestedItem := range formatItem.Format {
+		if nestedItem.Unique {
+			return errors.New(fmt.Sprintf("nested key %s on key %s cannot be unique", nestedKey, key))
+		}
+	}
+	formatter := CreateFormatter(f.Name, formatItem.Format)
+	if err := formatter.HasFormatStructure(); err != nil {
+		return errors.New(fmt.Sprintf("error on key %s: %s", key, err.Error()))
+	}
 	return nil
 }
 
@@ -151,18 +289,158 @@ func (f *BlobFormatter) convertRecordValue(value any, formatItem diskModels.Form
 		}
 		return converted, nil
 	case memoryConstants.Date:
-		fallthrough
+		if dateString, ok := value.(string); ok {
+			if _, err := time.Parse(time.DateOnly, dateString); err == nil {
+				return dateString, nil
+			}
+		}
+		timeValue, err := memoryUtils.ConvertToTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return timeValue.Format(time.DateOnly), nil
 	case memoryConstants.DateTime:
-		timeValueInt, err := memoryUtils.ConvertToInt(value)
+		timeValue, err := memoryUtils.ConvertToTime(value)
 		if err != nil {
 			return nil, err
 		}
-		timeValue := time.Unix(int64(timeValueInt), 0)
-		if formatItem.KeyType == memoryConstants.Date {
-			return timeValue.Format(time.DateOnly), nil
-		} else {
-			return timeValue.Format(time.DateTime), nil
+		return memoryUtils.FormatDateTime(timeValue), nil
+	case memoryConstants.Uuid:
+		switch typedValue := value.(type) {
+		case uuid.UUID:
+			return typedValue.String(), nil
+		case string:
+			converted, err := uuid.Parse(typedValue)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("%s is not a valid uuid", typedValue))
+			}
+			return converted.String(), nil
+		}
+		return nil, errors.New(fmt.Sprintf("%+v could not be converted to uuid", value))
+	case memoryConstants.Decimal:
+		return convertDecimalValue(value, formatItem.Scale)
+	case memoryConstants.Enum:
+		converted, ok := value.(string)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("%+v could not be converted to string", value))
 		}
+		if !slices.Contains(formatItem.Values, converted) {
+			return nil, errors.New(fmt.Sprintf("%s is not one of %v", converted, formatItem.Values))
+		}
+		return converted, nil
+	case memoryConstants.Bytes:
+		switch typedValue := value.(type) {
+		case []byte:
+			return base64.StdEncoding.EncodeToString(typedValue), nil
+		case string:
+			decoded, err := base64.StdEncoding.DecodeString(typedValue)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("%s is not valid base64", typedValue))
+			}
+			return base64.StdEncoding.EncodeToString(decoded), nil
+		}
+		return nil, errors.New(fmt.Sprintf("%+v could not be converted to bytes", value))
+	case memoryConstants.Object:
+		var record diskModels.PageRecord
+		switch typedValue := value.(type) {
+		case map[string]any:
+			record = typedValue
+		case diskModels.PageRecord:
+			record = typedValue
+		default:
+			return nil, errors.New(fmt.Sprintf("%+v could not be converted to object", value))
+		}
+		formatter := CreateFormatter(f.Name, formatItem.Format)
+		newRecord, err := formatter.FormatRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any(newRecord), nil
+	}
+	if elementType, ok := memoryConstants.GetArrayElementType(formatItem.KeyType); ok {
+		elementItem := formatItem
+		elementItem.KeyType = elementType
+		return f.convertArrayValue(value, elementItem)
 	}
 	return nil, errors.New("type not handled")
 }
+
+func (f *BlobFormatter) convertArrayValue(value any, elementItem diskModels.FormatItem) ([]any, error) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		return nil, errors.New(fmt.Sprintf("%+v could not be converted to array", value))
+	}
+	values := []any{}
+	for i := 0; i < reflectValue.Len(); i++ {
+		element := reflectValue.Index(i).Interface()
+		if element == nil {
+			return nil, errors.New(fmt.Sprintf("array element %d cannot be null", i))
+		}
+		convertedElement, err := f.convertRecordValue(element, elementItem)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("array element %d: %s", i, err.Error()))
+		}
+		values = append(values, convertedElement)
+	}
+	return values, nil
+}
+
+func hasDateTimeKeys(format diskModels.Format) bool {
+	for _, formatItem := range format {
+		if memoryConstants.GetBaseType(formatItem.KeyType) == memoryConstants.DateTime || hasDateTimeKeys(formatItem.Format) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeLegacyValue(value any, formatItem diskModels.FormatItem) (any, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		if formatItem.KeyType != memoryConstants.DateTime {
+			return value, false
+		}
+		parsed, err := time.ParseInLocation(time.DateTime, typedValue, time.Local)
+		if err != nil {
+			return value, false
+		}
+		return memoryUtils.FormatDateTime(parsed.UTC()), true
+	case map[string]any:
+		changed := false
+		for key, nestedValue := range typedValue {
+			if nestedItem, ok := formatItem.Format[key]; ok {
+				normalizedValue, normalized := normalizeLegacyValue(nestedValue, nestedItem)
+				typedValue[key] = normalizedValue
+				changed = changed || normalized
+			}
+		}
+		return typedValue, changed
+	case []any:
+		elementType, ok := memoryConstants.GetArrayElementType(formatItem.KeyType)
+		if !ok {
+			return value, false
+		}
+		elementItem := formatItem
+		elementItem.KeyType = elementType
+		changed := false
+		for i, element := range typedValue {
+			normalizedValue, normalized := normalizeLegacyValue(element, elementItem)
+			typedValue[i] = normalizedValue
+			changed = changed || normalized
+		}
+		return typedValue, changed
+	}
+	return value, false
+}
+
+func convertDecimalValue(value any, scale int) (string, error) {
+	decimal, err := memoryUtils.ConvertToDecimal(value)
+	if err != nil {
+		return "", err
+	}
+	scaled := new(big.Rat).Mul(decimal, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	if !scaled.IsInt() {
+		return "", errors.New(fmt.Sprintf("%v has more than %d decimal places", value, scale))
+	}
+	return decimal.FloatString(scale), nil
+}
@@ -0,0 +1,338 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestUnit_FormatRecord_FormatsStoredDateValues(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"day":     diskModels.FormatItem{KeyType: memoryConstants.Date},
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+	})
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{
+		"day":     "2024-03-15",
+		"created": "2024-03-15 10:30:00",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{"day": "2024-03-15", "created": "2024-03-15T10:30:00Z"}, pageRecord)
+}
+
+func TestUnit_FormatRecord_FormatsUnixDateValues(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+	})
+	unix := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC).Unix()
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{"created": unix})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-03-15T10:30:00Z", pageRecord["created"])
+}
+
+func TestUnit_FormatRecord_FormatsUnixMillisDateValues(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+	})
+	millis := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC).UnixMilli()
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{"created": millis})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-03-15T10:30:00Z", pageRecord["created"])
+}
+
+func TestUnit_FormatRecord_FormatsZonedDateValuesInUTC(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"day":     diskModels.FormatItem{KeyType: memoryConstants.Date},
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+	})
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{
+		"day":     "2024-03-15T22:30:00-05:00",
+		"created": "2024-03-15T10:30:00.750+02:00",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{"day": "2024-03-16", "created": "2024-03-15T08:30:00.75Z"}, pageRecord)
+}
+
+func TestUnit_FormatRecord_KeepsSubSecondDateTimes(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+	})
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{"created": "2024-01-01T00:00:00.250Z"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00.25Z", pageRecord["created"])
+}
+
+func TestUnit_FormatRecord_FailsOnMalformedDateString(t *testing.T) {
+	formatter := CreateFormatter("events", diskModels.Format{
+		"day": diskModels.FormatItem{KeyType: memoryConstants.Date},
+	})
+
+	_, err := formatter.FormatRecord(diskModels.PageRecord{"day": "15/03/2024"})
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_HasFormatStructure_AcceptsCompositeUniqueKeys(t *testing.T) {
+	formatter := CreateFormatter("members", diskModels.Format{
+		"org":  diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"name"}},
+		"name": diskModels.FormatItem{KeyType: memoryConstants.String},
+	})
+
+	assert.Nil(t, formatter.HasFormatStructure())
+}
+
+func TestUnit_HasFormatStructure_FailsOnInvalidUniqueKeys(t *testing.T) {
+	formats := []diskModels.Format{
+		{"org": diskModels.FormatItem{KeyType: memoryConstants.String, UniqueWith: []string{"name"}}, "name": diskModels.FormatItem{KeyType: memoryConstants.String}},
+		{"org": diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"missing"}}},
+		{"org": diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"org"}}},
+		{"org": diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true, UniqueWith: []string{"name", "name"}}, "name": diskModels.FormatItem{KeyType: memoryConstants.String}},
+	}
+	for _, format := range formats {
+		formatter := CreateFormatter("members", format)
+		assert.NotNil(t, formatter.HasFormatStructure())
+	}
+}
+
+func TestUnit_FormatRecord_FillsOptionalAndDefaultValues(t *testing.T) {
+	formatter := CreateFormatter("members", diskModels.Format{
+		"name":   diskModels.FormatItem{KeyType: memoryConstants.String},
+		"nick":   diskModels.FormatItem{KeyType: memoryConstants.String, Optional: true},
+		"active": diskModels.FormatItem{KeyType: memoryConstants.Bool, Default: true},
+		"score":  diskModels.FormatItem{KeyType: memoryConstants.Int, Optional: true, Default: float64(5)},
+	})
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{"name": "a", "score": nil})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{"name": "a", "nick": nil, "active": true, "score": nil}, pageRecord)
+}
+
+func TestUnit_FormatRecord_FailsOnMissingRequiredValue(t *testing.T) {
+	formatter := CreateFormatter("members", diskModels.Format{
+		"name":   diskModels.FormatItem{KeyType: memoryConstants.String},
+		"active": diskModels.FormatItem{KeyType: memoryConstants.Bool, Default: true},
+	})
+
+	_, missingErr := formatter.FormatRecord(diskModels.PageRecord{"active": false})
+	_, nullErr := formatter.FormatRecord(diskModels.PageRecord{"name": "a", "active": nil})
+
+	assert.NotNil(t, missingErr)
+	assert.NotNil(t, nullErr)
+}
+
+func TestUnit_FormatUpdateRecord_AllowsNullOnOptionalKeys(t *testing.T) {
+	formatter := CreateFormatter("members", diskModels.Format{
+		"name": diskModels.FormatItem{KeyType: memoryConstants.String},
+		"nick": diskModels.FormatItem{KeyType: memoryConstants.String, Optional: true},
+	})
+
+	pageRecord, err := formatter.FormatUpdateRecord(diskModels.PageRecord{"nick": nil})
+	_, requiredErr := formatter.FormatUpdateRecord(diskModels.PageRecord{"name": nil})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.PageRecord{"nick": nil}, pageRecord)
+	assert.NotNil(t, requiredErr)
+}
+
+func TestUnit_HasFormatStructure_FailsOnInvalidDefault(t *testing.T) {
+	formatter := CreateFormatter("members", diskModels.Format{
+		"score": diskModels.FormatItem{KeyType: memoryConstants.Int, Default: "high"},
+	})
+
+	assert.NotNil(t, formatter.HasFormatStructure())
+}
+
+func createTestNestedFormatter() BlobFormatter {
+	return CreateFormatter("events", diskModels.Format{
+		"tags": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.Int)},
+		"address": diskModels.FormatItem{KeyType: memoryConstants.Object, Format: diskModels.Format{
+			"city": diskModels.FormatItem{KeyType: memoryConstants.String},
+			"zip":  diskModels.FormatItem{KeyType: memoryConstants.String, Optional: true},
+		}},
+		"items": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.Object), Format: diskModels.Format{
+			"sku": diskModels.FormatItem{KeyType: memoryConstants.String},
+		}},
+	})
+}
+
+func TestUnit_FormatRecord_FormatsNestedValues(t *testing.T) {
+	formatter := createTestNestedFormatter()
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{
+		"tags":    []any{float64(1), 2},
+		"address": map[string]any{"city": "Paris"},
+		"items":   []any{map[string]any{"sku": "a"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Nil(t, formatter.HasFormatStructure())
+	assert.Equal(t, diskModels.PageRecord{
+		"tags":    []any{1, 2},
+		"address": map[string]any{"city": "Paris", "zip": nil},
+		"items":   []any{map[string]any{"sku": "a"}},
+	}, pageRecord)
+}
+
+func TestUnit_FormatRecord_FailsOnInvalidNestedValues(t *testing.T) {
+	formatter := createTestNestedFormatter()
+	validRecord := func(key string, value any) diskModels.PageRecord {
+		pageRecord := diskModels.PageRecord{
+			"tags":    []any{1},
+			"address": map[string]any{"city": "Paris"},
+			"items":   []any{},
+		}
+		pageRecord[key] = value
+		return pageRecord
+	}
+
+	_, notArrayErr := formatter.FormatRecord(validRecord("tags", 1))
+	_, elementErr := formatter.FormatRecord(validRecord("tags", []any{"a"}))
+	_, nullElementErr := formatter.FormatRecord(validRecord("tags", []any{nil}))
+	_, unknownKeyErr := formatter.FormatRecord(validRecord("address", map[string]any{"city": "Paris", "street": "a"}))
+	_, missingKeyErr := formatter.FormatRecord(validRecord("items", []any{map[string]any{}}))
+
+	assert.NotNil(t, notArrayErr)
+	assert.NotNil(t, elementErr)
+	assert.NotNil(t, nullElementErr)
+	assert.Contains(t, nullElementErr.Error(), "array element 0 cannot be null")
+	assert.NotNil(t, unknownKeyErr)
+	assert.Contains(t, unknownKeyErr.Error(), "key street does not exist")
+	assert.NotNil(t, missingKeyErr)
+}
+
+func TestUnit_HasFormatStructure_FailsOnInvalidNestedFormats(t *testing.T) {
+	formats := []diskModels.Format{
+		{"address": {KeyType: memoryConstants.Object}},
+		{"tags": {KeyType: memoryConstants.GetArrayType(memoryConstants.String), Format: diskModels.Format{"a": {KeyType: memoryConstants.String}}}},
+		{"address": {KeyType: memoryConstants.Object, Format: diskModels.Format{"City": {KeyType: memoryConstants.String}}}},
+		{"address": {KeyType: memoryConstants.Object, Format: diskModels.Format{"city": {KeyType: memoryConstants.String, Unique: true}}}},
+		{"tags": {KeyType: memoryConstants.GetArrayType(memoryConstants.String), Unique: true}},
+		{"tags": {KeyType: "array<array<int>>"}},
+	}
+
+	for _, format := range formats {
+		formatter := CreateFormatter("events", format)
+		assert.NotNil(t, formatter.HasFormatStructure())
+	}
+}
+
+func TestUnit_HasPartitionStructure_FailsOnNestedPartitionKey(t *testing.T) {
+	formatter := CreateFormatterWithPartition("events", diskModels.Format{
+		"tags": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.String)},
+	}, diskModels.Partition{Keys: []string{"tags"}})
+
+	assert.NotNil(t, formatter.HasPartitionStructure())
+}
+
+func TestUnit_HasPartitionStructure_ChecksPartitionBuckets(t *testing.T) {
+	format := diskModels.Format{
+		"qty":     diskModels.FormatItem{KeyType: memoryConstants.Int},
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+		"region":  diskModels.FormatItem{KeyType: memoryConstants.String},
+	}
+	valid := CreateFormatterWithPartition("events", format, diskModels.Partition{
+		Keys: []string{"region", "qty", "created"},
+		Buckets: map[string]diskModels.PartitionBucket{
+			"qty":     {Type: memoryConstants.PartitionRange, Bounds: []float64{10, 100}},
+			"created": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay},
+		},
+	})
+	invalidBuckets := []map[string]diskModels.PartitionBucket{
+		{"other": {Type: memoryConstants.PartitionRange, Bounds: []float64{10}}},
+		{"qty": {Type: "list"}},
+		{"region": {Type: memoryConstants.PartitionRange, Bounds: []float64{10}}},
+		{"qty": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay}},
+		{"qty": {Type: memoryConstants.PartitionRange}},
+		{"qty": {Type: memoryConstants.PartitionRange, Bounds: []float64{100, 10}}},
+		{"qty": {Type: memoryConstants.PartitionRange, Bounds: []float64{10}, Period: memoryConstants.PeriodDay}},
+		{"created": {Type: memoryConstants.PartitionTime, Period: "week"}},
+		{"created": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay, Bounds: []float64{1}}},
+	}
+
+	assert.Nil(t, valid.HasPartitionStructure())
+	for _, buckets := range invalidBuckets {
+		formatter := CreateFormatterWithPartition("events", format, diskModels.Partition{
+			Keys:    []string{"region", "qty", "created"},
+			Buckets: buckets,
+		})
+		assert.NotNil(t, formatter.HasPartitionStructure(), buckets)
+	}
+}
+
+func TestUnit_FormatRecord_FormatsScalarExtensionTypes(t *testing.T) {
+	formatter := CreateFormatter("orders", diskModels.Format{
+		"id":     diskModels.FormatItem{KeyType: memoryConstants.Uuid},
+		"amount": diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2},
+		"status": diskModels.FormatItem{KeyType: memoryConstants.Enum, Values: []string{"new", "paid"}},
+		"data":   diskModels.FormatItem{KeyType: memoryConstants.Bytes},
+	})
+
+	pageRecord, err := formatter.FormatRecord(diskModels.PageRecord{
+		"id":     "{6BA7B810-9DAD-11D1-80B4-00C04FD430C8}",
+		"amount": 12.5,
+		"status": "paid",
+		"data":   []byte("hi"),
+	})
+
+	assert.Nil(t, err)
+	assert.Nil(t, formatter.HasFormatStructure())
+	assert.Equal(t, diskModels.PageRecord{
+		"id":     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"amount": "12.50",
+		"status": "paid",
+		"data":   "aGk=",
+	}, pageRecord)
+}
+
+func TestUnit_FormatRecord_FailsOnInvalidScalarExtensionValues(t *testing.T) {
+	formatter := CreateFormatter("orders", diskModels.Format{
+		"id":     diskModels.FormatItem{KeyType: memoryConstants.Uuid, Optional: true},
+		"amount": diskModels.FormatItem{KeyType: memoryConstants.Decimal, Scale: 2, Optional: true},
+		"status": diskModels.FormatItem{KeyType: memoryConstants.Enum, Values: []string{"new"}, Optional: true},
+		"data":   diskModels.FormatItem{KeyType: memoryConstants.Bytes, Optional: true},
+	})
+	invalidRecords := []diskModels.PageRecord{
+		{"id": "not-a-uuid"},
+		{"amount": "1.005"},
+		{"amount": "abc"},
+		{"amount": "1/2"},
+		{"amount": "1e1"},
+		{"amount": "0x10"},
+		{"amount": ".5"},
+		{"status": "paid"},
+		{"data": "%%%"},
+	}
+
+	for _, pageRecord := range invalidRecords {
+		_, err := formatter.FormatRecord(pageRecord)
+		assert.NotNil(t, err, "%+v", pageRecord)
+	}
+}
+
+func TestUnit_HasFormatStructure_FailsOnInvalidTypeParams(t *testing.T) {
+	formats := []diskModels.Format{
+		{"qty": {KeyType: memoryConstants.Int, Scale: 2}},
+		{"amount": {KeyType: memoryConstants.Decimal, Scale: memoryConstants.DecimalMaxScale + 1}},
+		{"status": {KeyType: memoryConstants.Enum}},
+		{"status": {KeyType: memoryConstants.Enum, Values: []string{"a", "a"}}},
+		{"name": {KeyType: memoryConstants.String, Values: []string{"a"}}},
+		{"status": {KeyType: memoryConstants.Enum, Values: []string{"a"}, Default: "b"}},
+	}
+
+	for _, format := range formats {
+		formatter := CreateFormatter("orders", format)
+		assert.NotNil(t, formatter.HasFormatStructure(), "%+v", format)
+	}
+}
@@ -15,6 +15,7 @@ type IndexMapI interface {
 	Add(pageRecordId string) (*Index, error)
 	Delete(prefix string, fileName string) error
 	GetCurrentIndex(prefix string) (*Index, error)
+	Truncate() error
 }
 
 type IndexMap struct {
@@ -131,6 +132,17 @@ func (im *IndexMap) GetCurrentIndex(prefix string) (*Index, error) {
 	return nil, errors.New("current index not found")
 }
 
+func (im *IndexMap) Truncate() error {
+	im.m.Lock()
+	defer im.m.Unlock()
+	if err := im.indexDiskManager.Truncate(im.db, im.blob); err != nil {
+		return err
+	}
+	im.itemMap = IndexPrefixMap{}
+	im.currentPages = IndexPrefixCurrentPageMap{}
+	return nil
+}
+
 type Index struct {
 	m                *sync.Mutex
 	fileName         string
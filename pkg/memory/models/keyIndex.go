@@ -0,0 +1,568 @@
+package memoryModels
+
+import (
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+type KeyIndexMapI interface {
+	Initialize() error
+	Create(keyIndexItem diskModels.KeyIndexItem, pageRecordsMap PageRecordsMap) error
+	Delete(key string) error
+	GetAll() diskModels.KeyIndexes
+	AddRecords(pageRecordsMap PageRecordsMap) error
+	UpdateRecords(pageRecordsMap PageRecordsMap, updateRecord diskModels.PageRecord) error
+	DeleteRecords(pageRecordsMap PageRecordsMap) error
+	GetPageFiles(filterItems []FilterItem) (map[string]bool, bool, error)
+	GetOrdered(key string) (*OrderedKeyIndex, bool)
+	Truncate() error
+}
+
+type KeyIndexI interface {
+	GetIndexType() string
+	Build(pageRecordsMap PageRecordsMap) error
+	Add(pageRecordsMap PageRecordsMap) error
+	Replace(pageRecordsMap PageRecordsMap, value any) error
+	Remove(pageRecordsMap PageRecordsMap) error
+	Lookup(filterItem FilterItem) (diskModels.IndexRecords, bool, error)
+}
+
+type KeyIndexMap struct {
+	m                   *sync.Mutex
+	itemMap             map[string]KeyIndexI
+	db                  string
+	blob                string
+	format              diskModels.Format
+	keyIndexDiskManager diskManagers.KeyIndexManager
+	dataCaching         bool
+}
+
+func NewKeyIndexMap(db string, blob string, dataLocation string, format diskModels.Format, dataCaching bool) KeyIndexMapI {
+	return &KeyIndexMap{
+		m:                   &sync.Mutex{},
+		itemMap:             make(map[string]KeyIndexI),
+		db:                  db,
+		blob:                blob,
+		format:              format,
+		keyIndexDiskManager: diskManagers.CreateKeyIndexManager(dataLocation),
+		dataCaching:         dataCaching,
+	}
+}
+
+func (kim *KeyIndexMap) Initialize() error {
+	keyIndexes, err := kim.keyIndexDiskManager.GetAll(kim.db, kim.blob)
+	if err != nil {
+		return err
+	}
+	for key, keyIndexItem := range keyIndexes {
+		kim.itemMap[key] = kim.newKeyIndex(keyIndexItem)
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) Create(keyIndexItem diskModels.KeyIndexItem, pageRecordsMap PageRecordsMap) error {
+	kim.m.Lock()
+	defer kim.m.Unlock()
+	existing, exists := kim.itemMap[keyIndexItem.Key]
+	if exists {
+		if !kim.isUniqueHashIndex(keyIndexItem.Key, existing) || keyIndexItem.IndexType == memoryConstants.KeyIndexHash {
+			return fmt.Errorf("index on key %s already exists", keyIndexItem.Key)
+		}
+		if err := kim.keyIndexDiskManager.Delete(kim.db, kim.blob, keyIndexItem.Key); err != nil {
+			return err
+		}
+		delete(kim.itemMap, keyIndexItem.Key)
+	}
+	if err := kim.create(keyIndexItem, pageRecordsMap); err != nil {
+		if exists {
+			_ = kim.create(diskModels.KeyIndexItem{Key: keyIndexItem.Key, IndexType: memoryConstants.KeyIndexHash}, pageRecordsMap)
+		}
+		return err
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) create(keyIndexItem diskModels.KeyIndexItem, pageRecordsMap PageRecordsMap) error {
+	if err := kim.keyIndexDiskManager.Create(kim.db, kim.blob, keyIndexItem); err != nil {
+		return err
+	}
+	keyIndex := kim.newKeyIndex(keyIndexItem)
+	if err := keyIndex.Build(pageRecordsMap); err != nil {
+		_ = kim.keyIndexDiskManager.Delete(kim.db, kim.blob, keyIndexItem.Key)
+		return err
+	}
+	kim.itemMap[keyIndexItem.Key] = keyIndex
+	return nil
+}
+
+// isUniqueHashIndex reports whether keyIndex is the hash index kept on the leading key of a unique
+// constraint, which another index type may replace since unique checks fall back to a page scan.
+func (kim *KeyIndexMap) isUniqueHashIndex(key string, keyIndex KeyIndexI) bool {
+	if keyIndex.GetIndexType() != memoryConstants.KeyIndexHash {
+		return false
+	}
+	for _, uniqueKeys := range kim.format.GetUniqueKeys() {
+		if uniqueKeys[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (kim *KeyIndexMap) Delete(key string) error {
+	kim.m.Lock()
+	defer kim.m.Unlock()
+	if _, ok := kim.itemMap[key]; !ok {
+		return fmt.Errorf("index on key %s does not exist", key)
+	}
+	if err := kim.keyIndexDiskManager.Delete(kim.db, kim.blob, key); err != nil {
+		return err
+	}
+	delete(kim.itemMap, key)
+	return nil
+}
+
+func (kim *KeyIndexMap) GetAll() diskModels.KeyIndexes {
+	kim.m.Lock()
+	defer kim.m.Unlock()
+	keyIndexes := diskModels.KeyIndexes{}
+	for key, keyIndex := range kim.itemMap {
+		keyIndexes[key] = diskModels.KeyIndexItem{Key: key, IndexType: keyIndex.GetIndexType()}
+	}
+	return keyIndexes
+}
+
+func (kim *KeyIndexMap) AddRecords(pageRecordsMap PageRecordsMap) error {
+	for _, keyIndex := range kim.getKeyIndexes() {
+		if err := keyIndex.Add(pageRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) UpdateRecords(pageRecordsMap PageRecordsMap, updateRecord diskModels.PageRecord) error {
+	for key, keyIndex := range kim.getKeyIndexes() {
+		value, ok := updateRecord[key]
+		if !ok {
+			continue
+		}
+		if err := keyIndex.Replace(pageRecordsMap, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) DeleteRecords(pageRecordsMap PageRecordsMap) error {
+	for _, keyIndex := range kim.getKeyIndexes() {
+		if err := keyIndex.Remove(pageRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) Truncate() error {
+	for _, keyIndex := range kim.getKeyIndexes() {
+		if err := keyIndex.Build(PageRecordsMap{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kim *KeyIndexMap) GetPageFiles(filterItems []FilterItem) (map[string]bool, bool, error) {
+	var matches diskModels.IndexRecords
+	keyIndexes := kim.getKeyIndexes()
+	for _, filterItem := range filterItems {
+		keyIndex, ok := keyIndexes[filterItem.Key]
+		if !ok {
+			continue
+		}
+		indexRecords, ok, err := keyIndex.Lookup(filterItem)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+		if matches == nil {
+			matches = indexRecords
+			continue
+		}
+		for pageRecordId := range matches {
+			if _, ok := indexRecords[pageRecordId]; !ok {
+				delete(matches, pageRecordId)
+			}
+		}
+	}
+	if matches == nil {
+		return nil, false, nil
+	}
+	pageFiles := make(map[string]bool)
+	for _, pageFile := range matches {
+		pageFiles[pageFile] = true
+	}
+	return pageFiles, true, nil
+}
+
+func (kim *KeyIndexMap) GetOrdered(key string) (*OrderedKeyIndex, bool) {
+	kim.m.Lock()
+	defer kim.m.Unlock()
+	orderedKeyIndex, ok := kim.itemMap[key].(*OrderedKeyIndex)
+	return orderedKeyIndex, ok
+}
+
+func (kim *KeyIndexMap) getKeyIndexes() map[string]KeyIndexI {
+	kim.m.Lock()
+	defer kim.m.Unlock()
+	keyIndexes := make(map[string]KeyIndexI)
+	for key, keyIndex := range kim.itemMap {
+		keyIndexes[key] = keyIndex
+	}
+	return keyIndexes
+}
+
+func (kim *KeyIndexMap) newKeyIndex(keyIndexItem diskModels.KeyIndexItem) KeyIndexI {
+	if keyIndexItem.IndexType == memoryConstants.KeyIndexOrdered {
+		return &OrderedKeyIndex{
+			m:                   &sync.Mutex{},
+			key:                 keyIndexItem.Key,
+			keyType:             kim.format[keyIndexItem.Key].KeyType,
+			db:                  kim.db,
+			blob:                kim.blob,
+			keyIndexDiskManager: kim.keyIndexDiskManager,
+			dataCaching:         kim.dataCaching,
+		}
+	}
+	return &KeyIndex{
+		m:                   &sync.Mutex{},
+		key:                 keyIndexItem.Key,
+		keyType:             kim.format[keyIndexItem.Key].KeyType,
+		indexType:           keyIndexItem.IndexType,
+		db:                  kim.db,
+		blob:                kim.blob,
+		keyIndexDiskManager: kim.keyIndexDiskManager,
+		dataCaching:         kim.dataCaching,
+	}
+}
+
+type KeyIndex struct {
+	m                   *sync.Mutex
+	key                 string
+	keyType             string
+	indexType           string
+	db                  string
+	blob                string
+	keyIndexDiskManager diskManagers.KeyIndexManager
+	cache               map[string]diskModels.KeyIndexRecords
+	dataCaching         bool
+}
+
+func (ki *KeyIndex) GetIndexType() string {
+	return ki.indexType
+}
+
+func (ki *KeyIndex) Build(pageRecordsMap PageRecordsMap) error {
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	prefixes, err := ki.keyIndexDiskManager.GetPrefixes(ki.db, ki.blob, ki.key)
+	if err != nil {
+		return err
+	}
+	shards := groupByPrefix(pageRecordsMap, ki.keyIndexDiskManager.GetPageRecordIdPrefix)
+	for _, prefix := range prefixes {
+		if _, ok := shards[prefix]; !ok {
+			shards[prefix] = PageRecordsMap{}
+		}
+	}
+	for prefix, shardRecordsMap := range shards {
+		if err = ki.add(prefix, diskModels.KeyIndexRecords{}, shardRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ki *KeyIndex) Add(pageRecordsMap PageRecordsMap) error {
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, ki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		keyIndexRecords, err := ki._read(prefix)
+		if err != nil {
+			return err
+		}
+		if err = ki.add(prefix, keyIndexRecords, shardRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ki *KeyIndex) add(prefix string, keyIndexRecords diskModels.KeyIndexRecords, pageRecordsMap PageRecordsMap) error {
+	for pageFile, pageRecords := range pageRecordsMap {
+		for pageRecordId, pageRecord := range pageRecords {
+			ki.addItem(keyIndexRecords, pageRecord[ki.key], pageRecordId, pageFile)
+		}
+	}
+	return ki._write(prefix, keyIndexRecords)
+}
+
+func (ki *KeyIndex) Replace(pageRecordsMap PageRecordsMap, value any) error {
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, ki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		keyIndexRecords, err := ki._read(prefix)
+		if err != nil {
+			return err
+		}
+		for valueKey, indexRecords := range keyIndexRecords {
+			for _, pageRecords := range shardRecordsMap {
+				for pageRecordId := range pageRecords {
+					delete(indexRecords, pageRecordId)
+				}
+			}
+			if len(indexRecords) == 0 {
+				delete(keyIndexRecords, valueKey)
+			}
+		}
+		for pageFile, pageRecords := range shardRecordsMap {
+			for pageRecordId := range pageRecords {
+				ki.addItem(keyIndexRecords, value, pageRecordId, pageFile)
+			}
+		}
+		if err = ki._write(prefix, keyIndexRecords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ki *KeyIndex) Remove(pageRecordsMap PageRecordsMap) error {
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, ki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		keyIndexRecords, err := ki._read(prefix)
+		if err != nil {
+			return err
+		}
+		for _, pageRecords := range shardRecordsMap {
+			for pageRecordId, pageRecord := range pageRecords {
+				for _, valueKey := range ki.getValueKeys(pageRecord[ki.key]) {
+					delete(keyIndexRecords[valueKey], pageRecordId)
+					if len(keyIndexRecords[valueKey]) == 0 {
+						delete(keyIndexRecords, valueKey)
+					}
+				}
+			}
+		}
+		if err = ki._write(prefix, keyIndexRecords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ki *KeyIndex) Lookup(filterItem FilterItem) (diskModels.IndexRecords, bool, error) {
+	if ki.indexType == memoryConstants.KeyIndexText {
+		return ki.lookupTerms(filterItem)
+	}
+	var values []any
+	switch filterItem.Op {
+	case memoryConstants.OpEqual:
+		values = []any{filterItem.Value}
+	case memoryConstants.OpIn:
+		inValues, ok := filterItem.Value.([]any)
+		if !ok {
+			return nil, false, nil
+		}
+		values = inValues
+	default:
+		return nil, false, nil
+	}
+	valueKeys := []string{}
+	for _, value := range values {
+		if valueKey, ok := GetKeyIndexValue(value, ki.keyType); ok {
+			valueKeys = append(valueKeys, valueKey)
+		}
+	}
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	indexRecords := diskModels.IndexRecords{}
+	err := ki.readShards(func(keyIndexRecords diskModels.KeyIndexRecords) {
+		for _, valueKey := range valueKeys {
+			for pageRecordId, pageFile := range keyIndexRecords[valueKey] {
+				indexRecords[pageRecordId] = pageFile
+			}
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return indexRecords, true, nil
+}
+
+func (ki *KeyIndex) lookupTerms(filterItem FilterItem) (diskModels.IndexRecords, bool, error) {
+	terms, ok := filterItem.Value.([]string)
+	if filterItem.Op != memoryConstants.OpMatch || !ok {
+		return nil, false, nil
+	}
+	ki.m.Lock()
+	defer ki.m.Unlock()
+	indexRecords := diskModels.IndexRecords{}
+	err := ki.readShards(func(keyIndexRecords diskModels.KeyIndexRecords) {
+		for pageRecordId, pageFile := range keyIndexRecords[terms[0]] {
+			matchesAll := true
+			for _, term := range terms[1:] {
+				if _, ok := keyIndexRecords[term][pageRecordId]; !ok {
+					matchesAll = false
+					break
+				}
+			}
+			if matchesAll {
+				indexRecords[pageRecordId] = pageFile
+			}
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return indexRecords, true, nil
+}
+
+func (ki *KeyIndex) addItem(keyIndexRecords diskModels.KeyIndexRecords, value any, pageRecordId string, pageFile string) {
+	for _, valueKey := range ki.getValueKeys(value) {
+		if _, ok := keyIndexRecords[valueKey]; !ok {
+			keyIndexRecords[valueKey] = diskModels.IndexRecords{}
+		}
+		keyIndexRecords[valueKey][pageRecordId] = pageFile
+	}
+}
+
+func (ki *KeyIndex) getValueKeys(value any) []string {
+	if ki.indexType == memoryConstants.KeyIndexText {
+		text, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		return memoryUtils.GetUniqueTokens(text)
+	}
+	valueKey, ok := GetKeyIndexValue(value, ki.keyType)
+	if !ok {
+		return nil
+	}
+	return []string{valueKey}
+}
+
+func (ki *KeyIndex) readShards(readFunc func(keyIndexRecords diskModels.KeyIndexRecords)) error {
+	prefixes, err := ki.keyIndexDiskManager.GetPrefixes(ki.db, ki.blob, ki.key)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range prefixes {
+		keyIndexRecords, err := ki._read(prefix)
+		if err != nil {
+			return err
+		}
+		readFunc(keyIndexRecords)
+	}
+	return nil
+}
+
+func (ki *KeyIndex) _read(prefix string) (diskModels.KeyIndexRecords, error) {
+	if ki.dataCaching && ki.cache[prefix] != nil {
+		return ki.cache[prefix], nil
+	}
+	data, err := ki.keyIndexDiskManager.GetData(ki.db, ki.blob, ki.key, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = diskModels.KeyIndexRecords{}
+	}
+	if ki.dataCaching {
+		ki.setCache(prefix, data)
+	}
+	return data, nil
+}
+
+func (ki *KeyIndex) _write(prefix string, data diskModels.KeyIndexRecords) error {
+	err := ki.keyIndexDiskManager.WriteData(ki.db, ki.blob, ki.key, prefix, data)
+	if err != nil {
+		delete(ki.cache, prefix)
+		return err
+	}
+	if ki.dataCaching {
+		ki.setCache(prefix, data)
+	}
+	return nil
+}
+
+func (ki *KeyIndex) setCache(prefix string, data diskModels.KeyIndexRecords) {
+	if ki.cache == nil {
+		ki.cache = make(map[string]diskModels.KeyIndexRecords)
+	}
+	ki.cache[prefix] = data
+}
+
+func groupByPrefix(pageRecordsMap PageRecordsMap, getPrefix func(pageRecordId string) string) map[string]PageRecordsMap {
+	shards := make(map[string]PageRecordsMap)
+	for pageFile, pageRecords := range pageRecordsMap {
+		for pageRecordId, pageRecord := range pageRecords {
+			prefix := getPrefix(pageRecordId)
+			if _, ok := shards[prefix]; !ok {
+				shards[prefix] = PageRecordsMap{}
+			}
+			if _, ok := shards[prefix][pageFile]; !ok {
+				shards[prefix][pageFile] = diskModels.PageRecords{}
+			}
+			shards[prefix][pageFile][pageRecordId] = pageRecord
+		}
+	}
+	return shards
+}
+
+func GetKeyIndexValue(value any, keyType string) (string, bool) {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Slice {
+		return "", false
+	}
+	switch keyType {
+	case memoryConstants.Int, memoryConstants.Float:
+		number, err := memoryUtils.ConvertToFloat64(value)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	case memoryConstants.DateTime:
+		parsed, err := memoryUtils.ConvertToTime(value)
+		if err != nil {
+			return "", false
+		}
+		if parsed.Nanosecond() == 0 {
+			return strconv.FormatInt(parsed.Unix(), 10), true
+		}
+		return fmt.Sprintf("%d.%09d", parsed.Unix(), parsed.Nanosecond()), true
+	case memoryConstants.Bool:
+		boolean, ok := value.(bool)
+		if !ok {
+			return "", false
+		}
+		return strconv.FormatBool(boolean), true
+	case memoryConstants.Decimal:
+		decimal, err := memoryUtils.ConvertToDecimal(value)
+		if err != nil {
+			return "", false
+		}
+		return decimal.RatString(), true
+	default:
+		valueString, ok := value.(string)
+		return valueString, ok
+	}
+}
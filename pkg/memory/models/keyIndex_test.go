@@ -0,0 +1,319 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func createTestKeyIndexMap(data map[string]diskModels.KeyIndexRecords) KeyIndexMapI {
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		keyIndexes := diskModels.KeyIndexes{}
+		for key := range data {
+			keyIndexes[key] = diskModels.KeyIndexItem{Key: key, IndexType: memoryConstants.KeyIndexHash}
+		}
+		return keyIndexes, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPrefixesFunc = func(db string, blob string, key string) ([]string, error) {
+		return []string{""}, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return ""
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetDataFunc = func(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error) {
+		return data[key], nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.WriteDataFunc = func(db string, blob string, key string, prefix string, keyIndexRecords diskModels.KeyIndexRecords) error {
+		data[key] = keyIndexRecords
+		return nil
+	}
+	keyIndexMap := NewKeyIndexMap("db", "blob", "dataLocation", diskModels.Format{
+		"name": {KeyType: memoryConstants.String},
+		"age":  {KeyType: memoryConstants.Int},
+	}, false)
+	_ = keyIndexMap.Initialize()
+	return keyIndexMap
+}
+
+func TestUnit_KeyIndexMap_Create_BuildsIndexFromRecords(t *testing.T) {
+	data := map[string]diskModels.KeyIndexRecords{}
+	createCalled := false
+	keyIndexMap := createTestKeyIndexMap(data)
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		createCalled = true
+		assert.Equal(t, "age", keyIndexItem.Key)
+		return nil
+	}
+
+	err := keyIndexMap.Create(diskModels.KeyIndexItem{Key: "age", IndexType: memoryConstants.KeyIndexHash}, PageRecordsMap{
+		"page1": {
+			"id1": {"name": "a", "age": float64(20)},
+			"id2": {"name": "b", "age": float64(30)},
+		},
+		"page2": {
+			"id3": {"name": "c", "age": float64(20)},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, createCalled)
+	assert.Equal(t, diskModels.KeyIndexRecords{
+		"20": {"id1": "page1", "id3": "page2"},
+		"30": {"id2": "page1"},
+	}, data["age"])
+	assert.Equal(t, diskModels.KeyIndexes{"age": {Key: "age", IndexType: memoryConstants.KeyIndexHash}}, keyIndexMap.GetAll())
+}
+
+func TestUnit_KeyIndexMap_Create_FailsOnExistingIndex(t *testing.T) {
+	keyIndexMap := createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{"age": {}})
+	createCalled := false
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		createCalled = true
+		return nil
+	}
+
+	err := keyIndexMap.Create(diskModels.KeyIndexItem{Key: "age", IndexType: memoryConstants.KeyIndexHash}, PageRecordsMap{})
+
+	assert.NotNil(t, err)
+	assert.False(t, createCalled)
+}
+
+func TestUnit_KeyIndexMap_Create_ReplacesUniqueHashIndex(t *testing.T) {
+	data := map[string]diskModels.KeyIndexRecords{"name": {}, "age": {}}
+	keyIndexMap := createTestKeyIndexMap(data)
+	keyIndexMap.(*KeyIndexMap).format["name"] = diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true}
+	deletedKeys := []string{}
+	diskManagers.MockKeyIndexManagerInstance.DeleteFunc = func(db string, blob string, key string) error {
+		deletedKeys = append(deletedKeys, key)
+		return nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		return nil
+	}
+
+	textErr := keyIndexMap.Create(diskModels.KeyIndexItem{Key: "name", IndexType: memoryConstants.KeyIndexText}, PageRecordsMap{
+		"page1": {"id1": {"name": "blue shirt", "age": float64(20)}},
+	})
+	ageErr := keyIndexMap.Create(diskModels.KeyIndexItem{Key: "age", IndexType: memoryConstants.KeyIndexOrdered}, PageRecordsMap{})
+
+	assert.Nil(t, textErr)
+	assert.NotNil(t, ageErr)
+	assert.Contains(t, ageErr.Error(), "index on key age already exists")
+	assert.Equal(t, []string{"name"}, deletedKeys)
+	assert.Equal(t, diskModels.KeyIndexes{
+		"name": {Key: "name", IndexType: memoryConstants.KeyIndexText},
+		"age":  {Key: "age", IndexType: memoryConstants.KeyIndexHash},
+	}, keyIndexMap.GetAll())
+}
+
+func TestUnit_KeyIndexMap_Delete_FailsOnMissingIndex(t *testing.T) {
+	keyIndexMap := createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{})
+
+	err := keyIndexMap.Delete("age")
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_KeyIndexMap_MaintainsIndexOnRecordChanges(t *testing.T) {
+	data := map[string]diskModels.KeyIndexRecords{"name": {}}
+	keyIndexMap := createTestKeyIndexMap(data)
+
+	err := keyIndexMap.AddRecords(PageRecordsMap{
+		"page1": {
+			"id1": {"name": "a"},
+			"id2": {"name": "b"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexRecords{
+		"a": {"id1": "page1"},
+		"b": {"id2": "page1"},
+	}, data["name"])
+
+	err = keyIndexMap.UpdateRecords(PageRecordsMap{
+		"page1": {"id1": {"name": "a"}},
+	}, diskModels.PageRecord{"name": "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexRecords{
+		"b": {"id1": "page1", "id2": "page1"},
+	}, data["name"])
+
+	err = keyIndexMap.DeleteRecords(PageRecordsMap{
+		"page1": {"id2": {"name": "b"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexRecords{
+		"b": {"id1": "page1"},
+	}, data["name"])
+}
+
+func TestUnit_KeyIndexMap_GetPageFiles_IntersectsIndexedFilters(t *testing.T) {
+	keyIndexMap := createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{
+		"name": {
+			"a": {"id1": "page1", "id3": "page3"},
+			"b": {"id2": "page2"},
+		},
+		"age": {
+			"20": {"id1": "page1", "id2": "page2"},
+		},
+	})
+
+	pageFiles, ok, err := keyIndexMap.GetPageFiles([]FilterItem{
+		{Key: "name", Op: memoryConstants.OpIn, Value: []any{"a", "b"}},
+		{Key: "age", Op: memoryConstants.OpEqual, Value: 20},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]bool{"page1": true, "page2": true}, pageFiles)
+}
+
+func TestUnit_KeyIndexMap_GetPageFiles_SkipsUnindexedFilters(t *testing.T) {
+	keyIndexMap := createTestKeyIndexMap(map[string]diskModels.KeyIndexRecords{
+		"name": {"a": {"id1": "page1"}},
+	})
+
+	_, ok, err := keyIndexMap.GetPageFiles([]FilterItem{
+		{Key: "age", Op: memoryConstants.OpEqual, Value: 20},
+		{Key: "name", Op: memoryConstants.OpGreater, Value: "a"},
+	})
+
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestUnit_GetKeyIndexValue_NormalizesValues(t *testing.T) {
+	tests := []struct {
+		value    any
+		keyType  string
+		expected string
+		ok       bool
+	}{
+		{float64(20), memoryConstants.Int, "20", true},
+		{20, memoryConstants.Int, "20", true},
+		{1.5, memoryConstants.Float, "1.5", true},
+		{"2023-01-02 03:04:05", memoryConstants.DateTime, "1672628645", true},
+		{1672628645, memoryConstants.DateTime, "1672628645", true},
+		{"2023-01-02T03:04:05Z", memoryConstants.DateTime, "1672628645", true},
+		{"2023-01-02T05:04:05+02:00", memoryConstants.DateTime, "1672628645", true},
+		{"2023-01-02T03:04:05.25Z", memoryConstants.DateTime, "1672628645.250000000", true},
+		{"bad", memoryConstants.DateTime, "", false},
+		{true, memoryConstants.Bool, "true", true},
+		{"12.50", memoryConstants.Decimal, "25/2", true},
+		{big.NewRat(25, 2), memoryConstants.Decimal, "25/2", true},
+		{"abc", memoryConstants.Decimal, "", false},
+		{"a", memoryConstants.String, "a", true},
+		{"2023-01-02", memoryConstants.Date, "2023-01-02", true},
+		{nil, memoryConstants.String, "", false},
+		{[]any{"a"}, memoryConstants.String, "", false},
+	}
+	for _, test := range tests {
+		value, ok := GetKeyIndexValue(test.value, test.keyType)
+		assert.Equal(t, test.expected, value)
+		assert.Equal(t, test.ok, ok)
+	}
+}
+
+func TestUnit_KeyIndexMap_TextIndexMatchesAllTerms(t *testing.T) {
+	data := map[string]diskModels.KeyIndexRecords{}
+	keyIndexMap := createTestKeyIndexMap(data)
+	diskManagers.MockKeyIndexManagerInstance.CreateFunc = func(db string, blob string, keyIndexItem diskModels.KeyIndexItem) error {
+		return nil
+	}
+
+	err := keyIndexMap.Create(diskModels.KeyIndexItem{Key: "name", IndexType: memoryConstants.KeyIndexText}, PageRecordsMap{
+		"page1": {"id1": {"name": "Quick foxes"}},
+		"page2": {"id2": {"name": "a fox jumped"}},
+		"page3": {"id3": {"name": "jumping dogs"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.IndexRecords{"id1": "page1", "id2": "page2"}, data["name"]["fox"])
+
+	pageFiles, ok, err := keyIndexMap.GetPageFiles([]FilterItem{
+		{Key: "name", Op: memoryConstants.OpMatch, Value: []string{"fox", "jump"}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]bool{"page2": true}, pageFiles)
+
+	err = keyIndexMap.UpdateRecords(PageRecordsMap{"page2": {"id2": {"name": "a fox jumped"}}}, diskModels.PageRecord{"name": "sleeping cat"})
+	assert.Nil(t, err)
+	_, ok = data["name"]["jump"]["id2"]
+	assert.False(t, ok)
+	assert.Equal(t, diskModels.IndexRecords{"id2": "page2"}, data["name"]["cat"])
+
+	err = keyIndexMap.DeleteRecords(PageRecordsMap{"page1": {"id1": {"name": "Quick foxes"}}})
+	assert.Nil(t, err)
+	_, ok = data["name"]["fox"]
+	assert.False(t, ok)
+}
+
+func createTestShardedKeyIndexMap(shards map[string]diskModels.KeyIndexRecords, writtenPrefixes *[]string) KeyIndexMapI {
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		return diskModels.KeyIndexes{"name": {Key: "name", IndexType: memoryConstants.KeyIndexHash}}, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPrefixesFunc = func(db string, blob string, key string) ([]string, error) {
+		prefixes := []string{}
+		for prefix := range shards {
+			prefixes = append(prefixes, prefix)
+		}
+		return prefixes, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return pageRecordId[:1]
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetDataFunc = func(db string, blob string, key string, prefix string) (diskModels.KeyIndexRecords, error) {
+		return shards[prefix], nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.WriteDataFunc = func(db string, blob string, key string, prefix string, keyIndexRecords diskModels.KeyIndexRecords) error {
+		*writtenPrefixes = append(*writtenPrefixes, prefix)
+		shards[prefix] = keyIndexRecords
+		return nil
+	}
+	keyIndexMap := NewKeyIndexMap("db", "blob", "dataLocation", diskModels.Format{
+		"name": {KeyType: memoryConstants.String},
+	}, false)
+	_ = keyIndexMap.Initialize()
+	return keyIndexMap
+}
+
+func TestUnit_KeyIndexMap_WritesOnlyTouchedShards(t *testing.T) {
+	writtenPrefixes := []string{}
+	shards := map[string]diskModels.KeyIndexRecords{
+		"a": {"x": {"a1": "page1"}},
+		"b": {"y": {"b1": "page1"}},
+	}
+	keyIndexMap := createTestShardedKeyIndexMap(shards, &writtenPrefixes)
+
+	err := keyIndexMap.AddRecords(PageRecordsMap{"page2": {"b2": {"name": "x"}}})
+	assert.Nil(t, err)
+	err = keyIndexMap.UpdateRecords(PageRecordsMap{"page1": {"b1": {"name": "x"}}}, diskModels.PageRecord{"name": "x"})
+	assert.Nil(t, err)
+	err = keyIndexMap.DeleteRecords(PageRecordsMap{"page2": {"b2": {"name": "x"}}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"b", "b", "b"}, writtenPrefixes)
+	assert.Equal(t, diskModels.KeyIndexRecords{"x": {"a1": "page1"}}, shards["a"])
+	assert.Equal(t, diskModels.KeyIndexRecords{"x": {"b1": "page1"}}, shards["b"])
+
+	pageFiles, ok, err := keyIndexMap.GetPageFiles([]FilterItem{{Key: "name", Op: memoryConstants.OpEqual, Value: "x"}})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]bool{"page1": true}, pageFiles)
+}
+
+func TestUnit_KeyIndex_Build_ClearsStaleShards(t *testing.T) {
+	writtenPrefixes := []string{}
+	shards := map[string]diskModels.KeyIndexRecords{
+		"a": {"x": {"a1": "page1"}},
+	}
+	keyIndexMap := createTestShardedKeyIndexMap(shards, &writtenPrefixes)
+
+	err := keyIndexMap.Truncate()
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.KeyIndexRecords{}, shards["a"])
+}
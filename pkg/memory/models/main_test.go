@@ -2,6 +2,7 @@ package memoryModels
 
 import (
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"os"
 	"testing"
 )
@@ -12,11 +13,19 @@ func TestMain(m *testing.M) {
 	diskManagers.CreateMockPartitionManager()
 	diskManagers.CreateMockFormatManager()
 	diskManagers.CreateMockPageManager()
+	diskManagers.CreateMockKeyIndexManager()
+	diskManagers.MockFormatManagerInstance.GetMigrationsFunc = func(db string, blob string) ([]string, error) {
+		return memoryConstants.GetMigrations(), nil
+	}
+	diskManagers.MockFormatManagerInstance.WriteMigrationsFunc = func(db string, blob string, migrations []string) error {
+		return nil
+	}
 	code := m.Run()
 	diskManagers.DestructBlobManager()
 	diskManagers.DestructFormatManager()
 	diskManagers.DestructPartitionManager()
 	diskManagers.DestructIndexManager()
 	diskManagers.DestructPageManager()
+	diskManagers.DestructKeyIndexManager()
 	os.Exit(code)
 }
@@ -0,0 +1,253 @@
+package memoryModels
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"slices"
+	"strings"
+	"time"
+)
+
+type SortItem struct {
+	Key       string `json:"key,required"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// SortItems also accepts the single sort key string used before multi-key sorting.
+type SortItems []SortItem
+
+type GetOperationParams struct {
+	Limit         int       `json:"limit"`
+	Offset        int       `json:"offset"`
+	Sort          SortItems `json:"sort"`
+	Fields        []string  `json:"fields"`
+	ExcludeFields []string  `json:"excludeFields"`
+	TimeZone      string    `json:"timeZone"`
+	matchItems    []FilterItem
+	location      *time.Location
+}
+
+type PageRecordItem struct {
+	PageFile     string
+	PageRecordId string
+	PageRecord   diskModels.PageRecord
+}
+
+type PageRecordItems []PageRecordItem
+
+func (prm PageRecordsMap) Count() int {
+	count := 0
+	for _, pageRecords := range prm {
+		count += len(pageRecords)
+	}
+	return count
+}
+
+func (prm PageRecordsMap) ToItems() PageRecordItems {
+	pageRecordItems := PageRecordItems{}
+	for pageFile, pageRecords := range prm {
+		for pageRecordId, pageRecord := range pageRecords {
+			pageRecordItems = append(pageRecordItems, PageRecordItem{
+				PageFile:     pageFile,
+				PageRecordId: pageRecordId,
+				PageRecord:   pageRecord,
+			})
+		}
+	}
+	slices.SortFunc(pageRecordItems, func(a PageRecordItem, b PageRecordItem) int {
+		if result := strings.Compare(a.PageFile, b.PageFile); result != 0 {
+			return result
+		}
+		return strings.Compare(a.PageRecordId, b.PageRecordId)
+	})
+	return pageRecordItems
+}
+
+func (si *SortItems) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*si = nil
+		if key != "" {
+			*si = SortItems{{Key: key}}
+		}
+		return nil
+	}
+	var sortItems []SortItem
+	if err := json.Unmarshal(data, &sortItems); err != nil {
+		return err
+	}
+	*si = sortItems
+	return nil
+}
+
+func (gop *GetOperationParams) Validate(format diskModels.Format) error {
+	if gop.Limit < 0 {
+		return errors.New(fmt.Sprintf("limit %d cannot be negative", gop.Limit))
+	}
+	if gop.Offset < 0 {
+		return errors.New(fmt.Sprintf("offset %d cannot be negative", gop.Offset))
+	}
+	for _, sortItem := range gop.Sort {
+		if _, ok := format[sortItem.Key]; !ok && sortItem.Key != memoryConstants.IdKey {
+			return errors.New(fmt.Sprintf("sort key %s does not exist in format", sortItem.Key))
+		}
+		switch sortItem.Direction {
+		case "", memoryConstants.SortAsc, memoryConstants.SortDesc:
+		default:
+			return errors.New(fmt.Sprintf("sort direction %s on key %s is not known", sortItem.Direction, sortItem.Key))
+		}
+	}
+	for _, field := range gop.Fields {
+		if _, ok := format[field]; !ok && field != memoryConstants.IdKey {
+			return errors.New(fmt.Sprintf("field %s does not exist in format", field))
+		}
+	}
+	for _, field := range gop.ExcludeFields {
+		if field == memoryConstants.IdKey {
+			return errors.New(fmt.Sprintf("field %s cannot be excluded", field))
+		}
+		if _, ok := format[field]; !ok {
+			return errors.New(fmt.Sprintf("field %s does not exist in format", field))
+		}
+	}
+	if gop.TimeZone != "" {
+		location, err := time.LoadLocation(gop.TimeZone)
+		if err != nil {
+			return errors.New(fmt.Sprintf("time zone %s is not known", gop.TimeZone))
+		}
+		gop.location = location
+	}
+	return nil
+}
+
+func (gop *GetOperationParams) HasProjection() bool {
+	return len(gop.Fields) > 0 || len(gop.ExcludeFields) > 0
+}
+
+func (gop *GetOperationParams) Project(pageRecord diskModels.PageRecord, format diskModels.Format) diskModels.PageRecord {
+	if !gop.HasProjection() && gop.location == nil {
+		return pageRecord
+	}
+	projectedRecord := diskModels.PageRecord{}
+	if len(gop.Fields) > 0 {
+		for _, field := range gop.Fields {
+			if value, ok := pageRecord[field]; ok {
+				projectedRecord[field] = value
+			}
+		}
+	} else {
+		for key, value := range pageRecord {
+			projectedRecord[key] = value
+		}
+	}
+	for _, field := range gop.ExcludeFields {
+		delete(projectedRecord, field)
+	}
+	if gop.location != nil {
+		for key, value := range projectedRecord {
+			if formatItem, ok := format[key]; ok {
+				projectedRecord[key] = localizeValue(value, formatItem, gop.location)
+			}
+		}
+	}
+	return projectedRecord
+}
+
+func (gop *GetOperationParams) HasSort() bool {
+	return len(gop.Sort) > 0 || len(gop.matchItems) > 0
+}
+
+func (gop *GetOperationParams) RankBy(filter Filter) {
+	gop.matchItems = filter.GetMatchItems()
+}
+
+func (gop *GetOperationParams) IsSatisfied(count int) bool {
+	return !gop.HasSort() && gop.Limit > 0 && count >= gop.Offset+gop.Limit
+}
+
+func (gop *GetOperationParams) Apply(pageRecordItems PageRecordItems, format diskModels.Format) PageRecordItems {
+	if len(gop.Sort) == 0 && len(gop.matchItems) > 0 {
+		scores := make(map[string]float64)
+		for _, pageRecordItem := range pageRecordItems {
+			for _, matchItem := range gop.matchItems {
+				value, _ := getRecordValue(pageRecordItem.PageRecord, matchItem.Key).(string)
+				scores[pageRecordItem.PageRecordId] += getMatchScore(matchItem.Value.([]string), value)
+			}
+		}
+		slices.SortStableFunc(pageRecordItems, func(a PageRecordItem, b PageRecordItem) int {
+			return cmp.Compare(scores[b.PageRecordId], scores[a.PageRecordId])
+		})
+	} else if gop.HasSort() {
+		slices.SortStableFunc(pageRecordItems, func(a PageRecordItem, b PageRecordItem) int {
+			for _, sortItem := range gop.Sort {
+				var result int
+				if sortItem.Key == memoryConstants.IdKey {
+					result = strings.Compare(a.PageRecordId, b.PageRecordId)
+				} else {
+					result = compareFormatValues(a.PageRecord[sortItem.Key], b.PageRecord[sortItem.Key], format[sortItem.Key].KeyType)
+				}
+				if sortItem.Direction == memoryConstants.SortDesc {
+					result = -result
+				}
+				if result != 0 {
+					return result
+				}
+			}
+			return 0
+		})
+	}
+	if gop.Offset >= len(pageRecordItems) {
+		return PageRecordItems{}
+	}
+	pageRecordItems = pageRecordItems[gop.Offset:]
+	if gop.Limit > 0 && gop.Limit < len(pageRecordItems) {
+		pageRecordItems = pageRecordItems[:gop.Limit]
+	}
+	for i := range pageRecordItems {
+		pageRecordItems[i].PageRecord = gop.Project(pageRecordItems[i].PageRecord, format)
+	}
+	return pageRecordItems
+}
+
+func localizeValue(value any, formatItem diskModels.FormatItem, location *time.Location) any {
+	if value == nil {
+		return nil
+	}
+	switch formatItem.KeyType {
+	case memoryConstants.DateTime:
+		if timeValue, err := memoryUtils.ConvertToTime(value); err == nil {
+			return memoryUtils.FormatDateTime(timeValue.In(location))
+		}
+	case memoryConstants.Object:
+		if objectValue, ok := value.(map[string]any); ok {
+			localizedObject := make(map[string]any, len(objectValue))
+			for key, nestedValue := range objectValue {
+				localizedObject[key] = nestedValue
+				if nestedItem, ok := formatItem.Format[key]; ok {
+					localizedObject[key] = localizeValue(nestedValue, nestedItem, location)
+				}
+			}
+			return localizedObject
+		}
+	}
+	elementType, ok := memoryConstants.GetArrayElementType(formatItem.KeyType)
+	if !ok {
+		return value
+	}
+	arrayValue, ok := value.([]any)
+	if !ok {
+		return value
+	}
+	elementItem := formatItem
+	elementItem.KeyType = elementType
+	localizedArray := make([]any, len(arrayValue))
+	for i, element := range arrayValue {
+		localizedArray[i] = localizeValue(element, elementItem, location)
+	}
+	return localizedArray
+}
@@ -0,0 +1,257 @@
+package memoryModels
+
+import (
+	"encoding/json"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func createTestPageRecordItems() PageRecordItems {
+	return PageRecordItems{
+		{PageFile: "page_1.json", PageRecordId: "a", PageRecord: diskModels.PageRecord{"name": "bob", "age": 30}},
+		{PageFile: "page_1.json", PageRecordId: "b", PageRecord: diskModels.PageRecord{"name": "alice", "age": 25}},
+		{PageFile: "page_2.json", PageRecordId: "c", PageRecord: diskModels.PageRecord{"name": "carl", "age": 30}},
+		{PageFile: "page_2.json", PageRecordId: "d", PageRecord: diskModels.PageRecord{"name": "dave", "age": 41}},
+	}
+}
+
+func createTestOperationFormat() diskModels.Format {
+	return diskModels.Format{
+		"name": diskModels.FormatItem{KeyType: memoryConstants.String},
+		"age":  diskModels.FormatItem{KeyType: memoryConstants.Int},
+	}
+}
+
+func TestUnit_ToItems_ConvertsPageRecordsMapInPageOrder(t *testing.T) {
+	pageRecordsMap := PageRecordsMap{
+		"page_2.json": {"c": diskModels.PageRecord{"name": "carl"}},
+		"page_1.json": {
+			"b": diskModels.PageRecord{"name": "alice"},
+			"a": diskModels.PageRecord{"name": "bob"},
+		},
+	}
+
+	pageRecordItems := pageRecordsMap.ToItems()
+
+	assert.Equal(t, 3, pageRecordsMap.Count())
+	assert.Equal(t, 3, len(pageRecordItems))
+	assert.Equal(t, "a", pageRecordItems[0].PageRecordId)
+	assert.Equal(t, "b", pageRecordItems[1].PageRecordId)
+	assert.Equal(t, "c", pageRecordItems[2].PageRecordId)
+	assert.Equal(t, "page_2.json", pageRecordItems[2].PageFile)
+}
+
+func TestUnit_Validate_FailsOnUnknownSortKey(t *testing.T) {
+	getOperationParams := GetOperationParams{Sort: []SortItem{{Key: "unknown"}}}
+
+	err := getOperationParams.Validate(createTestOperationFormat())
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Validate_FailsOnUnknownSortDirection(t *testing.T) {
+	getOperationParams := GetOperationParams{Sort: []SortItem{{Key: "age", Direction: "sideways"}}}
+
+	err := getOperationParams.Validate(createTestOperationFormat())
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Validate_FailsOnNegativeLimitOrOffset(t *testing.T) {
+	limitParams := GetOperationParams{Limit: -1}
+	offsetParams := GetOperationParams{Offset: -1}
+
+	assert.NotNil(t, limitParams.Validate(createTestOperationFormat()))
+	assert.NotNil(t, offsetParams.Validate(createTestOperationFormat()))
+}
+
+func TestUnit_Validate_AllowsIdSortKey(t *testing.T) {
+	getOperationParams := GetOperationParams{Sort: []SortItem{{Key: memoryConstants.IdKey, Direction: memoryConstants.SortDesc}}}
+
+	err := getOperationParams.Validate(createTestOperationFormat())
+
+	assert.Nil(t, err)
+}
+
+func TestUnit_Apply_SortsByMultipleKeys(t *testing.T) {
+	getOperationParams := GetOperationParams{Sort: []SortItem{
+		{Key: "age", Direction: memoryConstants.SortDesc},
+		{Key: "name", Direction: memoryConstants.SortAsc},
+	}}
+
+	pageRecordItems := getOperationParams.Apply(createTestPageRecordItems(), createTestOperationFormat())
+
+	assert.Equal(t, 4, len(pageRecordItems))
+	assert.Equal(t, "d", pageRecordItems[0].PageRecordId)
+	assert.Equal(t, "a", pageRecordItems[1].PageRecordId)
+	assert.Equal(t, "c", pageRecordItems[2].PageRecordId)
+	assert.Equal(t, "b", pageRecordItems[3].PageRecordId)
+}
+
+func TestUnit_Apply_SlicesByLimitAndOffset(t *testing.T) {
+	getOperationParams := GetOperationParams{
+		Limit:  2,
+		Offset: 1,
+		Sort:   []SortItem{{Key: "name"}},
+	}
+
+	pageRecordItems := getOperationParams.Apply(createTestPageRecordItems(), createTestOperationFormat())
+
+	assert.Equal(t, 2, len(pageRecordItems))
+	assert.Equal(t, "a", pageRecordItems[0].PageRecordId)
+	assert.Equal(t, "c", pageRecordItems[1].PageRecordId)
+}
+
+func TestUnit_Apply_ReturnsEmptyOnOffsetOutOfRange(t *testing.T) {
+	getOperationParams := GetOperationParams{Offset: 10}
+
+	pageRecordItems := getOperationParams.Apply(createTestPageRecordItems(), createTestOperationFormat())
+
+	assert.Equal(t, 0, len(pageRecordItems))
+}
+
+func TestUnit_IsSatisfied_OnlyWithoutSort(t *testing.T) {
+	unsorted := GetOperationParams{Limit: 2, Offset: 1}
+	sorted := GetOperationParams{Limit: 2, Sort: []SortItem{{Key: "age"}}}
+
+	assert.False(t, unsorted.IsSatisfied(2))
+	assert.True(t, unsorted.IsSatisfied(3))
+	assert.False(t, sorted.IsSatisfied(10))
+}
+
+func TestUnit_Validate_FailsOnUnknownField(t *testing.T) {
+	fieldParams := GetOperationParams{Fields: []string{"unknown"}}
+	excludeParams := GetOperationParams{ExcludeFields: []string{"unknown"}}
+	excludeIdParams := GetOperationParams{ExcludeFields: []string{memoryConstants.IdKey}}
+
+	assert.NotNil(t, fieldParams.Validate(createTestOperationFormat()))
+	assert.NotNil(t, excludeParams.Validate(createTestOperationFormat()))
+	assert.NotNil(t, excludeIdParams.Validate(createTestOperationFormat()))
+}
+
+func TestUnit_Project_KeepsSelectedFields(t *testing.T) {
+	getOperationParams := GetOperationParams{Fields: []string{"name"}}
+
+	pageRecord := getOperationParams.Project(diskModels.PageRecord{"name": "bob", "age": 30}, createTestOperationFormat())
+
+	assert.Equal(t, diskModels.PageRecord{"name": "bob"}, pageRecord)
+}
+
+func TestUnit_Project_RemovesExcludedFields(t *testing.T) {
+	getOperationParams := GetOperationParams{ExcludeFields: []string{"name"}}
+
+	pageRecord := getOperationParams.Project(diskModels.PageRecord{"name": "bob", "age": 30}, createTestOperationFormat())
+
+	assert.Equal(t, diskModels.PageRecord{"age": 30}, pageRecord)
+}
+
+func TestUnit_Project_RendersDateTimesInTimeZone(t *testing.T) {
+	format := diskModels.Format{
+		"created": diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+		"audit": diskModels.FormatItem{KeyType: memoryConstants.Object, Format: diskModels.Format{
+			"seen": diskModels.FormatItem{KeyType: memoryConstants.GetArrayType(memoryConstants.DateTime)},
+		}},
+		"day": diskModels.FormatItem{KeyType: memoryConstants.Date},
+	}
+	getOperationParams := GetOperationParams{TimeZone: "America/New_York"}
+	assert.Nil(t, getOperationParams.Validate(format))
+	pageRecord := diskModels.PageRecord{
+		"created": "2024-03-15T10:30:00.5Z",
+		"audit":   map[string]any{"seen": []any{"2024-01-15 10:30:00"}},
+		"day":     "2024-03-15",
+	}
+
+	projectedRecord := getOperationParams.Project(pageRecord, format)
+
+	assert.Equal(t, diskModels.PageRecord{
+		"created": "2024-03-15T06:30:00.5-04:00",
+		"audit":   map[string]any{"seen": []any{"2024-01-15T05:30:00-05:00"}},
+		"day":     "2024-03-15",
+	}, projectedRecord)
+	assert.Equal(t, "2024-03-15T10:30:00.5Z", pageRecord["created"])
+}
+
+func TestUnit_Validate_FailsOnUnknownTimeZone(t *testing.T) {
+	getOperationParams := GetOperationParams{TimeZone: "Mars/Olympus"}
+
+	err := getOperationParams.Validate(createTestOperationFormat())
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Mars/Olympus")
+}
+
+func TestUnit_Apply_ProjectsAfterSorting(t *testing.T) {
+	getOperationParams := GetOperationParams{
+		Limit:  1,
+		Sort:   []SortItem{{Key: "age", Direction: memoryConstants.SortDesc}},
+		Fields: []string{"name"},
+	}
+
+	pageRecordItems := getOperationParams.Apply(createTestPageRecordItems(), createTestOperationFormat())
+
+	assert.Equal(t, 1, len(pageRecordItems))
+	assert.Equal(t, diskModels.PageRecord{"name": "dave"}, pageRecordItems[0].PageRecord)
+}
+
+func TestUnit_Apply_RanksByMatchScoreWithoutSort(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Key: "name", Op: memoryConstants.OpMatch, Value: "fox"}},
+		Format:      createTestOperationFormat(),
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+	getOperationParams := GetOperationParams{Limit: 2}
+	getOperationParams.RankBy(filter)
+
+	pageRecordItems := getOperationParams.Apply(PageRecordItems{
+		{PageFile: "page_1.json", PageRecordId: "a", PageRecord: diskModels.PageRecord{"name": "a fox in a very long sentence"}},
+		{PageFile: "page_1.json", PageRecordId: "b", PageRecord: diskModels.PageRecord{"name": "fox"}},
+		{PageFile: "page_2.json", PageRecordId: "c", PageRecord: diskModels.PageRecord{"name": "fox and foxes"}},
+	}, createTestOperationFormat())
+
+	assert.True(t, getOperationParams.HasSort())
+	assert.Equal(t, 2, len(pageRecordItems))
+	assert.Equal(t, "b", pageRecordItems[0].PageRecordId)
+	assert.Equal(t, "c", pageRecordItems[1].PageRecordId)
+}
+
+func TestUnit_Apply_RanksByMatchScoreNestedInGroups(t *testing.T) {
+	filter := Filter{
+		FilterItems: []FilterItem{{Op: memoryConstants.FilterAnd, Items: []FilterItem{
+			{Key: "name", Op: memoryConstants.OpMatch, Value: "fox"},
+			{Op: memoryConstants.FilterNot, Items: []FilterItem{
+				{Key: "name", Op: memoryConstants.OpMatch, Value: "sentence"},
+			}},
+		}}},
+		Format: createTestOperationFormat(),
+	}
+	assert.Nil(t, filter.ConvertFilterItems())
+	getOperationParams := GetOperationParams{Limit: 2}
+	getOperationParams.RankBy(filter)
+
+	pageRecordItems := getOperationParams.Apply(PageRecordItems{
+		{PageFile: "page_1.json", PageRecordId: "a", PageRecord: diskModels.PageRecord{"name": "a fox in a very long sentence"}},
+		{PageFile: "page_1.json", PageRecordId: "b", PageRecord: diskModels.PageRecord{"name": "fox"}},
+		{PageFile: "page_2.json", PageRecordId: "c", PageRecord: diskModels.PageRecord{"name": "fox and foxes"}},
+	}, createTestOperationFormat())
+
+	assert.True(t, getOperationParams.HasSort())
+	assert.Equal(t, 2, len(pageRecordItems))
+	assert.Equal(t, "b", pageRecordItems[0].PageRecordId)
+	assert.Equal(t, "c", pageRecordItems[1].PageRecordId)
+}
+
+func TestUnit_GetOperationParams_UnmarshalsLegacySortKey(t *testing.T) {
+	var legacy, current, empty GetOperationParams
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"limit":5,"sort":"qty"}`), &legacy))
+	assert.Nil(t, json.Unmarshal([]byte(`{"sort":[{"key":"qty","direction":"desc"}]}`), &current))
+	assert.Nil(t, json.Unmarshal([]byte(`{"sort":""}`), &empty))
+
+	assert.Equal(t, SortItems{{Key: "qty"}}, legacy.Sort)
+	assert.Equal(t, 5, legacy.Limit)
+	assert.Equal(t, SortItems{{Key: "qty", Direction: memoryConstants.SortDesc}}, current.Sort)
+	assert.Nil(t, empty.Sort)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"sort":7}`), &empty))
+}
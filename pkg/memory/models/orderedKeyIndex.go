@@ -0,0 +1,381 @@
+package memoryModels
+
+import (
+	"cmp"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxExactOrderedValue = 1 << 53
+
+type OrderedKeyIndex struct {
+	m                   *sync.Mutex
+	key                 string
+	keyType             string
+	db                  string
+	blob                string
+	keyIndexDiskManager diskManagers.KeyIndexManager
+	cache               map[string]diskModels.OrderedIndex
+	dataCaching         bool
+}
+
+func (oki *OrderedKeyIndex) GetIndexType() string {
+	return memoryConstants.KeyIndexOrdered
+}
+
+func (oki *OrderedKeyIndex) Build(pageRecordsMap PageRecordsMap) error {
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	prefixes, err := oki.keyIndexDiskManager.GetPrefixes(oki.db, oki.blob, oki.key)
+	if err != nil {
+		return err
+	}
+	shards := groupByPrefix(pageRecordsMap, oki.keyIndexDiskManager.GetPageRecordIdPrefix)
+	for _, prefix := range prefixes {
+		if _, ok := shards[prefix]; !ok {
+			shards[prefix] = PageRecordsMap{}
+		}
+	}
+	for prefix, shardRecordsMap := range shards {
+		if err = oki.add(prefix, diskModels.OrderedIndex{}, shardRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) Add(pageRecordsMap PageRecordsMap) error {
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, oki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		orderedIndex, err := oki._read(prefix)
+		if err != nil {
+			return err
+		}
+		if err = oki.add(prefix, orderedIndex, shardRecordsMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) Replace(pageRecordsMap PageRecordsMap, value any) error {
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, oki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		orderedIndex, err := oki._read(prefix)
+		if err != nil {
+			return err
+		}
+		oki.removeItems(&orderedIndex, shardRecordsMap)
+		for pageFile, pageRecords := range shardRecordsMap {
+			for pageRecordId := range pageRecords {
+				oki.addItem(&orderedIndex, value, pageRecordId, pageFile)
+			}
+		}
+		oki.sortEntries(&orderedIndex)
+		if err = oki._write(prefix, orderedIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) Remove(pageRecordsMap PageRecordsMap) error {
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	for prefix, shardRecordsMap := range groupByPrefix(pageRecordsMap, oki.keyIndexDiskManager.GetPageRecordIdPrefix) {
+		orderedIndex, err := oki._read(prefix)
+		if err != nil {
+			return err
+		}
+		oki.removeItems(&orderedIndex, shardRecordsMap)
+		if err = oki._write(prefix, orderedIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) Lookup(filterItem FilterItem) (diskModels.IndexRecords, bool, error) {
+	if filterItem.Op == memoryConstants.OpIsNull {
+		oki.m.Lock()
+		defer oki.m.Unlock()
+		indexRecords := diskModels.IndexRecords{}
+		err := oki.readShards(func(orderedIndex diskModels.OrderedIndex) {
+			for pageRecordId, pageFile := range orderedIndex.Nulls {
+				indexRecords[pageRecordId] = pageFile
+			}
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		return indexRecords, true, nil
+	}
+	var lowerValues, upperValues []any
+	lowerInclusive, upperInclusive := true, true
+	switch filterItem.Op {
+	case memoryConstants.OpEqual:
+		lowerValues, upperValues = []any{filterItem.Value}, []any{filterItem.Value}
+	case memoryConstants.OpIn:
+		inValues, ok := filterItem.Value.([]any)
+		if !ok {
+			return nil, false, nil
+		}
+		lowerValues, upperValues = inValues, inValues
+	case memoryConstants.OpBetween:
+		bounds, ok := filterItem.Value.([]any)
+		if !ok || len(bounds) != 2 {
+			return nil, false, nil
+		}
+		lowerValues, upperValues = bounds[:1], bounds[1:]
+	case memoryConstants.OpGreater, memoryConstants.OpGreaterEqual:
+		lowerValues, upperValues = []any{filterItem.Value}, []any{nil}
+		lowerInclusive = filterItem.Op == memoryConstants.OpGreaterEqual
+	case memoryConstants.OpLess, memoryConstants.OpLessEqual:
+		lowerValues, upperValues = []any{nil}, []any{filterItem.Value}
+		upperInclusive = filterItem.Op == memoryConstants.OpLessEqual
+	default:
+		return nil, false, nil
+	}
+	lowers := make([]float64, len(lowerValues))
+	uppers := make([]float64, len(upperValues))
+	for i := range lowerValues {
+		var ok bool
+		if lowers[i], ok = oki.getBound(lowerValues[i], math.Inf(-1)); !ok {
+			return nil, false, nil
+		}
+		if uppers[i], ok = oki.getBound(upperValues[i], math.Inf(1)); !ok {
+			return nil, false, nil
+		}
+		lowerInclusive = lowerInclusive || !isExactOrderedValue(lowers[i])
+		upperInclusive = upperInclusive || !isExactOrderedValue(uppers[i])
+	}
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	indexRecords := diskModels.IndexRecords{}
+	err := oki.readShards(func(orderedIndex diskModels.OrderedIndex) {
+		for i := range lowers {
+			start := sort.Search(len(orderedIndex.Entries), func(j int) bool {
+				if lowerInclusive {
+					return orderedIndex.Entries[j].Value >= lowers[i]
+				}
+				return orderedIndex.Entries[j].Value > lowers[i]
+			})
+			for _, entry := range orderedIndex.Entries[start:] {
+				if entry.Value > uppers[i] || (!upperInclusive && entry.Value == uppers[i]) {
+					break
+				}
+				indexRecords[entry.PageRecordId] = entry.PageFile
+			}
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return indexRecords, true, nil
+}
+
+func (oki *OrderedKeyIndex) Walk(direction string, walkFunc func(entry diskModels.OrderedIndexEntry, isNull bool) bool) error {
+	oki.m.Lock()
+	defer oki.m.Unlock()
+	nullEntries := []diskModels.OrderedIndexEntry{}
+	shardEntries := [][]diskModels.OrderedIndexEntry{}
+	err := oki.readShards(func(orderedIndex diskModels.OrderedIndex) {
+		for pageRecordId, pageFile := range orderedIndex.Nulls {
+			nullEntries = append(nullEntries, diskModels.OrderedIndexEntry{PageRecordId: pageRecordId, PageFile: pageFile})
+		}
+		shardEntries = append(shardEntries, orderedIndex.Entries)
+	})
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(nullEntries, func(a diskModels.OrderedIndexEntry, b diskModels.OrderedIndexEntry) int {
+		return strings.Compare(a.PageRecordId, b.PageRecordId)
+	})
+	entries := mergeOrderedEntries(shardEntries)
+	if direction == memoryConstants.SortDesc {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !walkFunc(entries[i], false) {
+				return nil
+			}
+		}
+	}
+	for _, entry := range nullEntries {
+		if !walkFunc(entry, true) {
+			return nil
+		}
+	}
+	if direction != memoryConstants.SortDesc {
+		for _, entry := range entries {
+			if !walkFunc(entry, false) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) getBound(value any, openBound float64) (float64, bool) {
+	if value == nil {
+		return openBound, true
+	}
+	return GetOrderedKeyIndexValue(value, oki.keyType)
+}
+
+func (oki *OrderedKeyIndex) add(prefix string, orderedIndex diskModels.OrderedIndex, pageRecordsMap PageRecordsMap) error {
+	for pageFile, pageRecords := range pageRecordsMap {
+		for pageRecordId, pageRecord := range pageRecords {
+			oki.addItem(&orderedIndex, pageRecord[oki.key], pageRecordId, pageFile)
+		}
+	}
+	oki.sortEntries(&orderedIndex)
+	return oki._write(prefix, orderedIndex)
+}
+
+func (oki *OrderedKeyIndex) addItem(orderedIndex *diskModels.OrderedIndex, value any, pageRecordId string, pageFile string) {
+	orderedValue, ok := GetOrderedKeyIndexValue(value, oki.keyType)
+	if !ok {
+		if orderedIndex.Nulls == nil {
+			orderedIndex.Nulls = diskModels.IndexRecords{}
+		}
+		orderedIndex.Nulls[pageRecordId] = pageFile
+		return
+	}
+	orderedIndex.Entries = append(orderedIndex.Entries, diskModels.OrderedIndexEntry{
+		Value:        orderedValue,
+		PageRecordId: pageRecordId,
+		PageFile:     pageFile,
+	})
+}
+
+func (oki *OrderedKeyIndex) removeItems(orderedIndex *diskModels.OrderedIndex, pageRecordsMap PageRecordsMap) {
+	pageRecordIds := make(map[string]bool)
+	for _, pageRecords := range pageRecordsMap {
+		for pageRecordId := range pageRecords {
+			pageRecordIds[pageRecordId] = true
+			delete(orderedIndex.Nulls, pageRecordId)
+		}
+	}
+	orderedIndex.Entries = slices.DeleteFunc(orderedIndex.Entries, func(entry diskModels.OrderedIndexEntry) bool {
+		return pageRecordIds[entry.PageRecordId]
+	})
+}
+
+func (oki *OrderedKeyIndex) sortEntries(orderedIndex *diskModels.OrderedIndex) {
+	slices.SortFunc(orderedIndex.Entries, compareOrderedEntries)
+}
+
+func (oki *OrderedKeyIndex) readShards(readFunc func(orderedIndex diskModels.OrderedIndex)) error {
+	prefixes, err := oki.keyIndexDiskManager.GetPrefixes(oki.db, oki.blob, oki.key)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range prefixes {
+		orderedIndex, err := oki._read(prefix)
+		if err != nil {
+			return err
+		}
+		readFunc(orderedIndex)
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) _read(prefix string) (diskModels.OrderedIndex, error) {
+	if cached, ok := oki.cache[prefix]; oki.dataCaching && ok {
+		return cached, nil
+	}
+	data, err := oki.keyIndexDiskManager.GetOrderedData(oki.db, oki.blob, oki.key, prefix)
+	if err != nil {
+		return diskModels.OrderedIndex{}, err
+	}
+	if oki.dataCaching {
+		oki.setCache(prefix, data)
+	}
+	return data, nil
+}
+
+func (oki *OrderedKeyIndex) _write(prefix string, data diskModels.OrderedIndex) error {
+	err := oki.keyIndexDiskManager.WriteOrderedData(oki.db, oki.blob, oki.key, prefix, data)
+	if err != nil {
+		delete(oki.cache, prefix)
+		return err
+	}
+	if oki.dataCaching {
+		oki.setCache(prefix, data)
+	}
+	return nil
+}
+
+func (oki *OrderedKeyIndex) setCache(prefix string, data diskModels.OrderedIndex) {
+	if oki.cache == nil {
+		oki.cache = make(map[string]diskModels.OrderedIndex)
+	}
+	oki.cache[prefix] = data
+}
+
+func compareOrderedEntries(a diskModels.OrderedIndexEntry, b diskModels.OrderedIndexEntry) int {
+	if result := cmp.Compare(a.Value, b.Value); result != 0 {
+		return result
+	}
+	return strings.Compare(a.PageRecordId, b.PageRecordId)
+}
+
+func mergeOrderedEntries(shardEntries [][]diskModels.OrderedIndexEntry) []diskModels.OrderedIndexEntry {
+	total := 0
+	for _, entries := range shardEntries {
+		total += len(entries)
+	}
+	merged := make([]diskModels.OrderedIndexEntry, 0, total)
+	positions := make([]int, len(shardEntries))
+	for len(merged) < total {
+		next := -1
+		for i, entries := range shardEntries {
+			if positions[i] >= len(entries) {
+				continue
+			}
+			if next == -1 || compareOrderedEntries(entries[positions[i]], shardEntries[next][positions[next]]) < 0 {
+				next = i
+			}
+		}
+		merged = append(merged, shardEntries[next][positions[next]])
+		positions[next]++
+	}
+	return merged
+}
+
+func isExactOrderedValue(value float64) bool {
+	return math.IsInf(value, 0) || math.Abs(value) < maxExactOrderedValue
+}
+
+func GetOrderedKeyIndexValue(value any, keyType string) (float64, bool) {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Slice {
+		return 0, false
+	}
+	switch keyType {
+	case memoryConstants.Int, memoryConstants.Float:
+		number, err := memoryUtils.ConvertToFloat64(value)
+		return number, err == nil
+	case memoryConstants.DateTime:
+		parsed, err := memoryUtils.ConvertToTime(value)
+		return memoryUtils.ConvertToUnixSeconds(parsed), err == nil
+	case memoryConstants.Date:
+		dateString, ok := value.(string)
+		if !ok {
+			return 0, false
+		}
+		parsed, err := time.Parse(time.DateOnly, dateString)
+		return float64(parsed.Unix()), err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,232 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func createTestOrderedKeyIndexMap(data map[string]diskModels.OrderedIndex) KeyIndexMapI {
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		return diskModels.KeyIndexes{"age": {Key: "age", IndexType: memoryConstants.KeyIndexOrdered}}, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPrefixesFunc = func(db string, blob string, key string) ([]string, error) {
+		return []string{""}, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetPageRecordIdPrefixFunc = func(pageRecordId string) string {
+		return ""
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetOrderedDataFunc = func(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error) {
+		return data[key], nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.WriteOrderedDataFunc = func(db string, blob string, key string, prefix string, orderedIndex diskModels.OrderedIndex) error {
+		data[key] = orderedIndex
+		return nil
+	}
+	keyIndexMap := NewKeyIndexMap("db", "blob", "dataLocation", diskModels.Format{
+		"age": {KeyType: memoryConstants.Int},
+	}, false)
+	_ = keyIndexMap.Initialize()
+	return keyIndexMap
+}
+
+func createTestOrderedKeyIndex(data map[string]diskModels.OrderedIndex) *OrderedKeyIndex {
+	orderedKeyIndex, _ := createTestOrderedKeyIndexMap(data).GetOrdered("age")
+	return orderedKeyIndex
+}
+
+func TestUnit_OrderedKeyIndex_Build_SortsEntries(t *testing.T) {
+	data := map[string]diskModels.OrderedIndex{}
+	orderedKeyIndex := createTestOrderedKeyIndex(data)
+
+	err := orderedKeyIndex.Build(PageRecordsMap{
+		"page1": {
+			"id1": {"age": float64(30)},
+			"id2": {"age": float64(10)},
+			"id3": {"age": nil},
+		},
+		"page2": {
+			"id4": {"age": float64(10)},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []diskModels.OrderedIndexEntry{
+		{Value: 10, PageRecordId: "id2", PageFile: "page1"},
+		{Value: 10, PageRecordId: "id4", PageFile: "page2"},
+		{Value: 30, PageRecordId: "id1", PageFile: "page1"},
+	}, data["age"].Entries)
+	assert.Equal(t, diskModels.IndexRecords{"id3": "page1"}, data["age"].Nulls)
+}
+
+func TestUnit_OrderedKeyIndex_Lookup_FindsRanges(t *testing.T) {
+	orderedKeyIndex := createTestOrderedKeyIndex(map[string]diskModels.OrderedIndex{
+		"age": {Entries: []diskModels.OrderedIndexEntry{
+			{Value: 10, PageRecordId: "id1", PageFile: "page1"},
+			{Value: 20, PageRecordId: "id2", PageFile: "page1"},
+			{Value: 30, PageRecordId: "id3", PageFile: "page2"},
+			{Value: 40, PageRecordId: "id4", PageFile: "page3"},
+		}, Nulls: diskModels.IndexRecords{"id5": "page3"}},
+	})
+	tests := []struct {
+		filterItem FilterItem
+		expected   diskModels.IndexRecords
+	}{
+		{FilterItem{Key: "age", Op: memoryConstants.OpEqual, Value: 20}, diskModels.IndexRecords{"id2": "page1"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpGreater, Value: 20}, diskModels.IndexRecords{"id3": "page2", "id4": "page3"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpGreaterEqual, Value: 30}, diskModels.IndexRecords{"id3": "page2", "id4": "page3"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpLess, Value: 20}, diskModels.IndexRecords{"id1": "page1"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpLessEqual, Value: 20}, diskModels.IndexRecords{"id1": "page1", "id2": "page1"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpBetween, Value: []any{15, 30}}, diskModels.IndexRecords{"id2": "page1", "id3": "page2"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpIn, Value: []any{10, 40, 50}}, diskModels.IndexRecords{"id1": "page1", "id4": "page3"}},
+		{FilterItem{Key: "age", Op: memoryConstants.OpIsNull}, diskModels.IndexRecords{"id5": "page3"}},
+	}
+	for _, test := range tests {
+		indexRecords, ok, err := orderedKeyIndex.Lookup(test.filterItem)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, indexRecords, test.filterItem.Op)
+	}
+
+	_, ok, err := orderedKeyIndex.Lookup(FilterItem{Key: "age", Op: memoryConstants.OpNotEqual, Value: 20})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestUnit_OrderedKeyIndex_Lookup_IncludesBoundsBeyondFloatPrecision(t *testing.T) {
+	data := map[string]diskModels.OrderedIndex{}
+	orderedKeyIndex := createTestOrderedKeyIndex(data)
+	err := orderedKeyIndex.Build(PageRecordsMap{
+		"page1": {"id1": {"age": int64(9007199254740992)}},
+		"page2": {"id2": {"age": int64(9007199254740993)}},
+	})
+	assert.Nil(t, err)
+
+	indexRecords, ok, err := orderedKeyIndex.Lookup(FilterItem{Key: "age", Op: memoryConstants.OpGreater, Value: int64(9007199254740992)})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "page2", indexRecords["id2"])
+
+	indexRecords, ok, err = orderedKeyIndex.Lookup(FilterItem{Key: "age", Op: memoryConstants.OpLess, Value: int64(9007199254740993)})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "page1", indexRecords["id1"])
+}
+
+func TestUnit_OrderedKeyIndex_Lookup_KeepsSubSecondDateTimeBounds(t *testing.T) {
+	format := diskModels.Format{"created": {KeyType: memoryConstants.DateTime}}
+	diskManagers.MockKeyIndexManagerInstance.GetAllFunc = func(db string, blob string) (diskModels.KeyIndexes, error) {
+		return diskModels.KeyIndexes{"created": {Key: "created", IndexType: memoryConstants.KeyIndexOrdered}}, nil
+	}
+	diskManagers.MockKeyIndexManagerInstance.GetOrderedDataFunc = func(db string, blob string, key string, prefix string) (diskModels.OrderedIndex, error) {
+		return diskModels.OrderedIndex{Entries: []diskModels.OrderedIndexEntry{
+			{Value: 1672660800, PageRecordId: "id1", PageFile: "page1"},
+			{Value: 1672660801, PageRecordId: "id2", PageFile: "page2"},
+		}}, nil
+	}
+	keyIndexMap := NewKeyIndexMap("db", "blob", "dataLocation", format, false)
+	_ = keyIndexMap.Initialize()
+	orderedKeyIndex, _ := keyIndexMap.GetOrdered("created")
+	tests := []struct {
+		op       string
+		expected diskModels.IndexRecords
+	}{
+		{memoryConstants.OpLess, diskModels.IndexRecords{"id1": "page1"}},
+		{memoryConstants.OpGreater, diskModels.IndexRecords{"id2": "page2"}},
+		{memoryConstants.OpEqual, diskModels.IndexRecords{}},
+	}
+	for _, test := range tests {
+		filter := Filter{FilterItems: []FilterItem{{Key: "created", Op: test.op, Value: "2023-01-02T12:00:00.5Z"}}, Format: format}
+		assert.Nil(t, filter.ConvertFilterItems())
+
+		indexRecords, ok, err := orderedKeyIndex.Lookup(filter.FilterItems[0])
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, indexRecords, test.op)
+		passes, err := filter.Passes(diskModels.PageRecord{"created": "2023-01-02T12:00:00Z"})
+		assert.Nil(t, err)
+		assert.Equal(t, test.op == memoryConstants.OpLess, passes, test.op)
+	}
+}
+
+func TestUnit_OrderedKeyIndex_MaintainsIndexOnRecordChanges(t *testing.T) {
+	data := map[string]diskModels.OrderedIndex{"age": {}}
+	orderedKeyIndex := createTestOrderedKeyIndex(data)
+
+	err := orderedKeyIndex.Add(PageRecordsMap{
+		"page1": {
+			"id1": {"age": 20},
+			"id2": {"age": 10},
+		},
+	})
+	assert.Nil(t, err)
+
+	err = orderedKeyIndex.Replace(PageRecordsMap{"page1": {"id2": {"age": 10}}}, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, []diskModels.OrderedIndexEntry{
+		{Value: 20, PageRecordId: "id1", PageFile: "page1"},
+		{Value: 30, PageRecordId: "id2", PageFile: "page1"},
+	}, data["age"].Entries)
+
+	err = orderedKeyIndex.Remove(PageRecordsMap{"page1": {"id1": {"age": 20}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []diskModels.OrderedIndexEntry{
+		{Value: 30, PageRecordId: "id2", PageFile: "page1"},
+	}, data["age"].Entries)
+}
+
+func TestUnit_OrderedKeyIndex_Walk_WalksInSortOrder(t *testing.T) {
+	orderedKeyIndex := createTestOrderedKeyIndex(map[string]diskModels.OrderedIndex{
+		"age": {
+			Entries: []diskModels.OrderedIndexEntry{
+				{Value: 10, PageRecordId: "id1", PageFile: "page1"},
+				{Value: 20, PageRecordId: "id2", PageFile: "page1"},
+			},
+			Nulls: diskModels.IndexRecords{"id3": "page2"},
+		},
+	})
+	walk := func(direction string, limit int) []string {
+		pageRecordIds := []string{}
+		err := orderedKeyIndex.Walk(direction, func(entry diskModels.OrderedIndexEntry, isNull bool) bool {
+			pageRecordIds = append(pageRecordIds, entry.PageRecordId)
+			return len(pageRecordIds) < limit
+		})
+		assert.Nil(t, err)
+		return pageRecordIds
+	}
+
+	assert.Equal(t, []string{"id3", "id1", "id2"}, walk(memoryConstants.SortAsc, 3))
+	assert.Equal(t, []string{"id2", "id1", "id3"}, walk(memoryConstants.SortDesc, 3))
+	assert.Equal(t, []string{"id2"}, walk(memoryConstants.SortDesc, 1))
+}
+
+func TestUnit_GetOrderedKeyIndexValue_ConvertsValues(t *testing.T) {
+	tests := []struct {
+		value    any
+		keyType  string
+		expected float64
+		ok       bool
+	}{
+		{20, memoryConstants.Int, 20, true},
+		{1.5, memoryConstants.Float, 1.5, true},
+		{"2023-01-02 03:04:05", memoryConstants.DateTime, 1672628645, true},
+		{int64(1672628645), memoryConstants.DateTime, 1672628645, true},
+		{"2023-01-02T03:04:05Z", memoryConstants.DateTime, 1672628645, true},
+		{"2023-01-02T03:04:05.5Z", memoryConstants.DateTime, 1672628645.5, true},
+		{1672628645.5, memoryConstants.DateTime, 1672628645.5, true},
+		{"2023-01-02", memoryConstants.Date, 1672617600, true},
+		{"bad", memoryConstants.Date, 0, false},
+		{nil, memoryConstants.Int, 0, false},
+		{"a", memoryConstants.String, 0, false},
+	}
+	for _, test := range tests {
+		value, ok := GetOrderedKeyIndexValue(test.value, test.keyType)
+		assert.Equal(t, test.ok, ok)
+		if test.ok {
+			assert.Equal(t, test.expected, value)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -14,12 +16,17 @@ type PageMapI interface {
 	Add() (*Page, error)
 	Delete(fileName string) (bool, error)
 	GetCurrentPage() (*Page, error)
+	SetTransform(transform PageRecordTransform)
+	Truncate() error
 }
 
+type PageRecordTransform func(pageRecord diskModels.PageRecord) diskModels.PageRecord
+
 type PageMap struct {
 	m               *sync.Mutex
 	itemMap         map[string]*Page
 	currentPage     *Page
+	transform       PageRecordTransform
 	db              string
 	blob            string
 	pageDiskManager diskManagers.PageManager
@@ -47,6 +54,7 @@ func (pm *PageMap) Initialize() error {
 	}
 	for _, page := range pages {
 		pageObj := NewPage(pm.db, pm.blob, page.FileName, pm.dataLocation, pm.dataCaching)
+		pageObj.setTransform(pm.transform)
 		pm.itemMap[page.FileName] = pageObj
 		pm.currentPage = pageObj
 	}
@@ -70,6 +78,9 @@ func (pm *PageMap) GetAll() []*Page {
 	for _, page := range pm.itemMap {
 		pages = append(pages, page)
 	}
+	slices.SortFunc(pages, func(a *Page, b *Page) int {
+		return strings.Compare(a.fileName, b.fileName)
+	})
 	return pages
 }
 
@@ -84,6 +95,7 @@ func (pm *PageMap) Add() (*Page, error) {
 		return nil, err
 	}
 	page := NewPage(pm.db, pm.blob, fileName, pm.dataLocation, pm.dataCaching)
+	page.setTransform(pm.transform)
 	pm.itemMap[fileName] = page
 	pm.currentPage = page
 	return page, nil
@@ -112,6 +124,26 @@ func (pm *PageMap) GetCurrentPage() (*Page, error) {
 	return pm.currentPage, nil
 }
 
+func (pm *PageMap) SetTransform(transform PageRecordTransform) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	pm.transform = transform
+	for _, page := range pm.itemMap {
+		page.setTransform(transform)
+	}
+}
+
+func (pm *PageMap) Truncate() error {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if err := pm.pageDiskManager.Truncate(pm.db, pm.blob); err != nil {
+		return err
+	}
+	pm.itemMap = make(map[string]*Page)
+	pm.currentPage = nil
+	return nil
+}
+
 type Page struct {
 	m               *sync.Mutex
 	fileName        string
@@ -120,6 +152,7 @@ type Page struct {
 	blob            string
 	dataCaching     bool
 	cache           diskModels.PageRecords
+	transform       PageRecordTransform
 }
 
 func NewPage(db string, blob string, fileName string, dataLocation string, dataCaching bool) *Page {
@@ -137,6 +170,27 @@ func NewPage(db string, blob string, fileName string, dataLocation string, dataC
 func (p *Page) Read() (diskModels.PageRecords, error) {
 	p.m.Lock()
 	defer p.m.Unlock()
+	data, err := p.read()
+	if err != nil {
+		return data, err
+	}
+	return p.applyTransform(data), nil
+}
+
+func (p *Page) Write(data diskModels.PageRecords) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	err := p.pageDiskManager.WriteData(p.db, p.blob, p.fileName, data)
+	if err != nil {
+		return err
+	}
+	if p.dataCaching {
+		p.cache = data
+	}
+	return nil
+}
+
+func (p *Page) read() (diskModels.PageRecords, error) {
 	if !p.dataCaching {
 		return p.pageDiskManager.GetData(p.db, p.blob, p.fileName)
 	}
@@ -154,19 +208,42 @@ func (p *Page) Read() (diskModels.PageRecords, error) {
 	return pageRecords, nil
 }
 
-func (p *Page) Write(data diskModels.PageRecords) error {
+func (p *Page) rewrite() error {
 	p.m.Lock()
 	defer p.m.Unlock()
-	err := p.pageDiskManager.WriteData(p.db, p.blob, p.fileName, data)
+	if p.transform == nil {
+		return nil
+	}
+	data, err := p.read()
 	if err != nil {
 		return err
 	}
+	data = p.applyTransform(data)
+	if err = p.pageDiskManager.WriteData(p.db, p.blob, p.fileName, data); err != nil {
+		return err
+	}
 	if p.dataCaching {
 		p.cache = data
 	}
 	return nil
 }
 
+func (p *Page) setTransform(transform PageRecordTransform) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.transform = transform
+}
+
+func (p *Page) applyTransform(data diskModels.PageRecords) diskModels.PageRecords {
+	if p.transform == nil {
+		return data
+	}
+	for pageRecordId, pageRecord := range data {
+		data[pageRecordId] = p.transform(pageRecord)
+	}
+	return data
+}
+
 func (p *Page) GetFileName() string {
 	return p.fileName
 }
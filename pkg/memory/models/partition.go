@@ -3,6 +3,7 @@ package memoryModels
 import (
 	"errors"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"sync"
 )
 
@@ -13,6 +14,11 @@ type PartitionMapI interface {
 	Add(hashKeyFile string, pageFileName string) error
 	Delete(hashKeyFile string, pageFileName string) error
 	GetCurrentPage(hashKeyFile string) (*Page, error)
+	GetCatalog() (diskModels.PartitionCatalog, bool)
+	SetCatalog(catalog diskModels.PartitionCatalog) error
+	UpdateCatalog(hashKeyFile string, values map[string]any, recordDelta int) error
+	Drop(hashKeyFile string) error
+	Truncate() error
 }
 
 type PartitionMap struct {
@@ -20,6 +26,7 @@ type PartitionMap struct {
 	itemMap              PartitionHashMap
 	pageMap              PageMapI
 	currentPages         PartitionHashCurrentPageMap
+	catalog              diskModels.PartitionCatalog
 	db                   string
 	blob                 string
 	partitionDiskManager diskManagers.PartitionManager
@@ -143,3 +150,91 @@ func (pm *PartitionMap) GetCurrentPage(hashKeyFile string) (*Page, error) {
 	}
 	return nil, errors.New("current partition page not found")
 }
+
+func (pm *PartitionMap) GetCatalog() (diskModels.PartitionCatalog, bool) {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if !pm.loadCatalog() {
+		return nil, false
+	}
+	catalog := diskModels.PartitionCatalog{}
+	for hashKeyFile, catalogItem := range pm.catalog {
+		catalog[hashKeyFile] = catalogItem
+	}
+	return catalog, true
+}
+
+func (pm *PartitionMap) SetCatalog(catalog diskModels.PartitionCatalog) error {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if err := pm.partitionDiskManager.WriteCatalog(pm.db, pm.blob, catalog); err != nil {
+		return err
+	}
+	pm.catalog = catalog
+	return nil
+}
+
+func (pm *PartitionMap) UpdateCatalog(hashKeyFile string, values map[string]any, recordDelta int) error {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if !pm.loadCatalog() {
+		return errors.New("partition catalog has not been built")
+	}
+	catalogItem := pm.catalog[hashKeyFile]
+	if catalogItem.Values == nil {
+		catalogItem.Values = values
+	}
+	catalogItem.Records += recordDelta
+	catalogItem.Pages = len(pm.itemMap[hashKeyFile])
+	if catalogItem.Records <= 0 && catalogItem.Pages == 0 {
+		delete(pm.catalog, hashKeyFile)
+	} else {
+		pm.catalog[hashKeyFile] = catalogItem
+	}
+	return pm.partitionDiskManager.WriteCatalog(pm.db, pm.blob, pm.catalog)
+}
+
+func (pm *PartitionMap) Drop(hashKeyFile string) error {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if err := pm.partitionDiskManager.Delete(pm.db, pm.blob, hashKeyFile); err != nil {
+		return err
+	}
+	delete(pm.itemMap, hashKeyFile)
+	delete(pm.currentPages, hashKeyFile)
+	if !pm.loadCatalog() {
+		return nil
+	}
+	if _, ok := pm.catalog[hashKeyFile]; !ok {
+		return nil
+	}
+	delete(pm.catalog, hashKeyFile)
+	return pm.partitionDiskManager.WriteCatalog(pm.db, pm.blob, pm.catalog)
+}
+
+func (pm *PartitionMap) Truncate() error {
+	pm.m.Lock()
+	defer pm.m.Unlock()
+	if err := pm.partitionDiskManager.Truncate(pm.db, pm.blob); err != nil {
+		return err
+	}
+	pm.itemMap = PartitionHashMap{}
+	pm.currentPages = PartitionHashCurrentPageMap{}
+	pm.catalog = diskModels.PartitionCatalog{}
+	return nil
+}
+
+func (pm *PartitionMap) loadCatalog() bool {
+	if pm.catalog != nil {
+		return true
+	}
+	catalog, err := pm.partitionDiskManager.GetCatalog(pm.db, pm.blob)
+	if err != nil {
+		if len(pm.itemMap) > 0 {
+			return false
+		}
+		catalog = diskModels.PartitionCatalog{}
+	}
+	pm.catalog = catalog
+	return true
+}
@@ -0,0 +1,160 @@
+package memoryModels
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/utils"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	partitionHashKeyLength = 28
+	rangeLabelLength       = 4
+	dayLabelLayout         = "20060102"
+	monthLabelLayout       = "200601"
+)
+
+func GetPartitionKeyLength(partition diskModels.Partition, partitionKey string) int {
+	bucket, ok := partition.Buckets[partitionKey]
+	if !ok {
+		return partitionHashKeyLength
+	}
+	if bucket.Type == memoryConstants.PartitionRange {
+		return rangeLabelLength
+	}
+	return len(getPeriodLayout(bucket.Period))
+}
+
+func GetBucketLabel(bucket diskModels.PartitionBucket, value any, keyType string) (string, error) {
+	number, ok := getBucketValue(value, keyType)
+	if !ok {
+		return "", errors.New(fmt.Sprintf("%+v cannot be placed in a %s partition", value, bucket.Type))
+	}
+	if bucket.Type == memoryConstants.PartitionRange {
+		index := sort.Search(len(bucket.Bounds), func(i int) bool {
+			return bucket.Bounds[i] > number
+		})
+		return fmt.Sprintf("%0*d", rangeLabelLength, index), nil
+	}
+	timeValue := time.Unix(int64(math.Floor(number)), 0).UTC()
+	if timeValue.Year() < 0 || timeValue.Year() > 9999 {
+		return "", errors.New(fmt.Sprintf("%+v is outside the supported time partition range", value))
+	}
+	return timeValue.Format(getPeriodLayout(bucket.Period)), nil
+}
+
+func GetBucketRange(bucket diskModels.PartitionBucket, label string) (float64, float64, error) {
+	if bucket.Type == memoryConstants.PartitionRange {
+		index, err := strconv.Atoi(label)
+		if err != nil || index < 0 || index > len(bucket.Bounds) {
+			return 0, 0, errors.New(fmt.Sprintf("range partition label %s is not valid", label))
+		}
+		lower, upper := math.Inf(-1), math.Inf(1)
+		if index > 0 {
+			lower = bucket.Bounds[index-1]
+		}
+		if index < len(bucket.Bounds) {
+			upper = bucket.Bounds[index]
+		}
+		return lower, upper, nil
+	}
+	start, err := time.Parse(getPeriodLayout(bucket.Period), label)
+	if err != nil {
+		return 0, 0, errors.New(fmt.Sprintf("time partition label %s is not valid", label))
+	}
+	end := start.AddDate(0, 0, 1)
+	if bucket.Period == memoryConstants.PeriodMonth {
+		end = start.AddDate(0, 1, 0)
+	}
+	return float64(start.Unix()), float64(end.Unix()), nil
+}
+
+func DescribeBucketLabel(bucket diskModels.PartitionBucket, label string) (string, error) {
+	lower, upper, err := GetBucketRange(bucket, label)
+	if err != nil {
+		return "", err
+	}
+	if bucket.Type == memoryConstants.PartitionRange {
+		return fmt.Sprintf("[%s, %s)", formatBucketBound(lower), formatBucketBound(upper)), nil
+	}
+	start := time.Unix(int64(lower), 0).UTC()
+	if bucket.Period == memoryConstants.PeriodMonth {
+		return start.Format("2006-01"), nil
+	}
+	return start.Format(time.DateOnly), nil
+}
+
+func BucketMayPass(filterItem FilterItem, keyType string, lower float64, upper float64) bool {
+	var values []any
+	switch filterItem.Op {
+	case memoryConstants.OpIn, memoryConstants.OpBetween:
+		var ok bool
+		if values, ok = filterItem.Value.([]any); !ok {
+			return true
+		}
+	default:
+		values = []any{filterItem.Value}
+	}
+	numbers := []float64{}
+	for _, value := range values {
+		number, ok := getBucketValue(value, keyType)
+		if !ok {
+			return true
+		}
+		numbers = append(numbers, number)
+	}
+	switch filterItem.Op {
+	case memoryConstants.OpEqual, memoryConstants.OpIn:
+		for _, number := range numbers {
+			if number >= lower && number < upper {
+				return true
+			}
+		}
+		return false
+	case memoryConstants.OpBetween:
+		return len(numbers) != 2 || (lower <= numbers[1] && upper > numbers[0])
+	case memoryConstants.OpGreater, memoryConstants.OpGreaterEqual:
+		return upper > numbers[0]
+	case memoryConstants.OpLess:
+		return lower < numbers[0]
+	case memoryConstants.OpLessEqual:
+		return lower <= numbers[0]
+	default:
+		return true
+	}
+}
+
+func getBucketValue(value any, keyType string) (float64, bool) {
+	if keyType == memoryConstants.Decimal {
+		decimal, err := memoryUtils.ConvertToDecimal(value)
+		if err != nil {
+			return 0, false
+		}
+		number, _ := decimal.Float64()
+		return number, true
+	}
+	return GetOrderedKeyIndexValue(value, keyType)
+}
+
+func formatBucketBound(bound float64) string {
+	switch {
+	case math.IsInf(bound, -1):
+		return "-inf"
+	case math.IsInf(bound, 1):
+		return "inf"
+	default:
+		return strconv.FormatFloat(bound, 'f', -1, 64)
+	}
+}
+
+func getPeriodLayout(period string) string {
+	if period == memoryConstants.PeriodMonth {
+		return monthLabelLayout
+	}
+	return dayLabelLayout
+}
@@ -0,0 +1,125 @@
+package memoryModels
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestUnit_GetPartitionKeyLength_GetsLengthByPartitionType(t *testing.T) {
+	partition := diskModels.Partition{
+		Keys: []string{"region", "qty", "day", "month"},
+		Buckets: map[string]diskModels.PartitionBucket{
+			"qty":   {Type: memoryConstants.PartitionRange, Bounds: []float64{10}},
+			"day":   {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay},
+			"month": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodMonth},
+		},
+	}
+
+	assert.Equal(t, 28, GetPartitionKeyLength(partition, "region"))
+	assert.Equal(t, 4, GetPartitionKeyLength(partition, "qty"))
+	assert.Equal(t, 8, GetPartitionKeyLength(partition, "day"))
+	assert.Equal(t, 6, GetPartitionKeyLength(partition, "month"))
+}
+
+func TestUnit_GetBucketLabel_GetsRangeLabels(t *testing.T) {
+	bucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionRange, Bounds: []float64{10, 100}}
+	tests := []struct {
+		value    any
+		keyType  string
+		expected string
+	}{
+		{-5, memoryConstants.Int, "0000"},
+		{10, memoryConstants.Int, "0001"},
+		{99.5, memoryConstants.Float, "0001"},
+		{"100.00", memoryConstants.Decimal, "0002"},
+	}
+	for _, test := range tests {
+		label, err := GetBucketLabel(bucket, test.value, test.keyType)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, label)
+	}
+}
+
+func TestUnit_GetBucketLabel_GetsTimeLabels(t *testing.T) {
+	day := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay}
+	month := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodMonth}
+
+	dayLabel, dayErr := GetBucketLabel(day, "2024-03-15T23:30:00Z", memoryConstants.DateTime)
+	monthLabel, monthErr := GetBucketLabel(month, "2024-03-15", memoryConstants.Date)
+	_, err := GetBucketLabel(day, "soon", memoryConstants.DateTime)
+
+	assert.Nil(t, dayErr)
+	assert.Nil(t, monthErr)
+	assert.Equal(t, "20240315", dayLabel)
+	assert.Equal(t, "202403", monthLabel)
+	assert.NotNil(t, err)
+}
+
+func TestUnit_GetBucketRange_GetsBucketRanges(t *testing.T) {
+	rangeBucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionRange, Bounds: []float64{10, 100}}
+	monthBucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodMonth}
+
+	firstLower, firstUpper, firstErr := GetBucketRange(rangeBucket, "0000")
+	lastLower, lastUpper, lastErr := GetBucketRange(rangeBucket, "0002")
+	monthLower, monthUpper, monthErr := GetBucketRange(monthBucket, "202402")
+	_, _, err := GetBucketRange(rangeBucket, "0003")
+
+	assert.Nil(t, firstErr)
+	assert.Nil(t, lastErr)
+	assert.Nil(t, monthErr)
+	assert.Equal(t, math.Inf(-1), firstLower)
+	assert.Equal(t, float64(10), firstUpper)
+	assert.Equal(t, float64(100), lastLower)
+	assert.Equal(t, math.Inf(1), lastUpper)
+	assert.Equal(t, float64(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Unix()), monthLower)
+	assert.Equal(t, float64(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix()), monthUpper)
+	assert.NotNil(t, err)
+}
+
+func TestUnit_DescribeBucketLabel_DescribesBucketLabels(t *testing.T) {
+	rangeBucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionRange, Bounds: []float64{10, 100.5}}
+	dayBucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay}
+	monthBucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodMonth}
+
+	first, _ := DescribeBucketLabel(rangeBucket, "0000")
+	middle, _ := DescribeBucketLabel(rangeBucket, "0001")
+	last, _ := DescribeBucketLabel(rangeBucket, "0002")
+	day, _ := DescribeBucketLabel(dayBucket, "20240315")
+	month, _ := DescribeBucketLabel(monthBucket, "202402")
+	_, err := DescribeBucketLabel(dayBucket, "2024")
+
+	assert.Equal(t, "[-inf, 10)", first)
+	assert.Equal(t, "[10, 100.5)", middle)
+	assert.Equal(t, "[100.5, inf)", last)
+	assert.Equal(t, "2024-03-15", day)
+	assert.Equal(t, "2024-02", month)
+	assert.NotNil(t, err)
+}
+
+func TestUnit_BucketMayPass_ChecksFilterAgainstBucketRange(t *testing.T) {
+	tests := []struct {
+		filterItem FilterItem
+		expected   bool
+	}{
+		{FilterItem{Op: memoryConstants.OpEqual, Value: 10}, true},
+		{FilterItem{Op: memoryConstants.OpEqual, Value: 20}, false},
+		{FilterItem{Op: memoryConstants.OpIn, Value: []any{1, 15}}, true},
+		{FilterItem{Op: memoryConstants.OpIn, Value: []any{1, 25}}, false},
+		{FilterItem{Op: memoryConstants.OpBetween, Value: []any{15, 30}}, true},
+		{FilterItem{Op: memoryConstants.OpBetween, Value: []any{20, 30}}, false},
+		{FilterItem{Op: memoryConstants.OpGreater, Value: 19}, true},
+		{FilterItem{Op: memoryConstants.OpGreaterEqual, Value: 20}, false},
+		{FilterItem{Op: memoryConstants.OpLess, Value: 10}, false},
+		{FilterItem{Op: memoryConstants.OpLessEqual, Value: 10}, true},
+		{FilterItem{Op: memoryConstants.OpNotEqual, Value: 10}, true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, BucketMayPass(test.filterItem, memoryConstants.Int, 10, 20), test.filterItem)
+	}
+	assert.False(t, BucketMayPass(FilterItem{Op: memoryConstants.OpGreater, Value: big.NewRat(25, 1)}, memoryConstants.Decimal, 10, 20))
+}
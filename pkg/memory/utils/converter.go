@@ -3,10 +3,14 @@ package memoryUtils
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"regexp"
 	"strconv"
 )
 
+var decimalPattern = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
 func ConvertToInt(value any) (int, error) {
 	switch value.(type) {
 	case int:
@@ -36,3 +40,27 @@ func ConvertToFloat64(value any) (float64, error) {
 		return -1, errors.New(fmt.Sprintf("cannot convert %+v of type %t to float", value, reflect.TypeOf(value)))
 	}
 }
+
+func ConvertToDecimal(value any) (*big.Rat, error) {
+	switch value.(type) {
+	case *big.Rat:
+		return new(big.Rat).Set(value.(*big.Rat)), nil
+	case int:
+		return new(big.Rat).SetInt64(int64(value.(int))), nil
+	case int64:
+		return new(big.Rat).SetInt64(value.(int64)), nil
+	case float64:
+		return ConvertToDecimal(strconv.FormatFloat(value.(float64), 'f', -1, 64))
+	case string:
+		if !decimalPattern.MatchString(value.(string)) {
+			return nil, errors.New(fmt.Sprintf("cannot convert %s to decimal", value.(string)))
+		}
+		decimal, ok := new(big.Rat).SetString(value.(string))
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("cannot convert %s to decimal", value.(string)))
+		}
+		return decimal, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("cannot convert %+v of type %T to decimal", value, value))
+	}
+}
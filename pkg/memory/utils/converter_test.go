@@ -0,0 +1,43 @@
+package memoryUtils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestUnit_ConvertToDecimal_ConvertsPlainDecimals(t *testing.T) {
+	values := map[any]string{
+		"12.50":  "25/2",
+		"-3":     "-3",
+		"+0.125": "1/8",
+		12.5:     "25/2",
+		7:        "7",
+	}
+
+	for value, expected := range values {
+		decimal, err := ConvertToDecimal(value)
+		assert.Nil(t, err, "%+v", value)
+		assert.Equal(t, expected, decimal.RatString(), "%+v", value)
+	}
+}
+
+func TestUnit_ConvertToDecimal_FailsOnNonPlainDecimalStrings(t *testing.T) {
+	for _, value := range []string{"1/2", "1e1", "1E-2", "0x10", ".5", "5.", "", " 1", "NaN"} {
+		_, err := ConvertToDecimal(value)
+		assert.NotNil(t, err, value)
+		if err != nil {
+			assert.Equal(t, "cannot convert "+value+" to decimal", err.Error())
+		}
+	}
+}
+
+func TestUnit_ConvertToDecimal_CopiesRat(t *testing.T) {
+	value := big.NewRat(1, 2)
+
+	decimal, err := ConvertToDecimal(value)
+
+	assert.Nil(t, err)
+	assert.NotSame(t, value, decimal)
+	assert.Equal(t, "1/2", decimal.RatString())
+}
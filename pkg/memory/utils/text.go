@@ -0,0 +1,57 @@
+package memoryUtils
+
+import (
+	"strings"
+	"unicode"
+)
+
+func Tokenize(text string) []string {
+	tokens := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsNumber(char)
+	}) {
+		tokens = append(tokens, Stem(word))
+	}
+	return tokens
+}
+
+func GetUniqueTokens(text string) []string {
+	seen := make(map[string]bool)
+	tokens := []string{}
+	for _, token := range Tokenize(text) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func Stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		return trimDoubleConsonant(word[:len(word)-3])
+	case len(word) > 4 && strings.HasSuffix(word, "ed"):
+		return trimDoubleConsonant(word[:len(word)-2])
+	case len(word) > 4 && strings.HasSuffix(word, "ly"):
+		return word[:len(word)-2]
+	case len(word) > 4 && (strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+func trimDoubleConsonant(stem string) string {
+	if len(stem) < 2 || stem[len(stem)-1] != stem[len(stem)-2] {
+		return stem
+	}
+	if strings.ContainsRune("aeiouls", rune(stem[len(stem)-1])) {
+		return stem
+	}
+	return stem[:len(stem)-1]
+}
@@ -0,0 +1,46 @@
+package memoryUtils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const epochMillisThreshold = 100000000000
+
+func ConvertToTime(value any) (time.Time, error) {
+	switch value.(type) {
+	case time.Time:
+		return value.(time.Time).UTC(), nil
+	case float64:
+		seconds := value.(float64)
+		if seconds >= epochMillisThreshold || seconds <= -epochMillisThreshold {
+			seconds /= 1000
+		}
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(fraction*float64(time.Second))).UTC(), nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if parsed, err := time.Parse(layout, value.(string)); err == nil {
+				return parsed.UTC(), nil
+			}
+		}
+	}
+	epoch, err := ConvertToInt(value)
+	if err != nil {
+		return time.Time{}, errors.New(fmt.Sprintf("cannot convert %+v to time", value))
+	}
+	if epoch >= epochMillisThreshold || epoch <= -epochMillisThreshold {
+		return time.UnixMilli(int64(epoch)).UTC(), nil
+	}
+	return time.Unix(int64(epoch), 0).UTC(), nil
+}
+
+func FormatDateTime(value time.Time) string {
+	return value.Format(time.RFC3339Nano)
+}
+
+func ConvertToUnixSeconds(value time.Time) float64 {
+	return float64(value.Unix()) + float64(value.Nanosecond())/float64(time.Second)
+}
@@ -1,18 +1,24 @@
 package queryConstants
 
 const (
-	ActionCreate = "create"
-	ActionDelete = "delete"
-	ActionUpdate = "update"
-	ActionGet    = "get"
+	ActionCreate   = "create"
+	ActionDelete   = "delete"
+	ActionUpdate   = "update"
+	ActionGet      = "get"
+	ActionTruncate = "truncate"
 
 	OnDBs = "dbs"
 
 	OnDB    = "db"
 	OnBlobs = "blobs"
 
-	OnBlob = "blob"
-	OnData = "data"
+	OnBlob       = "blob"
+	OnData       = "data"
+	OnAggregate  = "aggregate"
+	OnIndex      = "index"
+	OnPartitions = "partitions"
+	OnPartition  = "partition"
+	OnAlter      = "alter"
 
 	OnLogs       = "logs"
 	OnUsers      = "users"
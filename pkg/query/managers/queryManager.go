@@ -1,18 +1,23 @@
 package queryManagers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/managers"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/system/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"sort"
 	"strings"
 )
 
 type QueryManager interface {
-	Query(query queryModels.Query) queryModels.QueryResult
+	Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult
 }
 
 type queryManager struct {
@@ -25,27 +30,44 @@ var queryManagerInstance *queryManager
 
 func CreateQueryManager(operationManager memoryManagers.OperationManager, userManager systemManagers.UserManager, logManager systemManagers.LogManager) QueryManager {
 	if queryManagerInstance == nil {
-		queryManagerInstance = &queryManager{
-			operationManager: operationManager,
-			userManager:      userManager,
-			logManager:       logManager,
-		}
+		queryManagerInstance = NewQueryManager(operationManager, userManager, logManager).(*queryManager)
 	}
 	return queryManagerInstance
 }
 
-func (qm *queryManager) Query(query queryModels.Query) queryModels.QueryResult {
+func NewQueryManager(operationManager memoryManagers.OperationManager, userManager systemManagers.UserManager, logManager systemManagers.LogManager) QueryManager {
+	return &queryManager{
+		operationManager: operationManager,
+		userManager:      userManager,
+		logManager:       logManager,
+	}
+}
+
+func (qm *queryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if err := qm.checkPermission(query, user); err != nil {
+		return queryModels.QueryResult{
+			ErrorMessage:     err.Error(),
+			PermissionDenied: true,
+		}
+	}
 	switch query.Action {
 	case queryConstants.ActionCreate:
-		return qm.handleActionCreate(query)
+		queryResult := qm.handleActionCreate(query)
+		if query.On == queryConstants.OnConnection {
+			return queryResult
+		}
+		return qm.log(query, user, queryResult)
 	case queryConstants.ActionDelete:
 		queryResult := qm.handleActionDelete(query)
-		return queryResult
+		return qm.log(query, user, queryResult)
 	case queryConstants.ActionUpdate:
 		queryResult := qm.handleActionUpdate(query)
-		return queryResult
+		return qm.log(query, user, queryResult)
 	case queryConstants.ActionGet:
-		return qm.handleActionGet(query)
+		return qm.handleActionGet(query, user)
+	case queryConstants.ActionTruncate:
+		queryResult := qm.handleActionTruncate(query)
+		return qm.log(query, user, queryResult)
 	default:
 		return queryModels.QueryResult{
 			ErrorMessage: fmt.Sprintf("action %s does not exist", query.Action),
@@ -57,9 +79,13 @@ func (qm *queryManager) handleActionCreate(query queryModels.Query) queryModels.
 	switch query.On {
 	case queryConstants.OnConnection:
 		errMessage := ""
+		userConnection := systemModels.UserConnection{}
+		if query.With.UserConnection != nil {
+			userConnection = *query.With.UserConnection
+		}
 		user, err := qm.userManager.Authenticate(
-			query.With.UserConnection.User,
-			query.With.UserConnection.Password,
+			userConnection.User,
+			userConnection.Password,
 		)
 		if err != nil {
 			errMessage = err.Error()
@@ -84,18 +110,48 @@ func (qm *queryManager) handleActionCreate(query queryModels.Query) queryModels.
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		format, err := qm.buildFormat(query.With)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
 		err = qm.operationManager.CreateBlob(
 			nameSplit.DB,
 			nameSplit.Blob,
-			qm.buildFormat(query.With.Format),
-			qm.buildPartition(query.With.Partition),
+			format,
+			qm.buildPartition(query.With.Partition, query.With.PartitionBuckets),
 		)
 		if err != nil {
 			errMessage = err.Error()
+			notFound = isNotFound(err)
 		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
+		}
+	case queryConstants.OnIndex:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
+		err = qm.operationManager.CreateIndex(
+			nameSplit.DB,
+			nameSplit.Blob,
+			query.With.Key,
+			query.With.IndexType,
+		)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
 		}
 	case queryConstants.OnData:
 		nameSplit, err := qm.getSplitName(query.Name)
@@ -104,7 +160,7 @@ func (qm *queryManager) handleActionCreate(query queryModels.Query) queryModels.
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		errMessage, notFound := "", false
 		records, err := qm.operationManager.AddRecords(
 			nameSplit.DB,
 			nameSplit.Blob,
@@ -112,9 +168,11 @@ func (qm *queryManager) handleActionCreate(query queryModels.Query) queryModels.
 		)
 		if err != nil {
 			errMessage = err.Error()
+			notFound = isNotFound(err)
 		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
 			Records:      records,
 		}
 	default:
@@ -127,13 +185,15 @@ func (qm *queryManager) handleActionCreate(query queryModels.Query) queryModels.
 func (qm *queryManager) handleActionDelete(query queryModels.Query) queryModels.QueryResult {
 	switch query.On {
 	case queryConstants.OnDB:
-		errMessage := ""
+		errMessage, notFound := "", false
 		err := qm.operationManager.DeleteDB(query.Name)
 		if err != nil {
 			errMessage = err.Error()
+			notFound = isNotFound(err)
 		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
 		}
 	case queryConstants.OnBlob:
 		nameSplit, err := qm.getSplitName(query.Name)
@@ -142,16 +202,60 @@ func (qm *queryManager) handleActionDelete(query queryModels.Query) queryModels.
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		errMessage, notFound := "", false
 		err = qm.operationManager.DeleteBlob(
 			nameSplit.DB,
 			nameSplit.Blob,
 		)
 		if err != nil {
 			errMessage = err.Error()
+			notFound = isNotFound(err)
 		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
+		}
+	case queryConstants.OnIndex:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
+		err = qm.operationManager.DeleteIndex(
+			nameSplit.DB,
+			nameSplit.Blob,
+			query.With.Key,
+		)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
+		}
+	case queryConstants.OnPartition:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
+		err = qm.operationManager.DropPartition(
+			nameSplit.DB,
+			nameSplit.Blob,
+			query.With.SearchPartition,
+		)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
 		}
 	case queryConstants.OnData:
 		nameSplit, err := qm.getSplitName(query.Name)
@@ -160,7 +264,7 @@ func (qm *queryManager) handleActionDelete(query queryModels.Query) queryModels.
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		errMessage, notFound := "", false
 		if query.With.Index != "" {
 			err = qm.operationManager.DeleteRecordByIndex(
 				nameSplit.DB,
@@ -169,6 +273,7 @@ func (qm *queryManager) handleActionDelete(query queryModels.Query) queryModels.
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
 			}
 		} else {
 			err = qm.operationManager.DeleteRecords(
@@ -179,10 +284,12 @@ func (qm *queryManager) handleActionDelete(query queryModels.Query) queryModels.
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
 			}
 		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
 		}
 	default:
 		return queryModels.QueryResult{
@@ -200,7 +307,7 @@ func (qm *queryManager) handleActionUpdate(query queryModels.Query) queryModels.
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		errMessage, notFound := "", false
 		if query.With.Index != "" {
 			err = qm.operationManager.UpdateRecordByIndex(
 				nameSplit.DB,
@@ -210,6 +317,7 @@ func (qm *queryManager) handleActionUpdate(query queryModels.Query) queryModels.
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
 			}
 		} else {
 			err = qm.operationManager.UpdateRecords(
@@ -221,10 +329,34 @@ func (qm *queryManager) handleActionUpdate(query queryModels.Query) queryModels.
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
+			}
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
+		}
+	case queryConstants.OnBlob:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		if query.With.Alter == nil {
+			return queryModels.QueryResult{
+				ErrorMessage: fmt.Sprintf("alter is required to update %s", query.Name),
 			}
 		}
+		errMessage, notFound := "", false
+		err = qm.operationManager.AlterBlob(nameSplit.DB, nameSplit.Blob, *query.With.Alter)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
 		}
 	default:
 		return queryModels.QueryResult{
@@ -233,7 +365,33 @@ func (qm *queryManager) handleActionUpdate(query queryModels.Query) queryModels.
 	}
 }
 
-func (qm *queryManager) handleActionGet(query queryModels.Query) queryModels.QueryResult {
+func (qm *queryManager) handleActionTruncate(query queryModels.Query) queryModels.QueryResult {
+	switch query.On {
+	case queryConstants.OnBlob:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
+		err = qm.operationManager.TruncateBlob(nameSplit.DB, nameSplit.Blob)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
+		}
+	default:
+		return queryModels.QueryResult{
+			ErrorMessage: fmt.Sprintf("%s not allowed on action %s", query.On, query.Action),
+		}
+	}
+}
+
+func (qm *queryManager) handleActionGet(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
 	switch query.On {
 	case queryConstants.OnData:
 		nameSplit, err := qm.getSplitName(query.Name)
@@ -242,16 +400,26 @@ func (qm *queryManager) handleActionGet(query queryModels.Query) queryModels.Que
 				ErrorMessage: err.Error(),
 			}
 		}
-		errMessage := ""
+		errMessage, notFound := "", false
 		var records []diskModels.PageRecord
+		getOperationParams := memoryModels.GetOperationParams{
+			Limit:         query.With.Limit,
+			Offset:        query.With.Offset,
+			Sort:          query.With.Sort,
+			Fields:        query.With.Fields,
+			ExcludeFields: query.With.ExcludeFields,
+			TimeZone:      query.With.TimeZone,
+		}
 		if query.With.Index != "" {
 			record, err := qm.operationManager.GetRecordByIndex(
 				nameSplit.DB,
 				nameSplit.Blob,
 				query.With.Index,
+				getOperationParams,
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
 			}
 			records = append(records, record)
 		} else {
@@ -260,24 +428,112 @@ func (qm *queryManager) handleActionGet(query queryModels.Query) queryModels.Que
 				nameSplit.Blob,
 				query.With.Filter,
 				query.With.SearchPartition,
-				memoryModels.GetOperationParams{},
+				getOperationParams,
 			)
 			if err != nil {
 				errMessage = err.Error()
+				notFound = isNotFound(err)
+			}
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
+			Records:      records,
+		}
+	case queryConstants.OnAggregate:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
 			}
 		}
+		errMessage, notFound := "", false
+		records, err := qm.operationManager.AggregateRecords(
+			nameSplit.DB,
+			nameSplit.Blob,
+			query.With.Filter,
+			query.With.SearchPartition,
+			memoryModels.AggregateParams{
+				Aggregates: query.With.Aggregates,
+				GroupBy:    query.With.GroupBy,
+			},
+		)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
 		return queryModels.QueryResult{
 			ErrorMessage: errMessage,
+			NotFound:     notFound,
 			Records:      records,
 		}
 	case queryConstants.OnDBs:
+		records := []diskModels.PageRecord{}
+		for _, record := range qm.operationManager.GetDBs() {
+			if name, ok := record["name"].(string); ok && systemConstants.IsSystemName(name) && !systemConstants.HasSuperRead(user.Permission) {
+				continue
+			}
+			records = append(records, record)
+		}
 		return queryModels.QueryResult{
-			Records: qm.operationManager.GetDBs(),
+			Records: records,
 		}
 	case queryConstants.OnBlobs:
+		if !qm.operationManager.DBExists(query.Name) {
+			return queryModels.QueryResult{
+				ErrorMessage: memoryModels.DBNotFoundError{DB: query.Name}.Error(),
+				NotFound:     true,
+			}
+		}
 		return queryModels.QueryResult{
 			Records: qm.operationManager.GetBlobs(query.Name),
 		}
+	case queryConstants.OnPartitions:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		errMessage, notFound := "", false
+		records, err := qm.operationManager.GetPartitions(nameSplit.DB, nameSplit.Blob)
+		if err != nil {
+			errMessage = err.Error()
+			notFound = isNotFound(err)
+		}
+		return queryModels.QueryResult{
+			ErrorMessage: errMessage,
+			NotFound:     notFound,
+			Records:      records,
+		}
+	case queryConstants.OnAlter:
+		nameSplit, err := qm.getSplitName(query.Name)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+			}
+		}
+		alterState, ok, err := qm.operationManager.GetAlterState(nameSplit.DB, nameSplit.Blob)
+		if err != nil {
+			return queryModels.QueryResult{
+				ErrorMessage: err.Error(),
+				NotFound:     isNotFound(err),
+			}
+		}
+		records := []diskModels.PageRecord{}
+		if ok {
+			records = append(records, diskModels.PageRecord{
+				"op":         alterState.Op,
+				"key":        alterState.Key,
+				"running":    alterState.Running,
+				"pagesDone":  alterState.PagesDone,
+				"pagesTotal": alterState.PagesTotal,
+				"error":      alterState.Error,
+			})
+		}
+		return queryModels.QueryResult{
+			Records: records,
+		}
 	case queryConstants.OnLogs:
 		errMessage := ""
 		logs, err := qm.logManager.GetLogs(query.With.Filter)
@@ -313,6 +569,48 @@ func (qm *queryManager) handleActionGet(query queryModels.Query) queryModels.Que
 	}
 }
 
+func (qm *queryManager) log(query queryModels.Query, user systemModels.User, queryResult queryModels.QueryResult) queryModels.QueryResult {
+	if queryResult.ErrorMessage != "" {
+		return queryResult
+	}
+	if query.Action == queryConstants.ActionCreate && query.On == queryConstants.OnData {
+		query.With.Records = queryResult.Records
+	}
+	if err := qm.logManager.AddLog(query, user); err != nil {
+		queryResult.ErrorMessage = fmt.Sprintf("query succeeded but could not be logged: %s", err.Error())
+	}
+	return queryResult
+}
+
+func (qm *queryManager) checkPermission(query queryModels.Query, user systemModels.User) error {
+	if query.Action == queryConstants.ActionCreate && query.On == queryConstants.OnConnection {
+		return nil
+	}
+	isWrite := query.Action != queryConstants.ActionGet
+	isSystem := query.On == queryConstants.OnLogs ||
+		query.On == queryConstants.OnUsers ||
+		systemConstants.IsSystemName(strings.Split(query.Name, ".")[0])
+	var allowed bool
+	switch {
+	case isSystem && isWrite:
+		allowed = systemConstants.HasSuper(user.Permission)
+	case isSystem:
+		allowed = systemConstants.HasSuperRead(user.Permission)
+	case isWrite:
+		allowed = systemConstants.HasReadWrite(user.Permission)
+	default:
+		allowed = systemConstants.HasRead(user.Permission)
+	}
+	if !allowed {
+		target := query.On
+		if query.Name != "" {
+			target = fmt.Sprintf("%s %s", query.On, query.Name)
+		}
+		return fmt.Errorf("permission denied: user %s with permission %s cannot %s %s", user.User, user.Permission, query.Action, target)
+	}
+	return nil
+}
+
 func (qm *queryManager) getSplitName(name string) (queryModels.NameSplit, error) {
 	items := strings.Split(name, ".")
 	if len(items) != 2 {
@@ -324,17 +622,102 @@ func (qm *queryManager) getSplitName(name string) (queryModels.NameSplit, error)
 	}, nil
 }
 
-func (qm *queryManager) buildFormat(format map[string]string) diskModels.Format {
+func (qm *queryManager) buildFormat(with queryModels.With) (diskModels.Format, error) {
 	formatObj := make(map[string]diskModels.FormatItem)
-	for key, keyType := range format {
-		formatObj[key] = diskModels.FormatItem{KeyType: keyType}
+	keys := []string{}
+	for key := range with.Format {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return strings.Count(keys[i], memoryConstants.KeySeparator) < strings.Count(keys[j], memoryConstants.KeySeparator)
+	})
+	for _, key := range keys {
+		format, pathKey, ok := qm.getParentFormat(formatObj, key)
+		if !ok {
+			return nil, fmt.Errorf("parent of key %s is not an object in format", key)
+		}
+		formatItem := diskModels.FormatItem{KeyType: with.Format[key]}
+		if memoryConstants.HasNestedFormat(formatItem.KeyType) {
+			formatItem.Format = diskModels.Format{}
+		}
+		format[pathKey] = formatItem
+	}
+	for _, key := range with.Optional {
+		format, pathKey, ok := qm.getParentFormat(formatObj, key)
+		formatItem, exists := format[pathKey]
+		if !ok || !exists {
+			return nil, fmt.Errorf("optional key %s not found in format", key)
+		}
+		formatItem.Optional = true
+		format[pathKey] = formatItem
+	}
+	for key, value := range with.Default {
+		format, pathKey, ok := qm.getParentFormat(formatObj, key)
+		formatItem, exists := format[pathKey]
+		if !ok || !exists {
+			return nil, fmt.Errorf("default key %s not found in format", key)
+		}
+		formatItem.Default = value
+		format[pathKey] = formatItem
 	}
-	return formatObj
+	for key, scale := range with.Scale {
+		format, pathKey, ok := qm.getParentFormat(formatObj, key)
+		formatItem, exists := format[pathKey]
+		if !ok || !exists {
+			return nil, fmt.Errorf("scale key %s not found in format", key)
+		}
+		formatItem.Scale = scale
+		format[pathKey] = formatItem
+	}
+	for key, values := range with.Values {
+		format, pathKey, ok := qm.getParentFormat(formatObj, key)
+		formatItem, exists := format[pathKey]
+		if !ok || !exists {
+			return nil, fmt.Errorf("values key %s not found in format", key)
+		}
+		formatItem.Values = values
+		format[pathKey] = formatItem
+	}
+	for _, uniqueKeys := range with.Unique {
+		if len(uniqueKeys) == 0 {
+			return nil, fmt.Errorf("unique keys cannot be empty")
+		}
+		formatItem, ok := formatObj[uniqueKeys[0]]
+		if !ok {
+			return nil, fmt.Errorf("unique key %s not found in format", uniqueKeys[0])
+		}
+		if formatItem.Unique {
+			return nil, fmt.Errorf("unique key %s is already unique", uniqueKeys[0])
+		}
+		formatItem.Unique = true
+		formatItem.UniqueWith = uniqueKeys[1:]
+		formatObj[uniqueKeys[0]] = formatItem
+	}
+	return formatObj, nil
 }
 
-func (qm *queryManager) buildPartition(partition []string) *diskModels.Partition {
+func (qm *queryManager) getParentFormat(format diskModels.Format, key string) (diskModels.Format, string, bool) {
+	keys := strings.Split(key, memoryConstants.KeySeparator)
+	for _, pathKey := range keys[:len(keys)-1] {
+		formatItem, ok := format[pathKey]
+		if !ok || formatItem.Format == nil {
+			return nil, "", false
+		}
+		format = formatItem.Format
+	}
+	return format, keys[len(keys)-1], true
+}
+
+func (qm *queryManager) buildPartition(partition []string, buckets map[string]diskModels.PartitionBucket) *diskModels.Partition {
 	if partition == nil || len(partition) == 0 {
 		return nil
 	}
-	return &diskModels.Partition{Keys: partition}
+	return &diskModels.Partition{Keys: partition, Buckets: buckets}
+}
+
+func isNotFound(err error) bool {
+	return errors.As(err, &memoryModels.RecordNotFoundError{}) ||
+		errors.As(err, &memoryModels.DBNotFoundError{}) ||
+		errors.As(err, &memoryModels.BlobNotFoundError{})
 }
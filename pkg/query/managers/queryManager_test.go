@@ -0,0 +1,241 @@
+package queryManagers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnit_CheckPermission_ChecksPermissionByQuery(t *testing.T) {
+	qm := &queryManager{}
+	getData := queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: "shop.orders"}
+	createData := queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnData, Name: "shop.orders"}
+	getSysData := queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: "sys.sys_user"}
+	deleteSysData := queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnData, Name: "sys.sys_log"}
+	createSysBlob := queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnBlob, Name: "sys.other"}
+	getLogs := queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnLogs}
+	connection := queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnConnection}
+	truncateBlob := queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: "shop.orders"}
+	truncateSysBlob := queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: "sys.sys_log"}
+
+	expectations := map[string]map[*queryModels.Query]bool{
+		"": {&getData: false, &connection: true},
+		systemConstants.PermissionRead: {
+			&getData: true, &createData: false, &getSysData: false, &getLogs: false, &connection: true, &truncateBlob: false,
+		},
+		systemConstants.PermissionReadWrite: {
+			&getData: true, &createData: true, &getSysData: false, &createSysBlob: false, &getLogs: false, &truncateBlob: true,
+		},
+		systemConstants.PermissionReadSuper: {
+			&getData: true, &createData: false, &truncateBlob: false, &getSysData: true, &deleteSysData: false, &getLogs: true, &truncateSysBlob: false,
+		},
+		systemConstants.PermissionSuper: {
+			&createData: true, &truncateBlob: true, &getSysData: true, &deleteSysData: true, &createSysBlob: true, &getLogs: true, &truncateSysBlob: true,
+		},
+	}
+
+	for permission, queries := range expectations {
+		user := systemModels.User{User: "tester", Permission: permission}
+		for query, expected := range queries {
+			err := qm.checkPermission(*query, user)
+			assert.Equal(t, expected, err == nil, "%s %+v", permission, *query)
+		}
+	}
+}
+
+func TestUnit_BuildFormat_SetsUniqueKeys(t *testing.T) {
+	qm := &queryManager{}
+
+	format, err := qm.buildFormat(queryModels.With{
+		Format: map[string]string{"email": "string", "org": "string", "name": "string"},
+		Unique: [][]string{{"email"}, {"org", "name"}},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, format["email"].Unique)
+	assert.Empty(t, format["email"].UniqueWith)
+	assert.True(t, format["org"].Unique)
+	assert.Equal(t, []string{"name"}, format["org"].UniqueWith)
+	assert.False(t, format["name"].Unique)
+}
+
+func TestUnit_BuildFormat_FailsOnInvalidUniqueKeys(t *testing.T) {
+	qm := &queryManager{}
+	format := map[string]string{"email": "string"}
+
+	for _, unique := range [][][]string{{{}}, {{"missing"}}, {{"email"}, {"email"}}} {
+		_, err := qm.buildFormat(queryModels.With{Format: format, Unique: unique})
+		assert.NotNil(t, err)
+	}
+}
+
+func TestUnit_BuildFormat_SetsOptionalAndDefaultKeys(t *testing.T) {
+	qm := &queryManager{}
+
+	format, err := qm.buildFormat(queryModels.With{
+		Format:   map[string]string{"nick": "string", "active": "bool"},
+		Optional: []string{"nick"},
+		Default:  map[string]any{"active": true},
+	})
+	_, optionalErr := qm.buildFormat(queryModels.With{Format: map[string]string{}, Optional: []string{"nick"}})
+	_, defaultErr := qm.buildFormat(queryModels.With{Format: map[string]string{}, Default: map[string]any{"nick": "a"}})
+
+	assert.Nil(t, err)
+	assert.True(t, format["nick"].Optional)
+	assert.Equal(t, true, format["active"].Default)
+	assert.NotNil(t, optionalErr)
+	assert.NotNil(t, defaultErr)
+}
+
+func TestUnit_BuildFormat_BuildsNestedFormats(t *testing.T) {
+	qm := &queryManager{}
+
+	format, err := qm.buildFormat(queryModels.With{
+		Format: map[string]string{
+			"address":         "object",
+			"address.city":    "string",
+			"address.geo":     "object",
+			"address.geo.lat": "float",
+			"items":           "array<object>",
+			"items.sku":       "string",
+			"tags":            "array<string>",
+		},
+		Optional: []string{"address.geo"},
+		Default:  map[string]any{"address.city": "Paris"},
+	})
+	_, parentErr := qm.buildFormat(queryModels.With{Format: map[string]string{"name": "string", "name.first": "string"}})
+	_, missingErr := qm.buildFormat(queryModels.With{Format: map[string]string{"address.city": "string"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.Format{
+		"address": {KeyType: "object", Format: diskModels.Format{
+			"city": {KeyType: "string", Default: "Paris"},
+			"geo": {KeyType: "object", Optional: true, Format: diskModels.Format{
+				"lat": {KeyType: "float"},
+			}},
+		}},
+		"items": {KeyType: "array<object>", Format: diskModels.Format{
+			"sku": {KeyType: "string"},
+		}},
+		"tags": {KeyType: "array<string>"},
+	}, format)
+	assert.NotNil(t, parentErr)
+	assert.NotNil(t, missingErr)
+}
+
+func TestUnit_BuildFormat_SetsScaleAndValues(t *testing.T) {
+	qm := &queryManager{}
+
+	format, err := qm.buildFormat(queryModels.With{
+		Format: map[string]string{"amount": "decimal", "status": "enum"},
+		Scale:  map[string]int{"amount": 2},
+		Values: map[string][]string{"status": {"new", "paid"}},
+	})
+	_, scaleErr := qm.buildFormat(queryModels.With{Format: map[string]string{}, Scale: map[string]int{"amount": 2}})
+	_, valuesErr := qm.buildFormat(queryModels.With{Format: map[string]string{}, Values: map[string][]string{"status": {"new"}}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, diskModels.FormatItem{KeyType: "decimal", Scale: 2}, format["amount"])
+	assert.Equal(t, diskModels.FormatItem{KeyType: "enum", Values: []string{"new", "paid"}}, format["status"])
+	assert.NotNil(t, scaleErr)
+	assert.NotNil(t, valuesErr)
+}
+
+func TestUnit_CreateSynchronizedQueryManager_DelegatesQueries(t *testing.T) {
+	target := &testQueryManager{}
+	synchronizedQueryManager := CreateSynchronizedQueryManager(target)
+	query := queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "shop"}
+	user := systemModels.User{User: "bob"}
+
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			synchronizedQueryManager.Query(query, user)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+
+	assert.Equal(t, 10, len(target.queries))
+	assert.Equal(t, user, target.users[0])
+}
+
+type overlapQueryManager struct {
+	active    int32
+	maxActive int32
+}
+
+func (oqm *overlapQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	active := atomic.AddInt32(&oqm.active, 1)
+	defer atomic.AddInt32(&oqm.active, -1)
+	for maxActive := atomic.LoadInt32(&oqm.maxActive); active > maxActive; maxActive = atomic.LoadInt32(&oqm.maxActive) {
+		if atomic.CompareAndSwapInt32(&oqm.maxActive, maxActive, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return queryModels.QueryResult{}
+}
+
+func TestUnit_CreateSynchronizedQueryManager_SerializesWrites(t *testing.T) {
+	target := &overlapQueryManager{}
+	synchronizedQueryManager := CreateSynchronizedQueryManager(target)
+
+	var wg sync.WaitGroup
+	for _, query := range []queryModels.Query{
+		{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "shop"},
+		{Action: queryConstants.ActionUpdate, On: queryConstants.OnData, Name: "shop.orders"},
+		{Action: queryConstants.ActionDelete, On: queryConstants.OnData, Name: "shop.orders"},
+		{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: "shop.orders"},
+	} {
+		wg.Add(1)
+		go func(query queryModels.Query) {
+			defer wg.Done()
+			synchronizedQueryManager.Query(query, systemModels.User{User: "bob"})
+		}(query)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), target.maxActive)
+}
+
+func TestUnit_Log_OmitsUserConnection(t *testing.T) {
+	logManager := &testLogManager{}
+	qm := &queryManager{logManager: logManager}
+
+	queryResult := qm.log(queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "shop"}, systemModels.User{User: "root"}, queryModels.QueryResult{})
+
+	assert.Equal(t, "", queryResult.ErrorMessage)
+	queryBytes, err := json.Marshal(logManager.added[0])
+	assert.Nil(t, err)
+	assert.NotContains(t, string(queryBytes), "userConnection")
+}
+
+func TestUnit_Query_FlagsPermissionDenied(t *testing.T) {
+	qm := &queryManager{}
+
+	queryResult := qm.Query(queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnData, Name: "shop.orders"}, systemModels.User{User: "bob", Permission: systemConstants.PermissionRead})
+
+	assert.True(t, queryResult.PermissionDenied)
+	assert.False(t, queryResult.NotFound)
+}
+
+func TestUnit_IsNotFound_MatchesTypedNotFoundErrors(t *testing.T) {
+	assert.True(t, isNotFound(memoryModels.RecordNotFoundError{PageRecordId: "id"}))
+	assert.True(t, isNotFound(memoryModels.DBNotFoundError{DB: "shop"}))
+	assert.True(t, isNotFound(fmt.Errorf("wrapped: %w", memoryModels.BlobNotFoundError{DB: "shop", Blob: "orders"})))
+	assert.False(t, isNotFound(errors.New("db shop does not exist")))
+}
@@ -0,0 +1,53 @@
+package queryManagers
+
+import (
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+)
+
+func Replay(logManager systemManagers.LogManager, target QueryManager, from int, to int) (int, error) {
+	if from < 1 {
+		return 0, fmt.Errorf("replay must start from version 1 or later, got %d", from)
+	}
+	if to != 0 && to < from {
+		return 0, fmt.Errorf("replay version %d is before version %d", to, from)
+	}
+	filterItems := []memoryModels.FilterItem{{
+		Key:   "version",
+		Op:    memoryConstants.OpGreaterEqual,
+		Value: from,
+	}}
+	if to != 0 {
+		filterItems = append(filterItems, memoryModels.FilterItem{
+			Key:   "version",
+			Op:    memoryConstants.OpLessEqual,
+			Value: to,
+		})
+	}
+	logs, err := logManager.GetLogs(filterItems)
+	if err != nil {
+		return 0, err
+	}
+	replayed := 0
+	for _, log := range logs {
+		if log.Version != from+replayed {
+			return replayed, fmt.Errorf("log version %d is missing", from+replayed)
+		}
+		queryResult := target.Query(log.Query, systemModels.User{
+			User:       log.User,
+			Permission: systemConstants.PermissionSuper,
+		})
+		if queryResult.ErrorMessage != "" {
+			return replayed, fmt.Errorf("replay failed on version %d: %s", log.Version, queryResult.ErrorMessage)
+		}
+		replayed++
+	}
+	if to != 0 && from+replayed <= to {
+		return replayed, fmt.Errorf("log version %d is missing", from+replayed)
+	}
+	return replayed, nil
+}
@@ -0,0 +1,110 @@
+package queryManagers
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testLogManager struct {
+	logs        []queryModels.Log
+	filterItems []memoryModels.FilterItem
+	added       []queryModels.Query
+}
+
+func (tlm *testLogManager) AddLog(query queryModels.Query, user systemModels.User) error {
+	tlm.added = append(tlm.added, query)
+	return nil
+}
+
+func (tlm *testLogManager) GetLogs(filterItems []memoryModels.FilterItem) ([]queryModels.Log, error) {
+	tlm.filterItems = filterItems
+	return tlm.logs, nil
+}
+
+func (tlm *testLogManager) GetCurrent() queryModels.Log {
+	return queryModels.Log{}
+}
+
+type testQueryManager struct {
+	queries []queryModels.Query
+	users   []systemModels.User
+	failOn  string
+}
+
+func (tqm *testQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if query.Name == tqm.failOn {
+		return queryModels.QueryResult{ErrorMessage: "failed"}
+	}
+	tqm.queries = append(tqm.queries, query)
+	tqm.users = append(tqm.users, user)
+	return queryModels.QueryResult{}
+}
+
+func createTestLogs(versions ...int) []queryModels.Log {
+	logs := []queryModels.Log{}
+	for _, version := range versions {
+		logs = append(logs, queryModels.Log{
+			Version: version,
+			User:    "bob",
+			Query:   queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: string(rune('a' + version))},
+		})
+	}
+	return logs
+}
+
+func TestUnit_Replay_ReplaysLogsInOrderAsLoggedUser(t *testing.T) {
+	logManager := &testLogManager{logs: createTestLogs(2, 3, 4)}
+	target := &testQueryManager{}
+
+	replayed, err := Replay(logManager, target, 2, 4)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, replayed)
+	assert.Equal(t, 2, len(logManager.filterItems))
+	assert.Equal(t, []string{"c", "d", "e"}, []string{target.queries[0].Name, target.queries[1].Name, target.queries[2].Name})
+	assert.Equal(t, "bob", target.users[0].User)
+	assert.Equal(t, systemConstants.PermissionSuper, target.users[0].Permission)
+}
+
+func TestUnit_Replay_ReplaysToLatestWithoutUpperVersion(t *testing.T) {
+	logManager := &testLogManager{logs: createTestLogs(1, 2)}
+
+	replayed, err := Replay(logManager, &testQueryManager{}, 1, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, replayed)
+	assert.Equal(t, 1, len(logManager.filterItems))
+}
+
+func TestUnit_Replay_FailsOnMissingVersion(t *testing.T) {
+	gapped, gapErr := Replay(&testLogManager{logs: createTestLogs(1, 3)}, &testQueryManager{}, 1, 3)
+	short, shortErr := Replay(&testLogManager{logs: createTestLogs(1, 2)}, &testQueryManager{}, 1, 3)
+
+	assert.NotNil(t, gapErr)
+	assert.Equal(t, 1, gapped)
+	assert.NotNil(t, shortErr)
+	assert.Equal(t, 2, short)
+}
+
+func TestUnit_Replay_StopsOnQueryError(t *testing.T) {
+	target := &testQueryManager{failOn: "c"}
+
+	replayed, err := Replay(&testLogManager{logs: createTestLogs(1, 2, 3)}, target, 1, 0)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Equal(t, 1, len(target.queries))
+}
+
+func TestUnit_Replay_FailsOnInvalidRange(t *testing.T) {
+	_, fromErr := Replay(&testLogManager{}, &testQueryManager{}, 0, 0)
+	_, toErr := Replay(&testLogManager{}, &testQueryManager{}, 3, 2)
+
+	assert.NotNil(t, fromErr)
+	assert.NotNil(t, toErr)
+}
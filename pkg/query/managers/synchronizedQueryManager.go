@@ -0,0 +1,31 @@
+package queryManagers
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"sync"
+)
+
+type synchronizedQueryManager struct {
+	m            *sync.RWMutex
+	queryManager QueryManager
+}
+
+func CreateSynchronizedQueryManager(queryManager QueryManager) QueryManager {
+	return &synchronizedQueryManager{
+		m:            &sync.RWMutex{},
+		queryManager: queryManager,
+	}
+}
+
+func (sqm *synchronizedQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if query.Action == queryConstants.ActionGet || query.On == queryConstants.OnConnection {
+		sqm.m.RLock()
+		defer sqm.m.RUnlock()
+	} else {
+		sqm.m.Lock()
+		defer sqm.m.Unlock()
+	}
+	return sqm.queryManager.Query(query, user)
+}
@@ -15,20 +15,39 @@ type Query struct {
 }
 
 type With struct {
-	Format          map[string]string            `json:"format,omitempty"`
-	Partition       []string                     `json:"partition,omitempty"`
-	UpdateRecord    diskModels.PageRecord        `json:"updateRecord,omitempty"`
-	Records         []diskModels.PageRecord      `json:"records,omitempty"`
-	Index           string                       `json:"index,omitempty"`
-	SearchPartition memoryModels.SearchPartition `json:"searchPartition,omitempty"`
-	Filter          []memoryModels.FilterItem    `json:"filter,omitempty"`
-	UserConnection  systemModels.UserConnection  `json:"userConnection,omitempty"`
+	Format           map[string]string                     `json:"format,omitempty"`
+	Partition        []string                              `json:"partition,omitempty"`
+	PartitionBuckets map[string]diskModels.PartitionBucket `json:"partitionBuckets,omitempty"`
+	Unique           [][]string                            `json:"unique,omitempty"`
+	Optional         []string                              `json:"optional,omitempty"`
+	Default          map[string]any                        `json:"default,omitempty"`
+	Scale            map[string]int                        `json:"scale,omitempty"`
+	Values           map[string][]string                   `json:"values,omitempty"`
+	Alter            *diskModels.Alter                     `json:"alter,omitempty"`
+	UpdateRecord     diskModels.PageRecord                 `json:"updateRecord,omitempty"`
+	Records          []diskModels.PageRecord               `json:"records,omitempty"`
+	Index            string                                `json:"index,omitempty"`
+	SearchPartition  memoryModels.SearchPartition          `json:"searchPartition,omitempty"`
+	Filter           []memoryModels.FilterItem             `json:"filter,omitempty"`
+	Limit            int                                   `json:"limit,omitempty"`
+	Offset           int                                   `json:"offset,omitempty"`
+	Sort             memoryModels.SortItems                `json:"sort,omitempty"`
+	Fields           []string                              `json:"fields,omitempty"`
+	ExcludeFields    []string                              `json:"excludeFields,omitempty"`
+	TimeZone         string                                `json:"timeZone,omitempty"`
+	Aggregates       []memoryModels.AggregateItem          `json:"aggregates,omitempty"`
+	GroupBy          []string                              `json:"groupBy,omitempty"`
+	Key              string                                `json:"key,omitempty"`
+	IndexType        string                                `json:"indexType,omitempty"`
+	UserConnection   *systemModels.UserConnection          `json:"userConnection,omitempty"`
 }
 
 type QueryResult struct {
-	Records        []diskModels.PageRecord `json:"records,omitempty"`
-	ConnectionUser systemModels.User       `json:"connectionUser,omitempty"`
-	ErrorMessage   string                  `json:"errorMessage,omitempty"`
+	Records          []diskModels.PageRecord `json:"records,omitempty"`
+	ConnectionUser   systemModels.User       `json:"connectionUser,omitempty"`
+	ErrorMessage     string                  `json:"errorMessage,omitempty"`
+	NotFound         bool                    `json:"-"`
+	PermissionDenied bool                    `json:"-"`
 }
 
 type NameSplit struct {
@@ -37,15 +56,19 @@ type NameSplit struct {
 }
 
 type Log struct {
-	Id      string
-	Version int
-	Query   Query
+	Id        string
+	Version   int
+	Timestamp string
+	User      string
+	Query     Query
 }
 
 func (l *Log) ConvertToPageRecord() diskModels.PageRecord {
 	return diskModels.PageRecord{
 		memoryConstants.IdKey: l.Id,
 		"version":             l.Version,
+		"timestamp":           l.Timestamp,
+		"user":                l.User,
 		"query":               l.Query,
 	}
 }
@@ -0,0 +1,26 @@
+package queryModels
+
+import (
+	"encoding/json"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnit_Query_DecodesLegacySortString(t *testing.T) {
+	query := Query{}
+
+	err := json.Unmarshal([]byte(`{"action":"get","on":"data","name":"db.b","with":{"sort":"qty"}}`), &query)
+
+	assert.Nil(t, err)
+	assert.Equal(t, memoryModels.SortItems{{Key: "qty"}}, query.With.Sort)
+}
+
+func TestUnit_Query_DecodesSortItems(t *testing.T) {
+	query := Query{}
+
+	err := json.Unmarshal([]byte(`{"action":"get","on":"data","name":"db.b","with":{"sort":[{"key":"qty","direction":"desc"}]}}`), &query)
+
+	assert.Nil(t, err)
+	assert.Equal(t, memoryModels.SortItems{{Key: "qty", Direction: "desc"}}, query.With.Sort)
+}
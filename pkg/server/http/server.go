@@ -0,0 +1,506 @@
+package httpServer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const SessionTTL = 24 * time.Hour
+
+type session struct {
+	user      systemModels.User
+	expiresAt time.Time
+}
+
+type SessionResult struct {
+	Token          string            `json:"token"`
+	ExpiresAt      time.Time         `json:"expiresAt"`
+	ConnectionUser systemModels.User `json:"connectionUser"`
+}
+
+type routeError struct {
+	status  int
+	message string
+}
+
+func (re *routeError) Error() string {
+	return re.message
+}
+
+type Server struct {
+	m            sync.Mutex
+	queryManager queryManagers.QueryManager
+	sessions     map[string]session
+	nowFunc      func() time.Time
+}
+
+func CreateServer(queryManager queryManagers.QueryManager) *Server {
+	return &Server{
+		queryManager: queryManager,
+		sessions:     make(map[string]session),
+		nowFunc:      time.Now,
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if segments[0] == "sessions" && len(segments) == 1 {
+		s.handleSessions(w, r)
+		return
+	}
+	user, err := s.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="nimydb"`)
+		s.writeResult(w, http.StatusUnauthorized, queryModels.QueryResult{ErrorMessage: err.Error()})
+		return
+	}
+	query, err := s.buildQuery(r, segments)
+	if err != nil {
+		var routeErr *routeError
+		if errors.As(err, &routeErr) {
+			s.writeResult(w, routeErr.status, queryModels.QueryResult{ErrorMessage: routeErr.message})
+			return
+		}
+		s.writeResult(w, http.StatusBadRequest, queryModels.QueryResult{ErrorMessage: err.Error()})
+		return
+	}
+	queryResult := s.queryManager.Query(query, user)
+	s.writeResult(w, s.getStatus(query, &queryResult), queryResult)
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		userConnection := systemModels.UserConnection{}
+		if name, password, ok := r.BasicAuth(); ok {
+			userConnection.User = name
+			userConnection.Password = password
+		} else if err := json.NewDecoder(r.Body).Decode(&userConnection); err != nil {
+			s.writeResult(w, http.StatusBadRequest, queryModels.QueryResult{ErrorMessage: fmt.Sprintf("body could not be parsed: %s", err.Error())})
+			return
+		}
+		user, err := s.connect(userConnection)
+		if err != nil {
+			s.writeResult(w, http.StatusUnauthorized, queryModels.QueryResult{ErrorMessage: err.Error()})
+			return
+		}
+		token, expiresAt, err := s.createSession(user)
+		if err != nil {
+			s.writeResult(w, http.StatusInternalServerError, queryModels.QueryResult{ErrorMessage: err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(SessionResult{
+			Token:          token,
+			ExpiresAt:      expiresAt,
+			ConnectionUser: user,
+		})
+	case http.MethodDelete:
+		token, ok := s.getBearerToken(r)
+		if !ok {
+			s.writeResult(w, http.StatusUnauthorized, queryModels.QueryResult{ErrorMessage: "bearer token is required"})
+			return
+		}
+		s.m.Lock()
+		delete(s.sessions, token)
+		s.m.Unlock()
+		s.writeResult(w, http.StatusOK, queryModels.QueryResult{})
+	default:
+		s.writeResult(w, http.StatusMethodNotAllowed, queryModels.QueryResult{ErrorMessage: fmt.Sprintf("method %s not allowed on sessions", r.Method)})
+	}
+}
+
+func (s *Server) authenticate(r *http.Request) (systemModels.User, error) {
+	if name, password, ok := r.BasicAuth(); ok {
+		return s.connect(systemModels.UserConnection{User: name, Password: password})
+	}
+	token, ok := s.getBearerToken(r)
+	if !ok {
+		return systemModels.User{}, errors.New("authentication is required")
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	userSession, ok := s.sessions[token]
+	if !ok {
+		return systemModels.User{}, errors.New("session does not exist")
+	}
+	if s.nowFunc().After(userSession.expiresAt) {
+		delete(s.sessions, token)
+		return systemModels.User{}, errors.New("session has expired")
+	}
+	return userSession.user, nil
+}
+
+func (s *Server) connect(userConnection systemModels.UserConnection) (systemModels.User, error) {
+	queryResult := s.queryManager.Query(queryModels.Query{
+		Action: queryConstants.ActionCreate,
+		On:     queryConstants.OnConnection,
+		With:   queryModels.With{UserConnection: &userConnection},
+	}, systemModels.User{})
+	if queryResult.ErrorMessage != "" {
+		return systemModels.User{}, errors.New(queryResult.ErrorMessage)
+	}
+	return queryResult.ConnectionUser, nil
+}
+
+func (s *Server) createSession(user systemModels.User) (string, time.Time, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	now := s.nowFunc()
+	s.m.Lock()
+	defer s.m.Unlock()
+	for existingToken, userSession := range s.sessions {
+		if now.After(userSession.expiresAt) {
+			delete(s.sessions, existingToken)
+		}
+	}
+	expiresAt := now.Add(SessionTTL)
+	s.sessions[token] = session{
+		user:      user,
+		expiresAt: expiresAt,
+	}
+	return token, expiresAt, nil
+}
+
+func (s *Server) getBearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+func (s *Server) buildQuery(r *http.Request, segments []string) (queryModels.Query, error) {
+	switch {
+	case len(segments) == 1 && segments[0] == "dbs":
+		switch r.Method {
+		case http.MethodGet:
+			return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnDBs}, nil
+		case http.MethodPost:
+			body := struct {
+				Name string `json:"name"`
+			}{}
+			if err := s.decodeBody(r, &body); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: body.Name}, nil
+		}
+	case len(segments) == 2 && segments[0] == "dbs":
+		if r.Method == http.MethodDelete {
+			return queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnDB, Name: segments[1]}, nil
+		}
+	case len(segments) == 3 && segments[0] == "dbs" && segments[2] == "blobs":
+		switch r.Method {
+		case http.MethodGet:
+			return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnBlobs, Name: segments[1]}, nil
+		case http.MethodPost:
+			body := struct {
+				Name string `json:"name"`
+				queryModels.With
+			}{}
+			if err := s.decodeBody(r, &body); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{
+				Action: queryConstants.ActionCreate,
+				On:     queryConstants.OnBlob,
+				Name:   fmt.Sprintf("%s.%s", segments[1], body.Name),
+				With: queryModels.With{
+					Format:           body.Format,
+					Partition:        body.Partition,
+					PartitionBuckets: body.PartitionBuckets,
+					Unique:           body.Unique,
+					Optional:         body.Optional,
+					Default:          body.Default,
+					Scale:            body.Scale,
+					Values:           body.Values,
+				},
+			}, nil
+		}
+	case len(segments) == 4 && segments[0] == "dbs" && segments[2] == "blobs":
+		switch r.Method {
+		case http.MethodDelete:
+			return queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnBlob, Name: s.getBlobName(segments)}, nil
+		case http.MethodPatch:
+			alter := diskModels.Alter{}
+			if err := s.decodeBody(r, &alter); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{
+				Action: queryConstants.ActionUpdate,
+				On:     queryConstants.OnBlob,
+				Name:   s.getBlobName(segments),
+				With:   queryModels.With{Alter: &alter},
+			}, nil
+		}
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "indexes":
+		if r.Method == http.MethodPost {
+			with := queryModels.With{}
+			if err := s.decodeBody(r, &with); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{
+				Action: queryConstants.ActionCreate,
+				On:     queryConstants.OnIndex,
+				Name:   s.getBlobName(segments),
+				With:   queryModels.With{Key: with.Key, IndexType: with.IndexType},
+			}, nil
+		}
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "partitions":
+		switch r.Method {
+		case http.MethodGet:
+			return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnPartitions, Name: s.getBlobName(segments)}, nil
+		case http.MethodDelete:
+			with := queryModels.With{}
+			if err := s.decodeBody(r, &with); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{
+				Action: queryConstants.ActionDelete,
+				On:     queryConstants.OnPartition,
+				Name:   s.getBlobName(segments),
+				With:   queryModels.With{SearchPartition: with.SearchPartition},
+			}, nil
+		}
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "alter":
+		if r.Method == http.MethodGet {
+			return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnAlter, Name: s.getBlobName(segments)}, nil
+		}
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "truncate":
+		if r.Method == http.MethodPost {
+			return queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: s.getBlobName(segments)}, nil
+		}
+	case len(segments) == 6 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "indexes":
+		if r.Method == http.MethodDelete {
+			return queryModels.Query{
+				Action: queryConstants.ActionDelete,
+				On:     queryConstants.OnIndex,
+				Name:   s.getBlobName(segments),
+				With:   queryModels.With{Key: segments[5]},
+			}, nil
+		}
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "records":
+		return s.buildRecordsQuery(r, segments)
+	case len(segments) == 6 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "records":
+		return s.buildRecordQuery(r, segments)
+	case len(segments) == 5 && segments[0] == "dbs" && segments[2] == "blobs" && segments[4] == "aggregate":
+		if r.Method == http.MethodPost {
+			with := queryModels.With{}
+			if err := s.decodeBody(r, &with); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{
+				Action: queryConstants.ActionGet,
+				On:     queryConstants.OnAggregate,
+				Name:   s.getBlobName(segments),
+				With: queryModels.With{
+					Aggregates:      with.Aggregates,
+					GroupBy:         with.GroupBy,
+					Filter:          with.Filter,
+					SearchPartition: with.SearchPartition,
+				},
+			}, nil
+		}
+	case len(segments) == 1 && (segments[0] == "logs" || segments[0] == "users"):
+		if r.Method == http.MethodGet {
+			with := queryModels.With{}
+			if err := s.decodeQueryParam(r, "filter", &with.Filter); err != nil {
+				return queryModels.Query{}, err
+			}
+			return queryModels.Query{Action: queryConstants.ActionGet, On: segments[0], With: with}, nil
+		}
+	default:
+		return queryModels.Query{}, &routeError{status: http.StatusNotFound, message: fmt.Sprintf("route %s does not exist", r.URL.Path)}
+	}
+	return queryModels.Query{}, &routeError{status: http.StatusMethodNotAllowed, message: fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path)}
+}
+
+func (s *Server) buildRecordsQuery(r *http.Request, segments []string) (queryModels.Query, error) {
+	name := s.getBlobName(segments)
+	switch r.Method {
+	case http.MethodGet:
+		with, err := s.getReadParams(r)
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		if err = s.decodeQueryParam(r, "filter", &with.Filter); err != nil {
+			return queryModels.Query{}, err
+		}
+		if err = s.decodeQueryParam(r, "searchPartition", &with.SearchPartition); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: name, With: with}, nil
+	case http.MethodPost:
+		with := queryModels.With{}
+		if err := s.decodeBody(r, &with); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{
+			Action: queryConstants.ActionCreate,
+			On:     queryConstants.OnData,
+			Name:   name,
+			With:   queryModels.With{Records: with.Records},
+		}, nil
+	case http.MethodPatch:
+		with := queryModels.With{}
+		if err := s.decodeBody(r, &with); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{
+			Action: queryConstants.ActionUpdate,
+			On:     queryConstants.OnData,
+			Name:   name,
+			With: queryModels.With{
+				UpdateRecord:    with.UpdateRecord,
+				Filter:          with.Filter,
+				SearchPartition: with.SearchPartition,
+			},
+		}, nil
+	case http.MethodDelete:
+		with := queryModels.With{}
+		if r.ContentLength != 0 {
+			if err := s.decodeBody(r, &with); err != nil {
+				return queryModels.Query{}, err
+			}
+		}
+		return queryModels.Query{
+			Action: queryConstants.ActionDelete,
+			On:     queryConstants.OnData,
+			Name:   name,
+			With: queryModels.With{
+				Filter:          with.Filter,
+				SearchPartition: with.SearchPartition,
+			},
+		}, nil
+	}
+	return queryModels.Query{}, &routeError{status: http.StatusMethodNotAllowed, message: fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path)}
+}
+
+func (s *Server) buildRecordQuery(r *http.Request, segments []string) (queryModels.Query, error) {
+	name := s.getBlobName(segments)
+	index := segments[5]
+	switch r.Method {
+	case http.MethodGet:
+		with, err := s.getReadParams(r)
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		with.Index = index
+		return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: name, With: with}, nil
+	case http.MethodPatch:
+		updateRecord := diskModels.PageRecord{}
+		if err := s.decodeBody(r, &updateRecord); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{
+			Action: queryConstants.ActionUpdate,
+			On:     queryConstants.OnData,
+			Name:   name,
+			With:   queryModels.With{Index: index, UpdateRecord: updateRecord},
+		}, nil
+	case http.MethodDelete:
+		return queryModels.Query{
+			Action: queryConstants.ActionDelete,
+			On:     queryConstants.OnData,
+			Name:   name,
+			With:   queryModels.With{Index: index},
+		}, nil
+	}
+	return queryModels.Query{}, &routeError{status: http.StatusMethodNotAllowed, message: fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path)}
+}
+
+func (s *Server) getReadParams(r *http.Request) (queryModels.With, error) {
+	with := queryModels.With{}
+	values := r.URL.Query()
+	for param, target := range map[string]*int{"limit": &with.Limit, "offset": &with.Offset} {
+		if value := values.Get(param); value != "" {
+			converted, err := strconv.Atoi(value)
+			if err != nil {
+				return with, fmt.Errorf("%s %s is not a number", param, value)
+			}
+			*target = converted
+		}
+	}
+	if value := values.Get("sort"); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			key, direction, _ := strings.Cut(item, ":")
+			with.Sort = append(with.Sort, memoryModels.SortItem{Key: key, Direction: direction})
+		}
+	}
+	if value := values.Get("fields"); value != "" {
+		with.Fields = strings.Split(value, ",")
+	}
+	if value := values.Get("excludeFields"); value != "" {
+		with.ExcludeFields = strings.Split(value, ",")
+	}
+	with.TimeZone = values.Get("tz")
+	return with, nil
+}
+
+func (s *Server) decodeBody(r *http.Request, target any) error {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		return fmt.Errorf("body could not be parsed: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *Server) decodeQueryParam(r *http.Request, param string, target any) error {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), target); err != nil {
+		return fmt.Errorf("%s could not be parsed: %s", param, err.Error())
+	}
+	return nil
+}
+
+func (s *Server) getBlobName(segments []string) string {
+	return fmt.Sprintf("%s.%s", segments[1], segments[3])
+}
+
+func (s *Server) getStatus(query queryModels.Query, queryResult *queryModels.QueryResult) int {
+	if queryResult.ErrorMessage != "" {
+		switch {
+		case queryResult.PermissionDenied:
+			return http.StatusForbidden
+		case queryResult.NotFound:
+			return http.StatusNotFound
+		default:
+			return http.StatusBadRequest
+		}
+	}
+	if query.Action == queryConstants.ActionGet && query.With.Index != "" {
+		if len(queryResult.Records) == 0 || len(queryResult.Records[0]) == 0 {
+			queryResult.Records = nil
+			queryResult.ErrorMessage = fmt.Sprintf("record with %s %s not found", memoryConstants.IdKey, query.With.Index)
+			return http.StatusNotFound
+		}
+	}
+	if query.Action == queryConstants.ActionCreate {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (s *Server) writeResult(w http.ResponseWriter, status int, queryResult queryModels.QueryResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(queryResult)
+}
@@ -0,0 +1,209 @@
+package httpServer
+
+import (
+	"encoding/json"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testQueryManager struct {
+	queries []queryModels.Query
+	result  queryModels.QueryResult
+}
+
+func (tqm *testQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if query.On == queryConstants.OnConnection {
+		if query.With.UserConnection.Password != "secret" {
+			return queryModels.QueryResult{ErrorMessage: "authentication failed on user " + query.With.UserConnection.User}
+		}
+		return queryModels.QueryResult{ConnectionUser: systemModels.User{User: query.With.UserConnection.User, Permission: "rw"}}
+	}
+	tqm.queries = append(tqm.queries, query)
+	return tqm.result
+}
+
+func sendTestRequest(server *Server, method string, target string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.SetBasicAuth("bob", "secret")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestUnit_ServeHTTP_MapsRoutesToQueries(t *testing.T) {
+	queryManager := &testQueryManager{}
+	server := CreateServer(queryManager)
+
+	sendTestRequest(server, http.MethodPost, "/dbs", `{"name":"shop"}`)
+	sendTestRequest(server, http.MethodPost, "/dbs/shop/blobs", `{"name":"orders","format":{"qty":"int","price":"decimal","status":"enum"},"partition":["qty"],"unique":[["qty"]],"optional":["qty"],"default":{"qty":0},"scale":{"price":2},"values":{"status":["new"]},"partitionBuckets":{"qty":{"type":"range","bounds":[10]}}}`)
+	sendTestRequest(server, http.MethodGet, `/dbs/shop/blobs/orders/records?limit=5&offset=2&sort=qty:desc,_id&fields=qty&tz=Europe/Paris&filter=[{"key":"qty","op":">","value":1}]`, "")
+	sendTestRequest(server, http.MethodPost, "/dbs/shop/blobs/orders/records", `{"records":[{"qty":1}]}`)
+	sendTestRequest(server, http.MethodPatch, "/dbs/shop/blobs/orders/records/abc", `{"qty":2}`)
+	sendTestRequest(server, http.MethodDelete, "/dbs/shop/blobs/orders/records", `{"filter":[{"key":"qty","op":"=","value":2}]}`)
+	sendTestRequest(server, http.MethodPost, "/dbs/shop/blobs/orders/aggregate", `{"aggregates":[{"op":"count"}],"groupBy":["qty"]}`)
+	sendTestRequest(server, http.MethodDelete, "/dbs/shop", "")
+	sendTestRequest(server, http.MethodPost, "/dbs/shop/blobs/orders/indexes", `{"key":"qty","indexType":"hash"}`)
+	sendTestRequest(server, http.MethodDelete, "/dbs/shop/blobs/orders/indexes/qty", "")
+	sendTestRequest(server, http.MethodPatch, "/dbs/shop/blobs/orders", `{"op":"rename","key":"qty","newKey":"quantity"}`)
+	sendTestRequest(server, http.MethodGet, "/dbs/shop/blobs/orders/partitions", "")
+	sendTestRequest(server, http.MethodDelete, "/dbs/shop/blobs/orders/partitions", `{"searchPartition":{"qty":5}}`)
+	sendTestRequest(server, http.MethodPost, "/dbs/shop/blobs/orders/truncate", "")
+
+	queries := queryManager.queries
+	assert.Equal(t, 14, len(queries))
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: "shop"}, queries[0])
+	assert.Equal(t, "shop.orders", queries[1].Name)
+	assert.Equal(t, map[string]string{"qty": "int", "price": "decimal", "status": "enum"}, queries[1].With.Format)
+	assert.Equal(t, []string{"qty"}, queries[1].With.Partition)
+	assert.Equal(t, [][]string{{"qty"}}, queries[1].With.Unique)
+	assert.Equal(t, []string{"qty"}, queries[1].With.Optional)
+	assert.Equal(t, map[string]any{"qty": float64(0)}, queries[1].With.Default)
+	assert.Equal(t, map[string]int{"price": 2}, queries[1].With.Scale)
+	assert.Equal(t, map[string][]string{"status": {"new"}}, queries[1].With.Values)
+	assert.Equal(t, map[string]diskModels.PartitionBucket{"qty": {Type: "range", Bounds: []float64{10}}}, queries[1].With.PartitionBuckets)
+	assert.Equal(t, queryConstants.ActionGet, queries[2].Action)
+	assert.Equal(t, 5, queries[2].With.Limit)
+	assert.Equal(t, 2, queries[2].With.Offset)
+	assert.Equal(t, "desc", queries[2].With.Sort[0].Direction)
+	assert.Equal(t, "_id", queries[2].With.Sort[1].Key)
+	assert.Equal(t, []string{"qty"}, queries[2].With.Fields)
+	assert.Equal(t, "Europe/Paris", queries[2].With.TimeZone)
+	assert.Equal(t, ">", queries[2].With.Filter[0].Op)
+	assert.Equal(t, queryConstants.ActionCreate, queries[3].Action)
+	assert.Equal(t, 1, len(queries[3].With.Records))
+	assert.Equal(t, "abc", queries[4].With.Index)
+	assert.Equal(t, diskModels.PageRecord{"qty": float64(2)}, queries[4].With.UpdateRecord)
+	assert.Equal(t, queryConstants.ActionDelete, queries[5].Action)
+	assert.Equal(t, "=", queries[5].With.Filter[0].Op)
+	assert.Equal(t, queryConstants.OnAggregate, queries[6].On)
+	assert.Equal(t, []string{"qty"}, queries[6].With.GroupBy)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnDB, Name: "shop"}, queries[7])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "qty", IndexType: "hash"}}, queries[8])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "qty"}}, queries[9])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionUpdate, On: queryConstants.OnBlob, Name: "shop.orders", With: queryModels.With{Alter: &diskModels.Alter{Op: "rename", Key: "qty", NewKey: "quantity"}}}, queries[10])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnPartitions, Name: "shop.orders"}, queries[11])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnPartition, Name: "shop.orders", With: queryModels.With{SearchPartition: memoryModels.SearchPartition{"qty": float64(5)}}}, queries[12])
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: "shop.orders"}, queries[13])
+}
+
+func TestUnit_ServeHTTP_MapsErrorsToStatusCodes(t *testing.T) {
+	expectations := []struct {
+		method           string
+		target           string
+		body             string
+		errorMessage     string
+		notFound         bool
+		permissionDenied bool
+		status           int
+	}{
+		{http.MethodPost, "/dbs/shop/blobs/orders/records", `{"records":[]}`, "user bob cannot create data shop.orders", false, true, http.StatusForbidden},
+		{http.MethodPost, "/dbs/other/blobs/orders/records", `{"records":[]}`, "db other does not exist", true, false, http.StatusNotFound},
+		{http.MethodGet, "/dbs/shop/blobs/items/records", "", "blob items does not exist in db shop", true, false, http.StatusNotFound},
+		{http.MethodGet, "/dbs/shop/blobs/orders/records/abc", "", "record with id abc not found in page page.json", false, false, http.StatusBadRequest},
+		{http.MethodPatch, "/dbs/shop/blobs/orders/records/missing", `{"qty":2}`, "record with id missing does not exist", true, false, http.StatusNotFound},
+		{http.MethodDelete, "/dbs/shop/blobs/orders/records/missing", "", "record with id missing does not exist", true, false, http.StatusNotFound},
+		{http.MethodGet, "/dbs/shop/blobs/orders/records?sort=unknown", "", "sort key unknown does not exist in format", false, false, http.StatusBadRequest},
+		{http.MethodPost, "/dbs/shop/blobs/orders/records", `{"records":[{"unknown":1}]}`, "key unknown does not exist in orders", false, false, http.StatusBadRequest},
+		{http.MethodGet, `/dbs/shop/blobs/orders/records?filter=[{"key":"unknown","op":"=","value":1}]`, "", "filter key unknown does not exist", false, false, http.StatusBadRequest},
+		{http.MethodGet, "/dbs/shop/blobs/orders/records?limit=-1", "", "limit -1 cannot be negative", false, false, http.StatusBadRequest},
+	}
+
+	for _, expectation := range expectations {
+		queryManager := &testQueryManager{
+			result: queryModels.QueryResult{ErrorMessage: expectation.errorMessage, NotFound: expectation.notFound, PermissionDenied: expectation.permissionDenied},
+		}
+		server := CreateServer(queryManager)
+		recorder := sendTestRequest(server, expectation.method, expectation.target, expectation.body)
+		var queryResult queryModels.QueryResult
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &queryResult))
+		assert.Equal(t, expectation.status, recorder.Code, expectation.errorMessage)
+		assert.Equal(t, expectation.errorMessage, queryResult.ErrorMessage)
+		assert.Equal(t, 1, len(queryManager.queries), expectation.errorMessage)
+	}
+}
+
+func TestUnit_ServeHTTP_ReturnsStatusForRouting(t *testing.T) {
+	server := CreateServer(&testQueryManager{})
+
+	created := sendTestRequest(server, http.MethodPost, "/dbs", `{"name":"shop"}`)
+	ok := sendTestRequest(server, http.MethodGet, "/dbs", "")
+	notFound := sendTestRequest(server, http.MethodGet, "/unknown", "")
+	notAllowed := sendTestRequest(server, http.MethodPut, "/dbs", "")
+	badBody := sendTestRequest(server, http.MethodPost, "/dbs", `{"name":`)
+	badLimit := sendTestRequest(server, http.MethodGet, "/dbs/shop/blobs/orders/records?limit=many", "")
+	missingRecord := sendTestRequest(server, http.MethodGet, "/dbs/shop/blobs/orders/records/abc", "")
+
+	assert.Equal(t, http.StatusCreated, created.Code)
+	assert.Equal(t, http.StatusOK, ok.Code)
+	assert.Equal(t, http.StatusNotFound, notFound.Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, notAllowed.Code)
+	assert.Equal(t, http.StatusBadRequest, badBody.Code)
+	assert.Equal(t, http.StatusBadRequest, badLimit.Code)
+	assert.Equal(t, http.StatusNotFound, missingRecord.Code)
+}
+
+func TestUnit_ServeHTTP_RequiresAuthentication(t *testing.T) {
+	server := CreateServer(&testQueryManager{})
+	missing := httptest.NewRecorder()
+	server.ServeHTTP(missing, httptest.NewRequest(http.MethodGet, "/dbs", nil))
+	wrongPassword := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/dbs", nil)
+	request.SetBasicAuth("bob", "wrong")
+	server.ServeHTTP(wrongPassword, request)
+
+	assert.Equal(t, http.StatusUnauthorized, missing.Code)
+	assert.NotEmpty(t, missing.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, wrongPassword.Code)
+}
+
+func TestUnit_ServeHTTP_AuthenticatesWithBearerSession(t *testing.T) {
+	queryManager := &testQueryManager{}
+	server := CreateServer(queryManager)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.nowFunc = func() time.Time { return now }
+	sessionRecorder := httptest.NewRecorder()
+	server.ServeHTTP(sessionRecorder, httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(`{"user":"bob","password":"secret"}`)))
+	var sessionResult SessionResult
+	assert.Nil(t, json.Unmarshal(sessionRecorder.Body.Bytes(), &sessionResult))
+
+	sendWithToken := func(method string, target string) int {
+		request := httptest.NewRequest(method, target, nil)
+		request.Header.Set("Authorization", "Bearer "+sessionResult.Token)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, sessionRecorder.Code)
+	assert.Equal(t, "bob", sessionResult.ConnectionUser.User)
+	assert.Equal(t, http.StatusOK, sendWithToken(http.MethodGet, "/dbs"))
+	now = now.Add(SessionTTL + time.Second)
+	assert.Equal(t, http.StatusUnauthorized, sendWithToken(http.MethodGet, "/dbs"))
+}
+
+func TestUnit_ServeHTTP_DeletesSession(t *testing.T) {
+	server := CreateServer(&testQueryManager{})
+	token, _, err := server.createSession(systemModels.User{User: "bob"})
+	assert.Nil(t, err)
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, "/sessions", nil)
+	deleteRequest.Header.Set("Authorization", "Bearer "+token)
+	deleteRecorder := httptest.NewRecorder()
+	server.ServeHTTP(deleteRecorder, deleteRequest)
+	getRequest := httptest.NewRequest(http.MethodGet, "/dbs", nil)
+	getRequest.Header.Set("Authorization", "Bearer "+token)
+	getRecorder := httptest.NewRecorder()
+	server.ServeHTTP(getRecorder, getRequest)
+
+	assert.Equal(t, http.StatusOK, deleteRecorder.Code)
+	assert.Equal(t, http.StatusUnauthorized, getRecorder.Code)
+}
@@ -0,0 +1,141 @@
+package tcpServer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"net"
+	"sync"
+)
+
+const MaxQuerySize = 64 * 1024 * 1024
+
+type session struct {
+	user *systemModels.User
+}
+
+type Server struct {
+	m            sync.Mutex
+	wg           sync.WaitGroup
+	queryManager queryManagers.QueryManager
+	listener     net.Listener
+	connections  map[net.Conn]struct{}
+	closed       bool
+}
+
+func CreateServer(queryManager queryManagers.QueryManager) *Server {
+	return &Server{
+		queryManager: queryManager,
+		connections:  make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		_ = listener.Close()
+		return errors.New("server is closed")
+	}
+	s.listener = listener
+	s.m.Unlock()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.m.Lock()
+			closed := s.closed
+			s.m.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.m.Lock()
+		s.connections[conn] = struct{}{}
+		s.wg.Add(1)
+		s.m.Unlock()
+		go s.handleConnection(conn)
+	}
+}
+
+func (s *Server) Close() error {
+	s.m.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.m.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) handleConnection(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.m.Lock()
+		delete(s.connections, conn)
+		s.m.Unlock()
+		_ = conn.Close()
+	}()
+	connectionSession := &session{}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxQuerySize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var queryResult queryModels.QueryResult
+		var query queryModels.Query
+		if err := json.Unmarshal(line, &query); err != nil {
+			queryResult = queryModels.QueryResult{
+				ErrorMessage: fmt.Sprintf("query could not be parsed: %s", err.Error()),
+			}
+		} else {
+			queryResult = s.runQuery(query, connectionSession)
+		}
+		if err := encoder.Encode(queryResult); err != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = encoder.Encode(queryModels.QueryResult{
+			ErrorMessage: fmt.Sprintf("connection closed: %s", err.Error()),
+		})
+	}
+}
+
+func (s *Server) runQuery(query queryModels.Query, connectionSession *session) queryModels.QueryResult {
+	if query.Action == queryConstants.ActionCreate && query.On == queryConstants.OnConnection {
+		queryResult := s.queryManager.Query(query, systemModels.User{})
+		if queryResult.ErrorMessage == "" {
+			connectionUser := queryResult.ConnectionUser
+			connectionSession.user = &connectionUser
+		}
+		return queryResult
+	}
+	if connectionSession.user == nil {
+		return queryModels.QueryResult{
+			ErrorMessage: "connection is not authenticated",
+		}
+	}
+	return s.queryManager.Query(query, *connectionSession.user)
+}
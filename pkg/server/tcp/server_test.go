@@ -0,0 +1,141 @@
+package tcpServer
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testQueryManager struct {
+	m         sync.Mutex
+	users     []systemModels.User
+	delay     time.Duration
+	active    int32
+	maxActive int32
+}
+
+func (tqm *testQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	if query.On == queryConstants.OnConnection {
+		if query.With.UserConnection.Password != "secret" {
+			return queryModels.QueryResult{ErrorMessage: "authentication failed"}
+		}
+		return queryModels.QueryResult{ConnectionUser: systemModels.User{User: query.With.UserConnection.User, Permission: "r"}}
+	}
+	active := atomic.AddInt32(&tqm.active, 1)
+	defer atomic.AddInt32(&tqm.active, -1)
+	for maxActive := atomic.LoadInt32(&tqm.maxActive); active > maxActive; maxActive = atomic.LoadInt32(&tqm.maxActive) {
+		if atomic.CompareAndSwapInt32(&tqm.maxActive, maxActive, active) {
+			break
+		}
+	}
+	time.Sleep(tqm.delay)
+	tqm.m.Lock()
+	tqm.users = append(tqm.users, user)
+	tqm.m.Unlock()
+	return queryModels.QueryResult{Records: []diskModels.PageRecord{{"name": query.Name}}}
+}
+
+func startTestServer(t *testing.T, queryManager queryManagers.QueryManager) (*Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := CreateServer(queryManager)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return server, listener.Addr().String()
+}
+
+func sendTestLine(t *testing.T, conn net.Conn, reader *bufio.Reader, line string) queryModels.QueryResult {
+	_, err := conn.Write([]byte(line + "\n"))
+	assert.Nil(t, err)
+	response, err := reader.ReadBytes('\n')
+	assert.Nil(t, err)
+	var queryResult queryModels.QueryResult
+	assert.Nil(t, json.Unmarshal(response, &queryResult))
+	return queryResult
+}
+
+func TestUnit_Serve_RequiresAuthenticationPerConnection(t *testing.T) {
+	queryManager := &testQueryManager{}
+	server, address := startTestServer(t, queryManager)
+	defer server.Close()
+	conn, err := net.Dial("tcp", address)
+	assert.Nil(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	unauthenticated := sendTestLine(t, conn, reader, `{"action":"get","on":"data","name":"shop.orders"}`)
+	failed := sendTestLine(t, conn, reader, `{"action":"create","on":"connection","with":{"userConnection":{"user":"bob","password":"wrong"}}}`)
+	connected := sendTestLine(t, conn, reader, `{"action":"create","on":"connection","with":{"userConnection":{"user":"bob","password":"secret"}}}`)
+	authenticated := sendTestLine(t, conn, reader, `{"action":"get","on":"data","name":"shop.orders"}`)
+
+	assert.Equal(t, "connection is not authenticated", unauthenticated.ErrorMessage)
+	assert.Equal(t, "authentication failed", failed.ErrorMessage)
+	assert.Equal(t, "bob", connected.ConnectionUser.User)
+	assert.Equal(t, "", authenticated.ErrorMessage)
+	assert.Equal(t, "shop.orders", authenticated.Records[0]["name"])
+	assert.Equal(t, []systemModels.User{{User: "bob", Permission: "r"}}, queryManager.users)
+}
+
+func TestUnit_Serve_ReturnsErrorOnMalformedQuery(t *testing.T) {
+	server, address := startTestServer(t, &testQueryManager{})
+	defer server.Close()
+	conn, err := net.Dial("tcp", address)
+	assert.Nil(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	malformed := sendTestLine(t, conn, reader, `{"action":`)
+	connected := sendTestLine(t, conn, reader, `{"action":"create","on":"connection","with":{"userConnection":{"user":"bob","password":"secret"}}}`)
+
+	assert.Contains(t, malformed.ErrorMessage, "query could not be parsed")
+	assert.Equal(t, "", connected.ErrorMessage)
+}
+
+func TestUnit_Serve_SerializesWritesAcrossConnections(t *testing.T) {
+	queryManager := &testQueryManager{delay: 5 * time.Millisecond}
+	server, address := startTestServer(t, queryManagers.CreateSynchronizedQueryManager(queryManager))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		conn, err := net.Dial("tcp", address)
+		assert.Nil(t, err)
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		sendTestLine(t, conn, reader, `{"action":"create","on":"connection","with":{"userConnection":{"user":"bob","password":"secret"}}}`)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendTestLine(t, conn, reader, `{"action":"create","on":"db","name":"shop"}`)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 5, len(queryManager.users))
+	assert.Equal(t, int32(1), queryManager.maxActive)
+}
+
+func TestUnit_Close_ClosesOpenConnections(t *testing.T) {
+	server, address := startTestServer(t, &testQueryManager{})
+	conn, err := net.Dial("tcp", address)
+	assert.Nil(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	sendTestLine(t, conn, reader, `{"action":"get","on":"dbs"}`)
+
+	assert.Nil(t, server.Close())
+	_, err = reader.ReadBytes('\n')
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,82 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	tokenWord   = "word"
+	tokenString = "string"
+	tokenNumber = "number"
+	tokenSymbol = "symbol"
+)
+
+type token struct {
+	kind  string
+	value string
+	pos   int
+}
+
+func (t token) is(value string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, value) || t.kind == tokenSymbol && t.value == value
+}
+
+func tokenize(input string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			value, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_.-", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i]), pos: start})
+		case strings.ContainsRune("!<>", r) && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{kind: tokenSymbol, value: string(runes[i : i+2]), pos: i})
+			i += 2
+		case strings.ContainsRune("=<>(),:*", r):
+			tokens = append(tokens, token{kind: tokenSymbol, value: string(r), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func readQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	builder := strings.Builder{}
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == quote || runes[i+1] == '\\') {
+			i++
+			builder.WriteRune(runes[i])
+			continue
+		}
+		if runes[i] == quote {
+			return builder.String(), i + 1, nil
+		}
+		builder.WriteRune(runes[i])
+	}
+	return "", 0, fmt.Errorf("unterminated string starting at position %d", start)
+}
@@ -0,0 +1,849 @@
+package shell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type parser struct {
+	payload string
+	tokens  []token
+	pos     int
+}
+
+func Parse(input string) (queryModels.Query, error) {
+	payload := ""
+	if fields := strings.Fields(input); len(fields) > 0 && strings.EqualFold(fields[0], "insert") {
+		if index := strings.IndexAny(input, "{["); index >= 0 {
+			payload = strings.TrimSpace(input[index:])
+			input = input[:index]
+		}
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	if len(tokens) == 0 {
+		return queryModels.Query{}, errors.New("empty query")
+	}
+	p := &parser{payload: payload, tokens: tokens}
+	query, err := p.parseQuery()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	if !p.done() {
+		return queryModels.Query{}, fmt.Errorf("unexpected %s at position %d", p.peek().value, p.peek().pos)
+	}
+	return query, nil
+}
+
+func (p *parser) parseQuery() (queryModels.Query, error) {
+	command, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	switch strings.ToLower(command) {
+	case "login":
+		return p.parseLogin()
+	case "create":
+		return p.parseCreate()
+	case "insert":
+		return p.parseInsert()
+	case "get":
+		return p.parseGet()
+	case "aggregate":
+		return p.parseAggregate()
+	case "update":
+		return p.parseUpdate()
+	case "alter":
+		return p.parseAlter()
+	case "delete":
+		return p.parseDelete()
+	case queryConstants.ActionTruncate:
+		return p.parseTruncate()
+	default:
+		return queryModels.Query{}, fmt.Errorf("unknown command %s", command)
+	}
+}
+
+func (p *parser) parseLogin() (queryModels.Query, error) {
+	user, err := p.expectValueString()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	password, err := p.expectValueString()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	return queryModels.Query{
+		Action: queryConstants.ActionCreate,
+		On:     queryConstants.OnConnection,
+		With:   queryModels.With{UserConnection: &systemModels.UserConnection{User: user, Password: password}},
+	}, nil
+}
+
+func (p *parser) parseCreate() (queryModels.Query, error) {
+	on, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	switch strings.ToLower(on) {
+	case queryConstants.OnDB:
+		return queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnDB, Name: name}, nil
+	case queryConstants.OnBlob:
+		format := map[string]string{}
+		var unique [][]string
+		var optional []string
+		var defaults map[string]any
+		var scales map[string]int
+		var values map[string][]string
+		err = p.parseList(func() error {
+			key, err := p.expectWord()
+			if err != nil {
+				return err
+			}
+			if err = p.expect(":"); err != nil {
+				return err
+			}
+			keyType, err := p.expectKeyType()
+			if err != nil {
+				return err
+			}
+			format[key] = keyType
+			scale, enumValues, err := p.parseTypeParams(keyType)
+			if err != nil {
+				return err
+			}
+			if scale != 0 {
+				if scales == nil {
+					scales = map[string]int{}
+				}
+				scales[key] = scale
+			}
+			if enumValues != nil {
+				if values == nil {
+					values = map[string][]string{}
+				}
+				values[key] = enumValues
+			}
+			for {
+				switch {
+				case p.accept("unique"):
+					unique = append(unique, []string{key})
+				case p.accept("optional"):
+					optional = append(optional, key)
+				case p.accept("default"):
+					value, err := p.parseValue()
+					if err != nil {
+						return err
+					}
+					if defaults == nil {
+						defaults = map[string]any{}
+					}
+					defaults[key] = value
+				default:
+					return nil
+				}
+			}
+		})
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		var partition []string
+		var buckets map[string]diskModels.PartitionBucket
+		for {
+			switch {
+			case p.accept("partition"):
+				err = p.parseList(func() error {
+					key, err := p.expectWord()
+					if err != nil {
+						return err
+					}
+					partition = append(partition, key)
+					bucket, ok, err := p.parsePartitionBucket()
+					if ok {
+						if buckets == nil {
+							buckets = map[string]diskModels.PartitionBucket{}
+						}
+						buckets[key] = bucket
+					}
+					return err
+				})
+			case p.accept("unique"):
+				var uniqueKeys []string
+				err = p.parseList(func() error {
+					key, err := p.expectWord()
+					uniqueKeys = append(uniqueKeys, key)
+					return err
+				})
+				unique = append(unique, uniqueKeys)
+			default:
+				return queryModels.Query{
+					Action: queryConstants.ActionCreate,
+					On:     queryConstants.OnBlob,
+					Name:   name,
+					With: queryModels.With{
+						Format:           format,
+						Partition:        partition,
+						PartitionBuckets: buckets,
+						Unique:           unique,
+						Optional:         optional,
+						Default:          defaults,
+						Scale:            scales,
+						Values:           values,
+					},
+				}, nil
+			}
+			if err != nil {
+				return queryModels.Query{}, err
+			}
+		}
+	case queryConstants.OnIndex:
+		key, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		indexType := ""
+		if !p.done() {
+			if indexType, err = p.expectWord(); err != nil {
+				return queryModels.Query{}, err
+			}
+		}
+		return queryModels.Query{
+			Action: queryConstants.ActionCreate,
+			On:     queryConstants.OnIndex,
+			Name:   name,
+			With:   queryModels.With{Key: key, IndexType: strings.ToLower(indexType)},
+		}, nil
+	default:
+		return queryModels.Query{}, fmt.Errorf("cannot create %s", on)
+	}
+}
+
+func (p *parser) parseInsert() (queryModels.Query, error) {
+	p.accept("into")
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	if p.payload == "" {
+		return queryModels.Query{}, errors.New("expected records as a JSON object or array")
+	}
+	records := []diskModels.PageRecord{}
+	if strings.HasPrefix(p.payload, "{") {
+		record := diskModels.PageRecord{}
+		if err = json.Unmarshal([]byte(p.payload), &record); err != nil {
+			return queryModels.Query{}, fmt.Errorf("records could not be parsed: %s", err.Error())
+		}
+		records = append(records, record)
+	} else if err = json.Unmarshal([]byte(p.payload), &records); err != nil {
+		return queryModels.Query{}, fmt.Errorf("records could not be parsed: %s", err.Error())
+	}
+	return queryModels.Query{
+		Action: queryConstants.ActionCreate,
+		On:     queryConstants.OnData,
+		Name:   name,
+		With:   queryModels.With{Records: records},
+	}, nil
+}
+
+func (p *parser) parseGet() (queryModels.Query, error) {
+	target, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	switch strings.ToLower(target) {
+	case queryConstants.OnDBs:
+		return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnDBs}, nil
+	case queryConstants.OnBlobs:
+		db, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnBlobs, Name: db}, nil
+	case queryConstants.OnPartitions, queryConstants.OnAlter:
+		name, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionGet, On: strings.ToLower(target), Name: name}, nil
+	case queryConstants.OnLogs, queryConstants.OnUsers:
+		with := queryModels.With{}
+		if err = p.parseClauses(&with, "where"); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionGet, On: strings.ToLower(target), With: with}, nil
+	}
+	with := queryModels.With{}
+	if err = p.parseClauses(&with, "id", "where", "partition", "sort", "limit", "offset", "fields", "exclude", "zone"); err != nil {
+		return queryModels.Query{}, err
+	}
+	return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnData, Name: target, With: with}, nil
+}
+
+func (p *parser) parseAggregate() (queryModels.Query, error) {
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	with := queryModels.With{}
+	err = p.parseCommaSeparated(func() error {
+		op, err := p.expectWord()
+		if err != nil {
+			return err
+		}
+		aggregateItem := memoryModels.AggregateItem{Op: strings.ToLower(op)}
+		if err = p.expect("("); err != nil {
+			return err
+		}
+		if !p.accept(")") {
+			p.accept("*")
+			if !p.peek().is(")") {
+				if aggregateItem.Key, err = p.expectWord(); err != nil {
+					return err
+				}
+			}
+			if err = p.expect(")"); err != nil {
+				return err
+			}
+		}
+		if p.accept("as") {
+			if aggregateItem.As, err = p.expectWord(); err != nil {
+				return err
+			}
+		}
+		with.Aggregates = append(with.Aggregates, aggregateItem)
+		return nil
+	})
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	if err = p.parseClauses(&with, "where", "partition", "group"); err != nil {
+		return queryModels.Query{}, err
+	}
+	return queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnAggregate, Name: name, With: with}, nil
+}
+
+func (p *parser) parseUpdate() (queryModels.Query, error) {
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	with := queryModels.With{UpdateRecord: diskModels.PageRecord{}}
+	if err = p.parseClauses(&with, "id"); err != nil {
+		return queryModels.Query{}, err
+	}
+	if err = p.expect("set"); err != nil {
+		return queryModels.Query{}, err
+	}
+	err = p.parseCommaSeparated(func() error {
+		key, err := p.expectWord()
+		if err != nil {
+			return err
+		}
+		if err = p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		with.UpdateRecord[key] = value
+		return err
+	})
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	if with.Index == "" {
+		if err = p.parseClauses(&with, "where", "partition"); err != nil {
+			return queryModels.Query{}, err
+		}
+	}
+	return queryModels.Query{Action: queryConstants.ActionUpdate, On: queryConstants.OnData, Name: name, With: with}, nil
+}
+
+func (p *parser) parseAlter() (queryModels.Query, error) {
+	if err := p.expect(queryConstants.OnBlob); err != nil {
+		return queryModels.Query{}, err
+	}
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	op, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	key, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	alter := diskModels.Alter{Op: strings.ToLower(op), Key: key}
+	switch alter.Op {
+	case memoryConstants.AlterAdd:
+		if err = p.expect(":"); err != nil {
+			return queryModels.Query{}, err
+		}
+		if alter.KeyType, err = p.expectKeyType(); err != nil {
+			return queryModels.Query{}, err
+		}
+		if alter.Scale, alter.Values, err = p.parseTypeParams(alter.KeyType); err != nil {
+			return queryModels.Query{}, err
+		}
+		alter.Optional = p.accept("optional")
+		if p.accept("default") {
+			if alter.Default, err = p.parseValue(); err != nil {
+				return queryModels.Query{}, err
+			}
+		}
+	case memoryConstants.AlterDrop, memoryConstants.AlterUnique:
+	case memoryConstants.AlterRename:
+		if err = p.expect("to"); err != nil {
+			return queryModels.Query{}, err
+		}
+		if alter.NewKey, err = p.expectWord(); err != nil {
+			return queryModels.Query{}, err
+		}
+	case memoryConstants.AlterType:
+		if alter.KeyType, err = p.expectKeyType(); err != nil {
+			return queryModels.Query{}, err
+		}
+	default:
+		return queryModels.Query{}, fmt.Errorf("cannot alter blob with %s", op)
+	}
+	return queryModels.Query{
+		Action: queryConstants.ActionUpdate,
+		On:     queryConstants.OnBlob,
+		Name:   name,
+		With:   queryModels.With{Alter: &alter},
+	}, nil
+}
+
+func (p *parser) parseTruncate() (queryModels.Query, error) {
+	if err := p.expect(queryConstants.OnBlob); err != nil {
+		return queryModels.Query{}, err
+	}
+	name, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	return queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: name}, nil
+}
+
+func (p *parser) parseDelete() (queryModels.Query, error) {
+	target, err := p.expectWord()
+	if err != nil {
+		return queryModels.Query{}, err
+	}
+	switch strings.ToLower(target) {
+	case queryConstants.OnDB, queryConstants.OnBlob:
+		name, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionDelete, On: strings.ToLower(target), Name: name}, nil
+	case queryConstants.OnIndex:
+		name, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		key, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnIndex, Name: name, With: queryModels.With{Key: key}}, nil
+	case queryConstants.OnPartition:
+		name, err := p.expectWord()
+		if err != nil {
+			return queryModels.Query{}, err
+		}
+		with := queryModels.With{}
+		if err = p.parseSearchPartition(&with); err != nil {
+			return queryModels.Query{}, err
+		}
+		return queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnPartition, Name: name, With: with}, nil
+	}
+	with := queryModels.With{}
+	if err = p.parseClauses(&with, "id", "where", "partition"); err != nil {
+		return queryModels.Query{}, err
+	}
+	return queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnData, Name: target, With: with}, nil
+}
+
+func (p *parser) parseSearchPartition(with *queryModels.With) error {
+	with.SearchPartition = memoryModels.SearchPartition{}
+	return p.parseList(func() error {
+		key, err := p.expectWord()
+		if err != nil {
+			return err
+		}
+		if err = p.expect("="); err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		with.SearchPartition[key] = value
+		return err
+	})
+}
+
+func (p *parser) parseClauses(with *queryModels.With, allowed ...string) error {
+	for !p.done() {
+		clause := strings.ToLower(p.peek().value)
+		if p.peek().kind != tokenWord || !slices.Contains(allowed, clause) {
+			return nil
+		}
+		p.pos++
+		var err error
+		switch clause {
+		case "id":
+			with.Index, err = p.expectValueString()
+		case "where":
+			var filterItem memoryModels.FilterItem
+			if filterItem, err = p.parseOr(); err == nil {
+				if filterItem.Op == memoryConstants.FilterAnd {
+					with.Filter = append(with.Filter, filterItem.Items...)
+				} else {
+					with.Filter = append(with.Filter, filterItem)
+				}
+			}
+		case "partition":
+			err = p.parseSearchPartition(with)
+		case "sort", "group":
+			if err = p.expect("by"); err != nil {
+				return err
+			}
+			err = p.parseCommaSeparated(func() error {
+				key, err := p.expectWord()
+				if err != nil {
+					return err
+				}
+				if clause == "group" {
+					with.GroupBy = append(with.GroupBy, key)
+					return nil
+				}
+				sortItem := memoryModels.SortItem{Key: key}
+				if p.accept(memoryConstants.SortAsc) {
+					sortItem.Direction = memoryConstants.SortAsc
+				} else if p.accept(memoryConstants.SortDesc) {
+					sortItem.Direction = memoryConstants.SortDesc
+				}
+				with.Sort = append(with.Sort, sortItem)
+				return nil
+			})
+		case "limit":
+			with.Limit, err = p.expectInt()
+		case "offset":
+			with.Offset, err = p.expectInt()
+		case "zone":
+			with.TimeZone, err = p.expectValueString()
+		case "fields", "exclude":
+			err = p.parseCommaSeparated(func() error {
+				key, err := p.expectWord()
+				if clause == "fields" {
+					with.Fields = append(with.Fields, key)
+				} else {
+					with.ExcludeFields = append(with.ExcludeFields, key)
+				}
+				return err
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (memoryModels.FilterItem, error) {
+	return p.parseJoined(memoryConstants.FilterOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (memoryModels.FilterItem, error) {
+	return p.parseJoined(memoryConstants.FilterAnd, p.parseNot)
+}
+
+func (p *parser) parseJoined(op string, parseNext func() (memoryModels.FilterItem, error)) (memoryModels.FilterItem, error) {
+	filterItem, err := parseNext()
+	if err != nil {
+		return memoryModels.FilterItem{}, err
+	}
+	items := []memoryModels.FilterItem{filterItem}
+	for p.accept(op) {
+		if filterItem, err = parseNext(); err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		items = append(items, filterItem)
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+	return memoryModels.FilterItem{Op: op, Items: items}, nil
+}
+
+func (p *parser) parseNot() (memoryModels.FilterItem, error) {
+	if p.accept(memoryConstants.FilterNot) {
+		filterItem, err := p.parseNot()
+		if err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		return memoryModels.FilterItem{Op: memoryConstants.FilterNot, Items: []memoryModels.FilterItem{filterItem}}, nil
+	}
+	if p.accept("(") {
+		filterItem, err := p.parseOr()
+		if err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		return filterItem, p.expect(")")
+	}
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (memoryModels.FilterItem, error) {
+	key, err := p.expectWord()
+	if err != nil {
+		return memoryModels.FilterItem{}, err
+	}
+	if p.done() {
+		return memoryModels.FilterItem{}, fmt.Errorf("expected operator after %s", key)
+	}
+	next := p.next()
+	op := strings.ToUpper(next.value)
+	switch {
+	case next.kind == tokenSymbol:
+		if !slices.Contains([]string{memoryConstants.OpEqual, memoryConstants.OpNotEqual, memoryConstants.OpGreater, memoryConstants.OpGreaterEqual, memoryConstants.OpLess, memoryConstants.OpLessEqual}, op) {
+			return memoryModels.FilterItem{}, fmt.Errorf("unexpected %s at position %d", next.value, next.pos)
+		}
+	case op == "IS":
+		op = memoryConstants.OpIsNull
+		if p.accept("not") {
+			op = memoryConstants.OpIsNotNull
+		}
+		if err = p.expect("null"); err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		return memoryModels.FilterItem{Key: key, Op: op}, nil
+	case op == "NOT":
+		if err = p.expect("in"); err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		op = memoryConstants.OpNotIn
+	}
+	switch op {
+	case memoryConstants.OpIn, memoryConstants.OpNotIn, memoryConstants.OpAny:
+		values := []any{}
+		err = p.parseList(func() error {
+			value, err := p.parseValue()
+			values = append(values, value)
+			return err
+		})
+		return memoryModels.FilterItem{Key: key, Op: op, Value: values}, err
+	case memoryConstants.OpBetween:
+		low, err := p.parseValue()
+		if err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		if err = p.expect("and"); err != nil {
+			return memoryModels.FilterItem{}, err
+		}
+		high, err := p.parseValue()
+		return memoryModels.FilterItem{Key: key, Op: op, Value: []any{low, high}}, err
+	}
+	value, err := p.parseValue()
+	return memoryModels.FilterItem{Key: key, Op: op, Value: value}, err
+}
+
+func (p *parser) parseValue() (any, error) {
+	if p.done() {
+		return nil, errors.New("expected a value")
+	}
+	next := p.next()
+	switch next.kind {
+	case tokenString:
+		return next.value, nil
+	case tokenNumber:
+		if value, err := strconv.Atoi(next.value); err == nil {
+			return value, nil
+		}
+		value, err := strconv.ParseFloat(next.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", next.value)
+		}
+		return value, nil
+	case tokenWord:
+		switch strings.ToLower(next.value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return next.value, nil
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", next.value, next.pos)
+}
+
+func (p *parser) parseList(parseItem func() error) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if err := p.parseCommaSeparated(parseItem); err != nil {
+		return err
+	}
+	return p.expect(")")
+}
+
+func (p *parser) parseCommaSeparated(parseItem func() error) error {
+	for {
+		if err := parseItem(); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	next := p.peek()
+	p.pos++
+	return next
+}
+
+func (p *parser) accept(value string) bool {
+	if !p.done() && p.peek().is(value) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(value string) error {
+	if p.done() {
+		return fmt.Errorf("expected %s at end of query", value)
+	}
+	if !p.accept(value) {
+		return fmt.Errorf("expected %s at position %d but found %s", value, p.peek().pos, p.peek().value)
+	}
+	return nil
+}
+
+func (p *parser) expectWord() (string, error) {
+	if p.done() {
+		return "", errors.New("unexpected end of query")
+	}
+	if p.peek().kind != tokenWord {
+		return "", fmt.Errorf("expected a name at position %d but found %s", p.peek().pos, p.peek().value)
+	}
+	return p.next().value, nil
+}
+
+func (p *parser) expectKeyType() (string, error) {
+	keyType, err := p.expectWord()
+	if err != nil {
+		return "", err
+	}
+	keyType = strings.ToLower(keyType)
+	if !p.accept("<") {
+		return keyType, nil
+	}
+	elementType, err := p.expectKeyType()
+	if err != nil {
+		return "", err
+	}
+	return keyType + "<" + elementType + ">", p.expect(">")
+}
+
+func (p *parser) parseTypeParams(keyType string) (int, []string, error) {
+	if !p.peek().is("(") {
+		return 0, nil, nil
+	}
+	switch memoryConstants.GetBaseType(keyType) {
+	case memoryConstants.Decimal:
+		p.next()
+		scale, err := p.expectInt()
+		if err != nil {
+			return 0, nil, err
+		}
+		return scale, nil, p.expect(")")
+	case memoryConstants.Enum:
+		values := []string{}
+		err := p.parseList(func() error {
+			value, err := p.expectValueString()
+			values = append(values, value)
+			return err
+		})
+		return 0, values, err
+	default:
+		return 0, nil, fmt.Errorf("type %s does not take parameters", keyType)
+	}
+}
+
+func (p *parser) parsePartitionBucket() (diskModels.PartitionBucket, bool, error) {
+	switch {
+	case p.accept(memoryConstants.PartitionRange):
+		bucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionRange}
+		err := p.parseList(func() error {
+			bound, err := p.expectFloat()
+			bucket.Bounds = append(bucket.Bounds, bound)
+			return err
+		})
+		return bucket, true, err
+	case p.accept(memoryConstants.PartitionTime):
+		bucket := diskModels.PartitionBucket{Type: memoryConstants.PartitionTime}
+		err := p.parseList(func() error {
+			period, err := p.expectWord()
+			bucket.Period = strings.ToLower(period)
+			return err
+		})
+		return bucket, true, err
+	default:
+		return diskModels.PartitionBucket{}, false, nil
+	}
+}
+
+func (p *parser) expectValueString() (string, error) {
+	if p.done() {
+		return "", errors.New("unexpected end of query")
+	}
+	if p.peek().kind == tokenSymbol {
+		return "", fmt.Errorf("unexpected %s at position %d", p.peek().value, p.peek().pos)
+	}
+	return p.next().value, nil
+}
+
+func (p *parser) expectFloat() (float64, error) {
+	if p.done() || p.peek().kind != tokenNumber {
+		return 0, errors.New("expected a number")
+	}
+	return strconv.ParseFloat(p.next().value, 64)
+}
+
+func (p *parser) expectInt() (int, error) {
+	if p.done() || p.peek().kind != tokenNumber {
+		return 0, errors.New("expected a number")
+	}
+	return strconv.Atoi(p.next().value)
+}
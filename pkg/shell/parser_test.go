@@ -0,0 +1,156 @@
+package shell
+
+import (
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnit_Parse_ParsesGetWithClauses(t *testing.T) {
+	query, err := Parse("get shop.orders where qty > 1 and (status = 'a' or status in ('b', 'c')) sort by qty desc limit 5 offset 2 fields qty, status zone 'Europe/Paris'")
+
+	assert.Nil(t, err)
+	assert.Equal(t, queryConstants.ActionGet, query.Action)
+	assert.Equal(t, queryConstants.OnData, query.On)
+	assert.Equal(t, "shop.orders", query.Name)
+	assert.Equal(t, []memoryModels.FilterItem{
+		{Key: "qty", Op: ">", Value: 1},
+		{Op: memoryConstants.FilterOr, Items: []memoryModels.FilterItem{
+			{Key: "status", Op: "=", Value: "a"},
+			{Key: "status", Op: "IN", Value: []any{"b", "c"}},
+		}},
+	}, query.With.Filter)
+	assert.Equal(t, memoryModels.SortItems{{Key: "qty", Direction: "desc"}}, query.With.Sort)
+	assert.Equal(t, 5, query.With.Limit)
+	assert.Equal(t, 2, query.With.Offset)
+	assert.Equal(t, []string{"qty", "status"}, query.With.Fields)
+	assert.Equal(t, "Europe/Paris", query.With.TimeZone)
+}
+
+func TestUnit_Parse_ParsesConditionOperators(t *testing.T) {
+	query, err := Parse("get shop.orders where a is not null and b between 1 and 3 and not c like 'x%'")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []memoryModels.FilterItem{
+		{Key: "a", Op: "IS NOT NULL"},
+		{Key: "b", Op: "BETWEEN", Value: []any{1, 3}},
+		{Op: memoryConstants.FilterNot, Items: []memoryModels.FilterItem{{Key: "c", Op: "LIKE", Value: "x%"}}},
+	}, query.With.Filter)
+}
+
+func TestUnit_Parse_ParsesStatements(t *testing.T) {
+	login, _ := Parse("login bob secret")
+	createBlob, _ := Parse("create blob shop.orders (status:string, qty:int unique default 1, code:string optional) partition (status) unique (code, status)")
+	insert, _ := Parse(`insert into shop.orders {"qty":1}`)
+	aggregate, _ := Parse("aggregate shop.orders count(*), sum(qty) as total group by status")
+	update, _ := Parse("update shop.orders id abc set qty = 9")
+	deleteDB, _ := Parse("delete db shop")
+	createIndex, _ := Parse("create index shop.orders status HASH")
+	deleteIndex, _ := Parse("delete index shop.orders status")
+	getPartitions, _ := Parse("get partitions shop.orders")
+	getAlter, _ := Parse("get alter shop.orders")
+	dropPartition, _ := Parse("delete partition shop.orders (status = 'new')")
+	truncate, _ := Parse("truncate blob shop.orders")
+
+	assert.Equal(t, queryConstants.OnConnection, login.On)
+	assert.Equal(t, "secret", login.With.UserConnection.Password)
+	assert.Equal(t, map[string]string{"status": "string", "qty": "int", "code": "string"}, createBlob.With.Format)
+	assert.Equal(t, []string{"status"}, createBlob.With.Partition)
+	assert.Equal(t, [][]string{{"qty"}, {"code", "status"}}, createBlob.With.Unique)
+	assert.Equal(t, []string{"code"}, createBlob.With.Optional)
+	assert.Equal(t, map[string]any{"qty": 1}, createBlob.With.Default)
+	assert.Equal(t, []diskModels.PageRecord{{"qty": float64(1)}}, insert.With.Records)
+	assert.Equal(t, queryConstants.OnAggregate, aggregate.On)
+	assert.Equal(t, []memoryModels.AggregateItem{{Op: "count"}, {Op: "sum", Key: "qty", As: "total"}}, aggregate.With.Aggregates)
+	assert.Equal(t, []string{"status"}, aggregate.With.GroupBy)
+	assert.Equal(t, "abc", update.With.Index)
+	assert.Equal(t, diskModels.PageRecord{"qty": 9}, update.With.UpdateRecord)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnDB, Name: "shop"}, deleteDB)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionCreate, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "status", IndexType: "hash"}}, createIndex)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnIndex, Name: "shop.orders", With: queryModels.With{Key: "status"}}, deleteIndex)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnPartitions, Name: "shop.orders"}, getPartitions)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionGet, On: queryConstants.OnAlter, Name: "shop.orders"}, getAlter)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionDelete, On: queryConstants.OnPartition, Name: "shop.orders", With: queryModels.With{SearchPartition: memoryModels.SearchPartition{"status": "new"}}}, dropPartition)
+	assert.Equal(t, queryModels.Query{Action: queryConstants.ActionTruncate, On: queryConstants.OnBlob, Name: "shop.orders"}, truncate)
+}
+
+func TestUnit_Parse_ParsesPartitionBuckets(t *testing.T) {
+	query, err := Parse("create blob shop.events (region:string, qty:int, created:datetime) partition (region, qty range(-10, 100.5), created time(DAY))")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"region", "qty", "created"}, query.With.Partition)
+	assert.Equal(t, map[string]diskModels.PartitionBucket{
+		"qty":     {Type: memoryConstants.PartitionRange, Bounds: []float64{-10, 100.5}},
+		"created": {Type: memoryConstants.PartitionTime, Period: memoryConstants.PeriodDay},
+	}, query.With.PartitionBuckets)
+}
+
+func TestUnit_Parse_ReturnsErrors(t *testing.T) {
+	inputs := []string{
+		"select * from orders",
+		"create blob shop.events (qty:int) partition (qty range(low))",
+		"get shop.orders where qty ==",
+		"get shop.orders where (qty = 1",
+		"get shop.orders limit many",
+		"get shop.orders where name = 'open",
+		`insert into shop.orders {"qty":`,
+	}
+
+	for _, input := range inputs {
+		_, err := Parse(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestUnit_Parse_ParsesAlterBlob(t *testing.T) {
+	add, _ := Parse("alter blob shop.orders add note:string optional default 'none'")
+	drop, _ := Parse("alter blob shop.orders drop note")
+	rename, _ := Parse("alter blob shop.orders rename qty to quantity")
+	widen, _ := Parse("alter blob shop.orders type qty FLOAT")
+	unique, _ := Parse("alter blob shop.orders unique code")
+	_, err := Parse("alter blob shop.orders move qty")
+
+	assert.Equal(t, queryConstants.ActionUpdate, add.Action)
+	assert.Equal(t, queryConstants.OnBlob, add.On)
+	assert.Equal(t, &diskModels.Alter{Op: "add", Key: "note", KeyType: "string", Optional: true, Default: "none"}, add.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "drop", Key: "note"}, drop.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "rename", Key: "qty", NewKey: "quantity"}, rename.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "type", Key: "qty", KeyType: "float"}, widen.With.Alter)
+	assert.Equal(t, &diskModels.Alter{Op: "unique", Key: "code"}, unique.With.Alter)
+	assert.NotNil(t, err)
+}
+
+func TestUnit_Parse_ParsesNestedTypesAndOperators(t *testing.T) {
+	createBlob, err := Parse("create blob shop.events (tags:Array<String>, address:object, address.city:string optional)")
+	get, getErr := Parse("get shop.events where tags any ('a', 'b') and tags contains 'c' and address.city = 'Paris'")
+	_, typeErr := Parse("create blob shop.events (tags:array<string)")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"tags": "array<string>", "address": "object", "address.city": "string"}, createBlob.With.Format)
+	assert.Equal(t, []string{"address.city"}, createBlob.With.Optional)
+	assert.Nil(t, getErr)
+	assert.Equal(t, []memoryModels.FilterItem{
+		{Key: "tags", Op: memoryConstants.OpAny, Value: []any{"a", "b"}},
+		{Key: "tags", Op: memoryConstants.OpContains, Value: "c"},
+		{Key: "address.city", Op: memoryConstants.OpEqual, Value: "Paris"},
+	}, get.With.Filter)
+	assert.NotNil(t, typeErr)
+}
+
+func TestUnit_Parse_ParsesTypeParams(t *testing.T) {
+	createBlob, err := Parse("create blob shop.orders (id:uuid, amount:decimal(2), status:enum('new', paid), data:bytes optional)")
+	add, addErr := Parse("alter blob shop.orders add fees:array<decimal>(3) optional")
+	_, paramErr := Parse("create blob shop.orders (qty:int(2))")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"id": "uuid", "amount": "decimal", "status": "enum", "data": "bytes"}, createBlob.With.Format)
+	assert.Equal(t, map[string]int{"amount": 2}, createBlob.With.Scale)
+	assert.Equal(t, map[string][]string{"status": {"new", "paid"}}, createBlob.With.Values)
+	assert.Nil(t, addErr)
+	assert.Equal(t, &diskModels.Alter{Op: "add", Key: "fees", KeyType: "array<decimal>", Scale: 3, Optional: true}, add.With.Alter)
+	assert.NotNil(t, paramErr)
+}
@@ -0,0 +1,237 @@
+package shell
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const Prompt = "nimydb> "
+
+const helpText = `commands:
+  login <user> <password>
+  create db <db>
+  create blob <db.blob> (<key>:<type> [unique] [optional] [default <value>], ...) [partition (<key>, ...)] [unique (<key>, ...)]
+      partition keys as <key> | <key> range(<bound>, ...) | <key> time(day|month)
+      types: string int float bool date datetime uuid bytes decimal(<scale>) enum(<value>, ...) object array<type>
+      nested keys as <key>.<key>:<type>
+  create index <db.blob> <key> [hash|ordered|text]
+  insert into <db.blob> <json object or array>
+  get dbs | get blobs <db> | get partitions <db.blob> | get alter <db.blob> | get logs [where ...] | get users [where ...]
+  get <db.blob> [id <id>] [where ...] [partition (<key>=<value>, ...)] [sort by <key> [asc|desc], ...]
+      [limit <n>] [offset <n>] [fields <key>, ...] [exclude <key>, ...] [zone '<time zone>']
+  aggregate <db.blob> count(), sum(<key>) [as <name>], ... [where ...] [partition (...)] [group by <key>, ...]
+  update <db.blob> [id <id>] set <key>=<value>, ... [where ...] [partition (...)]
+  alter blob <db.blob> add <key>:<type> [optional] [default <value>] | drop <key> | rename <key> to <key> | type <key> <type>
+  delete db <db> | delete blob <db.blob> | delete index <db.blob> <key> | delete <db.blob> [id <id>] [where ...] [partition (...)]
+  delete partition <db.blob> (<key>=<value>, ...) | truncate blob <db.blob>
+  history | !<n> | help | exit
+where:
+  <key> <op> <value> joined by and, or, not and parentheses
+  ops: = != > >= < <= in (...) not in (...) between <a> and <b> is [not] null like ilike regex contains prefix suffix match any (...)
+  keys: <key> or <key>.<key> for object keys
+`
+
+type Shell struct {
+	queryManager queryManagers.QueryManager
+	user         systemModels.User
+	out          io.Writer
+	jsonOutput   bool
+	history      []string
+	historyFile  string
+}
+
+func CreateShell(queryManager queryManagers.QueryManager, user systemModels.User, out io.Writer, jsonOutput bool) *Shell {
+	return &Shell{
+		queryManager: queryManager,
+		user:         user,
+		out:          out,
+		jsonOutput:   jsonOutput,
+		history:      []string{},
+	}
+}
+
+func (s *Shell) LoadHistory(historyFile string) error {
+	s.historyFile = historyFile
+	data, err := os.ReadFile(historyFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			s.history = append(s.history, line)
+		}
+	}
+	return nil
+}
+
+func (s *Shell) Run(in io.Reader, prompt bool) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for {
+		if prompt {
+			_, _ = fmt.Fprint(s.out, Prompt)
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		if !s.Execute(scanner.Text()) {
+			return nil
+		}
+	}
+}
+
+func (s *Shell) Execute(line string) bool {
+	line = strings.TrimSuffix(strings.TrimSpace(line), ";")
+	if line == "" {
+		return true
+	}
+	switch strings.ToLower(line) {
+	case "exit", "quit":
+		return false
+	case "help":
+		_, _ = fmt.Fprint(s.out, helpText)
+		return true
+	case "history":
+		for i, item := range s.history {
+			_, _ = fmt.Fprintf(s.out, "%4d  %s\n", i+1, item)
+		}
+		return true
+	}
+	if strings.HasPrefix(line, "!") {
+		index, err := strconv.Atoi(line[1:])
+		if err != nil || index < 1 || index > len(s.history) {
+			s.printResult(queryModels.QueryResult{ErrorMessage: fmt.Sprintf("history item %s does not exist", line[1:])})
+			return true
+		}
+		line = s.history[index-1]
+		_, _ = fmt.Fprintln(s.out, line)
+	}
+	s.addHistory(line)
+	query, err := Parse(line)
+	if err != nil {
+		s.printResult(queryModels.QueryResult{ErrorMessage: err.Error()})
+		return true
+	}
+	queryResult := s.queryManager.Query(query, s.user)
+	if query.On == queryConstants.OnConnection && queryResult.ErrorMessage == "" {
+		s.user = queryResult.ConnectionUser
+	}
+	s.printResult(queryResult)
+	return true
+}
+
+func (s *Shell) addHistory(line string) {
+	if strings.HasPrefix(strings.ToLower(line), "login") {
+		return
+	}
+	if len(s.history) > 0 && s.history[len(s.history)-1] == line {
+		return
+	}
+	s.history = append(s.history, line)
+	if s.historyFile == "" {
+		return
+	}
+	historyFile, err := os.OpenFile(s.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer historyFile.Close()
+	_, _ = fmt.Fprintln(historyFile, line)
+}
+
+func (s *Shell) printResult(queryResult queryModels.QueryResult) {
+	if s.jsonOutput {
+		_ = json.NewEncoder(s.out).Encode(queryResult)
+		return
+	}
+	if queryResult.ErrorMessage != "" {
+		_, _ = fmt.Fprintf(s.out, "error: %s\n", queryResult.ErrorMessage)
+		return
+	}
+	if queryResult.ConnectionUser.User != "" {
+		_, _ = fmt.Fprintf(s.out, "logged in as %s (%s)\n", queryResult.ConnectionUser.User, queryResult.ConnectionUser.Permission)
+		return
+	}
+	if len(queryResult.Records) == 0 {
+		_, _ = fmt.Fprintln(s.out, "OK")
+		return
+	}
+	s.printTable(queryResult.Records)
+}
+
+func (s *Shell) printTable(records []diskModels.PageRecord) {
+	columns := getColumns(records)
+	writer := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, strings.Join(columns, "\t"))
+	separators := []string{}
+	for _, column := range columns {
+		separators = append(separators, strings.Repeat("-", len(column)))
+	}
+	_, _ = fmt.Fprintln(writer, strings.Join(separators, "\t"))
+	for _, record := range records {
+		values := []string{}
+		for _, column := range columns {
+			values = append(values, formatValue(record[column]))
+		}
+		_, _ = fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+	_ = writer.Flush()
+	rowLabel := "rows"
+	if len(records) == 1 {
+		rowLabel = "row"
+	}
+	_, _ = fmt.Fprintf(s.out, "(%d %s)\n", len(records), rowLabel)
+}
+
+func getColumns(records []diskModels.PageRecord) []string {
+	keySet := map[string]bool{}
+	for _, record := range records {
+		for key := range record {
+			keySet[key] = true
+		}
+	}
+	columns := []string{}
+	for key := range keySet {
+		if key != memoryConstants.IdKey {
+			columns = append(columns, key)
+		}
+	}
+	sort.Strings(columns)
+	if keySet[memoryConstants.IdKey] {
+		columns = append([]string{memoryConstants.IdKey}, columns...)
+	}
+	return columns
+}
+
+func formatValue(value any) string {
+	switch value.(type) {
+	case nil:
+		return "NULL"
+	case string, bool, int, int64, float64:
+		return fmt.Sprintf("%v", value)
+	default:
+		builder := strings.Builder{}
+		encoder := json.NewEncoder(&builder)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return strings.TrimSuffix(builder.String(), "\n")
+	}
+}
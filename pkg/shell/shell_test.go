@@ -0,0 +1,86 @@
+package shell
+
+import (
+	"bytes"
+	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testQueryManager struct {
+	users  []systemModels.User
+	result queryModels.QueryResult
+}
+
+func (tqm *testQueryManager) Query(query queryModels.Query, user systemModels.User) queryModels.QueryResult {
+	tqm.users = append(tqm.users, user)
+	if query.On == queryConstants.OnConnection {
+		return queryModels.QueryResult{ConnectionUser: systemModels.User{User: query.With.UserConnection.User, Permission: "r"}}
+	}
+	return tqm.result
+}
+
+func TestUnit_Run_PrintsRecordsAsTable(t *testing.T) {
+	queryManager := &testQueryManager{result: queryModels.QueryResult{Records: []diskModels.PageRecord{
+		{"_id": "a1", "status": "open", "qty": 2},
+		{"_id": "b2", "status": "closed", "note": nil},
+	}}}
+	out := &bytes.Buffer{}
+	shell := CreateShell(queryManager, systemModels.User{User: "root"}, out, false)
+
+	err := shell.Run(strings.NewReader("get shop.orders\n"), false)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, []string{"_id", "note", "qty", "status"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"a1", "NULL", "2", "open"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"b2", "NULL", "NULL", "closed"}, strings.Fields(lines[3]))
+	assert.Equal(t, "(2 rows)", lines[4])
+}
+
+func TestUnit_Run_PrintsNestedValuesAsJSON(t *testing.T) {
+	queryManager := &testQueryManager{result: queryModels.QueryResult{Records: []diskModels.PageRecord{
+		{"_id": "a1", "key_type": "array<string>", "address": map[string]any{"city": "Paris"}, "tags": []any{"a", "b"}},
+	}}}
+	out := &bytes.Buffer{}
+	shell := CreateShell(queryManager, systemModels.User{User: "root"}, out, false)
+
+	err := shell.Run(strings.NewReader("get shop.events\n"), false)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{"a1", `{"city":"Paris"}`, "array<string>", `["a","b"]`}, strings.Fields(lines[2]))
+}
+
+func TestUnit_Run_PrintsJSONLines(t *testing.T) {
+	queryManager := &testQueryManager{result: queryModels.QueryResult{ErrorMessage: "db shop does not exist"}}
+	out := &bytes.Buffer{}
+	shell := CreateShell(queryManager, systemModels.User{User: "root"}, out, true)
+
+	err := shell.Run(strings.NewReader("get blobs shop\nget nothing here\n"), false)
+
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, `{"connectionUser":{},"errorMessage":"db shop does not exist"}`, lines[0])
+	assert.Contains(t, lines[1], `"errorMessage"`)
+}
+
+func TestUnit_Run_HandlesMetaCommandsAndLogin(t *testing.T) {
+	queryManager := &testQueryManager{}
+	out := &bytes.Buffer{}
+	shell := CreateShell(queryManager, systemModels.User{User: "root"}, out, false)
+
+	err := shell.Run(strings.NewReader("create db shop\nlogin bob secret\n!1\nhistory\nexit\nget dbs\n"), false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"root", "root", "bob"}, []string{queryManager.users[0].User, queryManager.users[1].User, queryManager.users[2].User})
+	assert.Equal(t, 3, len(queryManager.users))
+	assert.Contains(t, out.String(), "logged in as bob (r)")
+	assert.Contains(t, out.String(), "   1  create db shop")
+	assert.NotContains(t, out.String(), "secret")
+}
@@ -15,11 +15,16 @@ const (
 	PermissionSuper     = "*rw" //global permission (non-system and system)
 )
 
-var permissionRankMap = map[string]int{
-	PermissionRead:      0,
-	PermissionReadWrite: 1,
-	PermissionReadSuper: 2,
-	PermissionSuper:     3,
+type permissionScope struct {
+	write  bool
+	system bool
+}
+
+var permissionScopeMap = map[string]permissionScope{
+	PermissionRead:      {},
+	PermissionReadWrite: {write: true},
+	PermissionReadSuper: {system: true},
+	PermissionSuper:     {write: true, system: true},
 }
 
 var sysBlobs = []string{
@@ -39,26 +44,21 @@ func IsSystemName(name string) bool {
 }
 
 func HasRead(permission string) bool {
-	return _hasPermission(permission, PermissionRead)
+	_, ok := permissionScopeMap[permission]
+	return ok
 }
 
 func HasReadWrite(permission string) bool {
-	return _hasPermission(permission, PermissionReadWrite)
+	return permissionScopeMap[permission].write
 }
 
 func HasSuperRead(permission string) bool {
-	return _hasPermission(permission, PermissionReadSuper)
+	return permissionScopeMap[permission].system
 }
 
 func HasSuper(permission string) bool {
-	return _hasPermission(permission, PermissionSuper)
-}
-
-func _hasPermission(permission string, comparePerm string) bool {
-	if rank, ok := permissionRankMap[permission]; ok {
-		return rank >= permissionRankMap[comparePerm]
-	}
-	return false
+	scope := permissionScopeMap[permission]
+	return scope.write && scope.system
 }
 
 func _isSystemBlob(name string) bool {
@@ -8,26 +8,34 @@ import (
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/query/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"github.com/stevekineeve88/nimydb-engine/pkg/system/models"
 	"sort"
+	"sync"
+	"time"
 )
 
 type LogManager interface {
-	AddLog(query queryModels.Query) error
+	AddLog(query queryModels.Query, user systemModels.User) error
 	GetLogs(filterItems []memoryModels.FilterItem) ([]queryModels.Log, error)
 	GetCurrent() queryModels.Log
 }
 
 type logManager struct {
+	m                sync.Locker
 	operationManager memoryManagers.OperationManager
 }
 
 func CreateLogManager(operationManager memoryManagers.OperationManager) LogManager {
 	return &logManager{
+		m:                &sync.Mutex{},
 		operationManager: operationManager,
 	}
 }
 
-func (lm *logManager) AddLog(query queryModels.Query) error {
+func (lm *logManager) AddLog(query queryModels.Query, user systemModels.User) error {
+	lm.m.Lock()
+	defer lm.m.Unlock()
+	timestamp := time.Now().Unix()
 	currentLog := lm.GetCurrent()
 	if currentLog.Version == 0 {
 		hexString, err := lm.convertToHex(query)
@@ -38,6 +46,8 @@ func (lm *logManager) AddLog(query queryModels.Query) error {
 			{
 				"is_current": true,
 				"version":    1,
+				"timestamp":  timestamp,
+				"user":       user.User,
 				"query_hex":  hexString,
 			},
 		})
@@ -57,6 +67,8 @@ func (lm *logManager) AddLog(query queryModels.Query) error {
 		{
 			"is_current": true,
 			"version":    currentLog.Version + 1,
+			"timestamp":  timestamp,
+			"user":       user.User,
 			"query_hex":  hexString,
 		},
 	})
@@ -77,11 +89,7 @@ func (lm *logManager) GetLogs(filterItems []memoryModels.FilterItem) ([]queryMod
 	logs := []queryModels.Log{}
 	for _, record := range records {
 		if query, err := lm.convertToQuery(record["query_hex"].(string)); err == nil {
-			logs = append(logs, queryModels.Log{
-				Id:      record["_id"].(string),
-				Version: record["version"].(int),
-				Query:   query,
-			})
+			logs = append(logs, lm.convertToLog(record, query))
 		}
 	}
 	sort.Slice(logs[:], func(i, j int) bool {
@@ -108,11 +116,7 @@ func (lm *logManager) GetCurrent() queryModels.Log {
 	logs := []queryModels.Log{}
 	for _, record := range records {
 		if query, err := lm.convertToQuery(record["query_hex"].(string)); err == nil {
-			logs = append(logs, queryModels.Log{
-				Id:      record["_id"].(string),
-				Version: record["version"].(int),
-				Query:   query,
-			})
+			logs = append(logs, lm.convertToLog(record, query))
 		}
 	}
 	if len(logs) > 1 {
@@ -145,3 +149,18 @@ func (lm *logManager) convertToQuery(hexString string) (queryModels.Query, error
 	}
 	return query, err
 }
+
+func (lm *logManager) convertToLog(record diskModels.PageRecord, query queryModels.Query) queryModels.Log {
+	log := queryModels.Log{
+		Id:      record["_id"].(string),
+		Version: record["version"].(int),
+		Query:   query,
+	}
+	if timestamp, ok := record["timestamp"].(string); ok {
+		log.Timestamp = timestamp
+	}
+	if user, ok := record["user"].(string); ok {
+		log.User = user
+	}
+	return log
+}
@@ -1,49 +1,137 @@
 package system
 
 import (
+	"errors"
+	"fmt"
 	"github.com/stevekineeve88/nimydb-engine/pkg/disk/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/constants"
 	"github.com/stevekineeve88/nimydb-engine/pkg/memory/managers"
+	"github.com/stevekineeve88/nimydb-engine/pkg/memory/models"
 	"github.com/stevekineeve88/nimydb-engine/pkg/system/constants"
+	"slices"
+	"strings"
 )
 
-func InitDB(operationManager memoryManagers.OperationManager) {
-	_buildDB(operationManager)
-	_buildSysLogs(operationManager)
-	_buildSysUsers(operationManager)
+func InitDB(operationManager memoryManagers.OperationManager) error {
+	if err := _buildDB(operationManager); err != nil {
+		return err
+	}
+	if err := _buildSysLogs(operationManager); err != nil {
+		return err
+	}
+	return _buildSysUsers(operationManager)
 }
 
-func _buildDB(operationManager memoryManagers.OperationManager) {
+func _buildDB(operationManager memoryManagers.OperationManager) error {
 	if operationManager.DBExists(systemConstants.DBSys) {
-		return
-	}
-	if err := operationManager.CreateDB(systemConstants.DBSys); err != nil {
-		panic(err)
+		return nil
 	}
+	return operationManager.CreateDB(systemConstants.DBSys)
 }
 
-func _buildSysLogs(operationManager memoryManagers.OperationManager) {
+func _buildSysLogs(operationManager memoryManagers.OperationManager) error {
 	if operationManager.BlobExists(systemConstants.DBSys, systemConstants.BlobSysLog) {
-		return
+		return _migrateSysLogs(operationManager)
 	}
-	if err := operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysLog, diskModels.Format{
+	return operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysLog, diskModels.Format{
 		"is_current": diskModels.FormatItem{KeyType: memoryConstants.Bool},
 		"version":    diskModels.FormatItem{KeyType: memoryConstants.Int},
+		"timestamp":  diskModels.FormatItem{KeyType: memoryConstants.DateTime},
+		"user":       diskModels.FormatItem{KeyType: memoryConstants.String},
 		"query_hex":  diskModels.FormatItem{KeyType: memoryConstants.String},
-	}, nil); err != nil {
-		panic(err)
+	}, nil)
+}
+
+func _migrateSysLogs(operationManager memoryManagers.OperationManager) error {
+	format, err := operationManager.GetFormat(systemConstants.DBSys, systemConstants.BlobSysLog)
+	if err != nil {
+		return err
+	}
+	addedFormat := diskModels.Format{
+		"timestamp": diskModels.FormatItem{KeyType: memoryConstants.DateTime, Optional: true},
+		"user":      diskModels.FormatItem{KeyType: memoryConstants.String, Optional: true},
+	}
+	for key, formatItem := range addedFormat {
+		if _, ok := format[key]; ok {
+			continue
+		}
+		if err = operationManager.AlterBlob(systemConstants.DBSys, systemConstants.BlobSysLog, diskModels.Alter{
+			Op:       memoryConstants.AlterAdd,
+			Key:      key,
+			KeyType:  formatItem.KeyType,
+			Optional: formatItem.Optional,
+		}); err != nil {
+			return err
+		}
+		if err = operationManager.WaitAlterBlob(systemConstants.DBSys, systemConstants.BlobSysLog); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func _buildSysUsers(operationManager memoryManagers.OperationManager) {
+func _buildSysUsers(operationManager memoryManagers.OperationManager) error {
 	if operationManager.BlobExists(systemConstants.DBSys, systemConstants.BlobSysUser) {
-		return
+		return _migrateSysUsers(operationManager)
 	}
-	if err := operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysUser, diskModels.Format{
-		"user":       diskModels.FormatItem{KeyType: memoryConstants.String},
+	return operationManager.CreateBlob(systemConstants.DBSys, systemConstants.BlobSysUser, diskModels.Format{
+		"user":       diskModels.FormatItem{KeyType: memoryConstants.String, Unique: true},
 		"password":   diskModels.FormatItem{KeyType: memoryConstants.String},
 		"permission": diskModels.FormatItem{KeyType: memoryConstants.String},
-	}, nil); err != nil {
-		panic(err)
+	}, nil)
+}
+
+func _migrateSysUsers(operationManager memoryManagers.OperationManager) error {
+	format, err := operationManager.GetFormat(systemConstants.DBSys, systemConstants.BlobSysUser)
+	if err != nil {
+		return err
+	}
+	if format["user"].Unique {
+		return nil
+	}
+	if err = _checkDuplicateSysUsers(operationManager); err != nil {
+		return err
+	}
+	if err = operationManager.AlterBlob(systemConstants.DBSys, systemConstants.BlobSysUser, diskModels.Alter{
+		Op:  memoryConstants.AlterUnique,
+		Key: "user",
+	}); err != nil {
+		return err
+	}
+	return operationManager.WaitAlterBlob(systemConstants.DBSys, systemConstants.BlobSysUser)
+}
+
+func _checkDuplicateSysUsers(operationManager memoryManagers.OperationManager) error {
+	records, err := operationManager.GetRecords(
+		systemConstants.DBSys,
+		systemConstants.BlobSysUser,
+		[]memoryModels.FilterItem{},
+		memoryModels.SearchPartition{},
+		memoryModels.GetOperationParams{},
+	)
+	if err != nil {
+		return err
+	}
+	recordIds := make(map[string][]string)
+	for _, record := range records {
+		user := fmt.Sprintf("%v", record["user"])
+		recordIds[user] = append(recordIds[user], fmt.Sprintf("%v", record[memoryConstants.IdKey]))
+	}
+	duplicates := []string{}
+	for user, ids := range recordIds {
+		if len(ids) < 2 {
+			continue
+		}
+		slices.Sort(ids)
+		duplicates = append(duplicates, fmt.Sprintf("%s (records %s)", user, strings.Join(ids, ", ")))
+	}
+	if len(duplicates) == 0 {
+		return nil
 	}
+	slices.Sort(duplicates)
+	return errors.New(fmt.Sprintf(
+		"%s has duplicate users, keep one record per user before starting: %s",
+		systemConstants.BlobSysUser,
+		strings.Join(duplicates, "; "),
+	))
 }